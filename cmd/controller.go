@@ -21,6 +21,7 @@ import (
 	"github.com/hellofresh/kangal/pkg/kubernetes"
 	clientSet "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned"
 	informers "github.com/hellofresh/kangal/pkg/kubernetes/generated/informers/externalversions"
+	"github.com/hellofresh/kangal/pkg/report"
 )
 
 // reconcileDistribution defines the bucket boundaries for the histogram of reconcile latency metric
@@ -49,6 +50,18 @@ func NewControllerCmd() *cobra.Command {
 			if err := envconfig.Process("", &cfg); err != nil {
 				return fmt.Errorf("could not load config from env: %w", err)
 			}
+			if err := cfg.Compare.Validate(); err != nil {
+				return fmt.Errorf("invalid compare tolerances: %w", err)
+			}
+			if err := cfg.MetricsExport.Validate(); err != nil {
+				return fmt.Errorf("invalid metrics export: %w", err)
+			}
+			if cfg.Notifications.MaxAttempts < 1 {
+				return fmt.Errorf("invalid notifications config: NOTIFY_MAX_ATTEMPTS should be at least 1")
+			}
+			if cfg.Notifications.DeliveryBudget < cfg.Notifications.Timeout {
+				return fmt.Errorf("invalid notifications config: NOTIFY_DELIVERY_BUDGET should be at least NOTIFY_TIMEOUT")
+			}
 
 			// set some command line option to controller config
 			cfg, err := populateCfgFromOpts(cfg, opts)
@@ -99,7 +112,7 @@ func NewControllerCmd() *cobra.Command {
 			kubeInformerFactory := kubeInformers.NewSharedInformerFactory(kubeClient, time.Second*30)
 			kangalInformerFactory := informers.NewSharedInformerFactory(kangalClient, time.Second*30)
 
-			return controller.Run(cfg, controller.Runner{
+			runner := controller.Runner{
 				Logger:         logger,
 				Exporter:       pe,
 				KubeClient:     kubeClient,
@@ -107,7 +120,26 @@ func NewControllerCmd() *cobra.Command {
 				StatsReporter:  statsReporter,
 				KubeInformer:   kubeInformerFactory,
 				KangalInformer: kangalInformerFactory,
-			})
+			}
+
+			// report storage is needed to archive logs, to record history and to evaluate load test thresholds
+			if cfg.ArchiveLogs || cfg.RecordHistory || cfg.Report.Enabled() {
+				reportStore, err := report.NewStore(cfg.Report)
+				if err != nil {
+					return fmt.Errorf("building reportingClient client: %w", err)
+				}
+
+				runner.ReportReader = report.NewReader(reportStore)
+				if cfg.RecordHistory {
+					runner.History = report.NewHistory(reportStore)
+					runner.Baselines = report.NewBaselines(reportStore, cfg.Compare)
+				}
+				if cfg.ArchiveLogs {
+					runner.Archiver = report.NewArchiver(reportStore)
+				}
+			}
+
+			return controller.Run(cfg, runner)
 		},
 	}
 
@@ -144,6 +176,14 @@ func populateCfgFromOpts(cfg controller.Config, opts *controllerCmdOptions) (con
 	if err != nil {
 		return controller.Config{}, fmt.Errorf("failed to convert node selectors: %w", err)
 	}
+	cfg.Guardrails, err = controller.LoadGuardrails(cfg)
+	if err != nil {
+		return controller.Config{}, fmt.Errorf("failed to load namespace guardrails: %w", err)
+	}
+	cfg.Notifications.Webhooks, err = controller.LoadWebhooks(cfg.Notifications)
+	if err != nil {
+		return controller.Config{}, fmt.Errorf("failed to load notification webhooks: %w", err)
+	}
 	return cfg, nil
 }
 
@@ -13,7 +13,9 @@ import (
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 	kubernetesClient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 
 	"github.com/hellofresh/kangal/pkg/core/observability"
 	"github.com/hellofresh/kangal/pkg/kubernetes"
@@ -43,6 +45,9 @@ func NewProxyCmd() *cobra.Command {
 			if err := envconfig.Process("", &cfg); err != nil {
 				return fmt.Errorf("could not load config from env: %w", err)
 			}
+			if err := cfg.Compare.Validate(); err != nil {
+				return fmt.Errorf("invalid compare tolerances: %w", err)
+			}
 
 			logger, _, err := observability.NewLogger(cfg.Logger)
 			if err != nil {
@@ -54,24 +59,48 @@ func NewProxyCmd() *cobra.Command {
 				return fmt.Errorf("could not build prometheus exporter: %w", err)
 			}
 
-			k8sConfig, err := kubernetes.BuildClientConfig(opts.masterURL, opts.kubeConfig, cfg.KubeClientTimeout)
-			if err != nil {
-				return fmt.Errorf("building config from flags: %w", err)
-			}
-
-			kangalClientSet, err := loadTestV1.NewForConfig(k8sConfig)
-			if err != nil {
-				return fmt.Errorf("building kangal clientset: %w", err)
-			}
-
-			kubeClientSet, err := kubernetesClient.NewForConfig(k8sConfig)
-			if err != nil {
-				return fmt.Errorf("building kubernetes clientset: %w", err)
+			var clusters proxy.Clusters
+			if cfg.ClustersConfig == "" {
+				k8sConfig, err := kubernetes.BuildClientConfig(opts.masterURL, opts.kubeConfig, cfg.KubeClientTimeout)
+				if err != nil {
+					return fmt.Errorf("building config from flags: %w", err)
+				}
+
+				kubeClient, err := newKubeClient(k8sConfig, logger)
+				if err != nil {
+					return err
+				}
+				clusters = proxy.SingleCluster(kubeClient, opts.maxLoadTestsRun)
+			} else {
+				clustersConfig, err := proxy.ReadClustersConfig(cfg.ClustersConfig, opts.maxLoadTestsRun)
+				if err != nil {
+					return err
+				}
+
+				for _, clusterConfig := range clustersConfig {
+					kubeConfig := clusterConfig.KubeConfig
+					if kubeConfig == "" {
+						kubeConfig = opts.kubeConfig
+					}
+
+					k8sConfig, err := kubernetes.BuildClientConfigForContext(kubeConfig, clusterConfig.Context, cfg.KubeClientTimeout)
+					if err != nil {
+						return fmt.Errorf("building config for cluster %q: %w", clusterConfig.Name, err)
+					}
+
+					kubeClient, err := newKubeClient(k8sConfig, logger.With(zap.String("cluster", clusterConfig.Name)))
+					if err != nil {
+						return fmt.Errorf("building clients for cluster %q: %w", clusterConfig.Name, err)
+					}
+
+					clusters = append(clusters, &proxy.Cluster{
+						Name:       clusterConfig.Name,
+						Capacity:   clusterConfig.Capacity,
+						KubeClient: kubeClient,
+					})
+				}
 			}
 
-			loadTestClient := kangalClientSet.LoadTests()
-			kubeClient := kubernetes.NewClient(loadTestClient, kubeClientSet, logger)
-
 			provider := metric.NewMeterProvider(metric.WithReader(pe), metric.WithResource(
 				resource.NewSchemaless(semconv.ServiceNameKey.String("kangal-proxy"))),
 				metric.WithView(metric.NewView(
@@ -95,12 +124,12 @@ func NewProxyCmd() *cobra.Command {
 
 			otel.SetMeterProvider(provider)
 
-			statsReporter, err := proxy.NewMetricsReporter(provider.Meter("proxy"), kubeClient)
+			statsReporter, err := proxy.NewMetricsReporter(provider.Meter("proxy"), clusters)
 			if err != nil {
 				return fmt.Errorf("error getting stats client:  %w", err)
 			}
 
-			err = report.InitObjectStorageClient(cfg.Report)
+			reportStore, err := report.NewStore(cfg.Report)
 			if err != nil {
 				return fmt.Errorf("building reportingClient client: %w", err)
 			}
@@ -110,7 +139,8 @@ func NewProxyCmd() *cobra.Command {
 
 			return proxy.RunServer(cfg, proxy.Runner{
 				Exporter:      pe,
-				KubeClient:    kubeClient,
+				Clusters:      clusters,
+				ReportStore:   reportStore,
 				Logger:        logger,
 				StatsReporter: statsReporter,
 			})
@@ -123,3 +153,17 @@ func NewProxyCmd() *cobra.Command {
 	flags.IntVar(&opts.maxLoadTestsRun, "max-load-tests", 10, "The maximum amount of load tests to run simultaneously.")
 	return cmd
 }
+
+func newKubeClient(k8sConfig *rest.Config, logger *zap.Logger) (*kubernetes.Client, error) {
+	kangalClientSet, err := loadTestV1.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building kangal clientset: %w", err)
+	}
+
+	kubeClientSet, err := kubernetesClient.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building kubernetes clientset: %w", err)
+	}
+
+	return kubernetes.NewClient(kangalClientSet.LoadTests(), kubeClientSet, logger), nil
+}
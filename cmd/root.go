@@ -14,6 +14,7 @@ func NewRootCmd(version string) *cobra.Command {
 
 	cmd.AddCommand(NewProxyCmd())
 	cmd.AddCommand(NewControllerCmd())
+	cmd.AddCommand(NewWebhookCmd())
 
 	return cmd
 }
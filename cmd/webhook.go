@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	kubernetesClient "k8s.io/client-go/kubernetes"
+
+	"github.com/hellofresh/kangal/pkg/core/observability"
+	"github.com/hellofresh/kangal/pkg/kubernetes"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/webhook"
+)
+
+type webhookCmdOpts struct {
+	kubeConfig      string
+	masterURL       string
+	maxLoadTestsRun int
+}
+
+// NewWebhookCmd creates a new webhook command
+func NewWebhookCmd() *cobra.Command {
+	opts := &webhookCmdOpts{}
+
+	cmd := &cobra.Command{
+		Use:     "webhook",
+		Short:   "Run admission webhook validating and defaulting LoadTest resources",
+		Aliases: []string{"w"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cfg webhook.Config
+			if err := envconfig.Process("", &cfg); err != nil {
+				return fmt.Errorf("could not load config from env: %w", err)
+			}
+
+			logger, _, err := observability.NewLogger(cfg.Logger)
+			if err != nil {
+				return fmt.Errorf("could not build logger instance: %w", err)
+			}
+
+			k8sConfig, err := kubernetes.BuildClientConfig(opts.masterURL, opts.kubeConfig, cfg.KubeClientTimeout)
+			if err != nil {
+				return fmt.Errorf("building config from flags: %w", err)
+			}
+
+			kangalClientSet, err := loadTestV1.NewForConfig(k8sConfig)
+			if err != nil {
+				return fmt.Errorf("building kangal clientset: %w", err)
+			}
+
+			kubeClientSet, err := kubernetesClient.NewForConfig(k8sConfig)
+			if err != nil {
+				return fmt.Errorf("building kubernetes clientset: %w", err)
+			}
+
+			kubeClient := kubernetes.NewClient(kangalClientSet.LoadTests(), kubeClientSet, logger)
+
+			if cfg.ConversionService != "" {
+				caBundle, err := os.ReadFile(cfg.TLSCAFile)
+				if err != nil {
+					return fmt.Errorf("could not read webhook CA: %w", err)
+				}
+
+				dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+				if err != nil {
+					return fmt.Errorf("building dynamic client: %w", err)
+				}
+
+				err = webhook.ConfigureConversion(context.Background(), dynamicClient, webhook.ConversionConfig{
+					Service:   cfg.ConversionService,
+					Namespace: cfg.ConversionNamespace,
+					Port:      cfg.ConversionPort,
+					CABundle:  caBundle,
+				})
+				if err != nil {
+					return fmt.Errorf("could not configure LoadTest conversion: %w", err)
+				}
+				logger.Info("Switched LoadTest CRD to webhook conversion")
+			}
+
+			cfg.MaxLoadTestsRun = opts.maxLoadTestsRun
+			cfg.MasterURL = opts.masterURL
+
+			return webhook.RunServer(cfg, webhook.Runner{
+				KubeClient: kubeClient,
+				Logger:     logger,
+			})
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.kubeConfig, "kubeconfig", "", "absolute path to the kubernetes config")
+	flags.StringVar(&opts.masterURL, "master-url", "", "The address of the Kubernetes API server. Overrides any value in kubeConfig. Only required if out-of-cluster.")
+	flags.IntVar(&opts.maxLoadTestsRun, "max-load-tests", 10, "The maximum amount of load tests to run simultaneously.")
+	return cmd
+}
@@ -30,7 +30,7 @@ TEMP="$SCRIPT_ROOT/_tmp"
 /bin/bash "${CODEGEN_PKG}"/generate-groups.sh "deepcopy,client,informer,lister" \
   "github.com/hellofresh/kangal/pkg/kubernetes/generated" \
   "github.com/hellofresh/kangal/pkg/kubernetes/apis" \
-  loadtest:v1 \
+  loadtest:v1,v2 \
   --output-base "$TEMP" \
   --go-header-file "$SCRIPT_ROOT/hack/boilerplate.go.txt" \
 
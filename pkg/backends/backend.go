@@ -7,6 +7,7 @@ import (
 	kubeCoreV1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	clientSetV "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned"
@@ -68,6 +69,27 @@ type BackendSetPodTolerations interface {
 	SetPodTolerations([]kubeCoreV1.Toleration)
 }
 
+// BackendSetSchedulingPolicy interface can be implemented by backend to receive the allowed LoadTest scheduling constraints
+// This method is called by both commands, Proxy and Webhook
+type BackendSetSchedulingPolicy interface {
+	// SetSchedulingPolicy gives backend the policy LoadTest scheduling constraints are validated against
+	SetSchedulingPolicy(SchedulingPolicy)
+}
+
+// BackendSetRuntimeLimits interface can be implemented by backend to receive LoadTest runtime limits
+// This method is called only by command Controller
+type BackendSetRuntimeLimits interface {
+	// SetRuntimeLimits gives backend the limits used for jobs and pods active deadline
+	SetRuntimeLimits(RuntimeLimits)
+}
+
+// BackendSetMetricsExport interface can be implemented by backend to push live LoadTest metrics
+// This method is called only by command Controller
+type BackendSetMetricsExport interface {
+	// SetMetricsExport gives backend the destination load generators push metrics to
+	SetMetricsExport(MetricsExport)
+}
+
 // BackendSetKubeClientSet interface can be implemented by backend to receive an kubeClientSet
 // This method is called only by command Controller
 type BackendSetKubeClientSet interface {
@@ -88,3 +110,10 @@ type BackendSetNamespaceLister interface {
 	// SetNamespaceLister gives backend a namespaceLister instance
 	SetNamespaceLister(coreListersV1.NamespaceLister)
 }
+
+// BackendSetEventRecorder interface can be implemented by backend to receive an eventRecorder
+// This method is called only by command Controller
+type BackendSetEventRecorder interface {
+	// SetEventRecorder gives backend an eventRecorder instance to record events on LoadTest resources
+	SetEventRecorder(record.EventRecorder)
+}
@@ -0,0 +1,51 @@
+package backends
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// maxCallbacks is the maximum number of callbacks of a LoadTest
+const maxCallbacks = 10
+
+var (
+	// ErrCallbackInvalid is returned when a LoadTest callback is not an absolute http or https URL
+	ErrCallbackInvalid = errors.New("callback should be an absolute http or https URL")
+	// ErrTooManyCallbacks is returned when a LoadTest has more callbacks than allowed
+	ErrTooManyCallbacks = fmt.Errorf("loadtest can have at most %d callbacks", maxCallbacks)
+)
+
+// ValidateCallbacks checks LoadTest callbacks can be notified once the loadtest has completed
+func ValidateCallbacks(callbacks []string) error {
+	if len(callbacks) > maxCallbacks {
+		return ErrTooManyCallbacks
+	}
+
+	for _, callback := range callbacks {
+		if err := ValidateCallbackURL(callback); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateCallbackURL checks the callback is an absolute http or https URL
+func ValidateCallbackURL(callback string) error {
+	u, err := url.Parse(callback)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w, got %q", ErrCallbackInvalid, callback)
+	}
+	return nil
+}
+
+// CallbackSecret returns the key signing notifications of a LoadTest callback. It is derived from the secret shared
+// by the proxy and the controller, so each callback receiver gets its own key and can not sign notifications of others
+func CallbackSecret(secret, loadTestName, callback string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(loadTestName + "\n" + callback))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package backends_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+)
+
+func TestValidateCallbacks(t *testing.T) {
+	tooMany := make([]string, 11)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("https://ci.example.com/hooks/%d", i)
+	}
+
+	for _, tt := range []struct {
+		name        string
+		callbacks   []string
+		expectedErr error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:      "valid",
+			callbacks: []string{"https://ci.example.com/hooks/kangal", "http://chat.local/notify?channel=loadtests"},
+		},
+		{
+			name:        "relative url",
+			callbacks:   []string{"/hooks/kangal"},
+			expectedErr: backends.ErrCallbackInvalid,
+		},
+		{
+			name:        "unsupported scheme",
+			callbacks:   []string{"ftp://ci.example.com/hooks"},
+			expectedErr: backends.ErrCallbackInvalid,
+		},
+		{
+			name:        "too many",
+			callbacks:   tooMany,
+			expectedErr: backends.ErrTooManyCallbacks,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, backends.ValidateCallbacks(tt.callbacks), tt.expectedErr)
+		})
+	}
+}
+
+func TestCallbackSecret(t *testing.T) {
+	secret := backends.CallbackSecret("shared-secret", "loadtest-name", "https://ci.example.com/hooks/kangal")
+	assert.Len(t, secret, 64)
+	assert.Equal(t, secret, backends.CallbackSecret("shared-secret", "loadtest-name", "https://ci.example.com/hooks/kangal"))
+	assert.NotEqual(t, secret, backends.CallbackSecret("shared-secret", "loadtest-name", "https://ci.example.com/hooks/other"))
+	assert.NotEqual(t, secret, backends.CallbackSecret("shared-secret", "loadtest-other", "https://ci.example.com/hooks/kangal"))
+}
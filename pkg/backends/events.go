@@ -0,0 +1,33 @@
+package backends
+
+import (
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	// EventReasonCreateFailed is recorded when a loadtest resource could not be created
+	EventReasonCreateFailed = "CreateFailed"
+	// EventReasonTimeout is recorded when a loadtest did not make progress in time
+	EventReasonTimeout = "Timeout"
+)
+
+// RecordCreateFailed records a warning event on the loadtest telling which resource could not be created.
+// Nothing is recorded when backend has not received an event recorder
+func RecordCreateFailed(recorder record.EventRecorder, loadTest *loadTestV1.LoadTest, resource string, err error) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(loadTest, coreV1.EventTypeWarning, EventReasonCreateFailed, "Failed to create %s in namespace %s: %v", resource, loadTest.Status.Namespace, err)
+}
+
+// RecordTimeout records a warning event on the loadtest telling it did not make progress in time.
+// Nothing is recorded when backend has not received an event recorder
+func RecordTimeout(recorder record.EventRecorder, loadTest *loadTestV1.LoadTest, message string) {
+	if recorder == nil {
+		return
+	}
+	recorder.Event(loadTest, coreV1.EventTypeWarning, EventReasonTimeout, message)
+}
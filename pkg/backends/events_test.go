@@ -0,0 +1,39 @@
+package backends_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestRecordCreateFailed(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Status:     loadTestV1.LoadTestStatus{Namespace: "loadtest-namespace"},
+	}
+
+	recorder := record.NewFakeRecorder(1)
+	backends.RecordCreateFailed(recorder, loadTest, "job loadtest-master", errors.New("quota exceeded"))
+
+	assert.Equal(t, "Warning CreateFailed Failed to create job loadtest-master in namespace loadtest-namespace: quota exceeded", <-recorder.Events)
+
+	// backends without event recorder must not panic
+	backends.RecordCreateFailed(nil, loadTest, "job loadtest-master", errors.New("quota exceeded"))
+}
+
+func TestRecordTimeout(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}
+
+	recorder := record.NewFakeRecorder(1)
+	backends.RecordTimeout(recorder, loadTest, "Pod loadtest-worker-000 is not running after 30s")
+
+	assert.Equal(t, "Warning Timeout Pod loadtest-worker-000 is not running after 30s", <-recorder.Events)
+
+	backends.RecordTimeout(nil, loadTest, "Pod loadtest-worker-000 is not running after 30s")
+}
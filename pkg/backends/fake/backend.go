@@ -10,6 +10,7 @@ import (
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
@@ -27,6 +28,7 @@ func init() {
 // Backend is the Fake implementation of backend interface
 type Backend struct {
 	kubeClient kubernetes.Interface
+	recorder   record.EventRecorder
 	logger     *zap.Logger
 	config     loadTestV1.ImageDetails
 }
@@ -44,6 +46,11 @@ func (b *Backend) SetDefaults() {
 	}
 }
 
+// SetEventRecorder receives a copy of eventRecorder
+func (b *Backend) SetEventRecorder(recorder record.EventRecorder) {
+	b.recorder = recorder
+}
+
 // SetLogger receives a copy of logger
 func (b *Backend) SetLogger(logger *zap.Logger) {
 	b.logger = logger
@@ -78,16 +85,20 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, _ stri
 		return err
 	}
 	// Check that we created master job
-	_, err = b.kubeClient.BatchV1().Jobs(namespace.GetName()).Get(ctx, "loadtest-master", metaV1.GetOptions{})
+	_, err = b.kubeClient.BatchV1().Jobs(namespace.GetName()).Get(ctx, backends.ResourceName(loadTest, "loadtest-master"), metaV1.GetOptions{})
 	if k8sAPIErrors.IsNotFound(err) {
-		_, err = b.kubeClient.BatchV1().Jobs(namespace.GetName()).Create(ctx, b.newMasterJob(loadTest), metaV1.CreateOptions{})
+		job := b.newMasterJob(loadTest)
+		_, err = b.kubeClient.BatchV1().Jobs(namespace.GetName()).Create(ctx, job, metaV1.CreateOptions{})
+		if err != nil {
+			backends.RecordCreateFailed(b.recorder, &loadTest, "job "+job.GetName(), err)
+		}
 		return err
 	}
 	return err
 }
 
 // SyncStatus check the Fake resources and calculate the current status of the LoadTest from them
-func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
 	// Get the Namespace resource
 	namespace, err := b.kubeClient.CoreV1().Namespaces().Get(ctx, loadTestStatus.Namespace, metaV1.GetOptions{})
 	// The LoadTest resource may no longer exist, in which case we stop
@@ -108,7 +119,7 @@ func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTes
 		return nil
 	}
 
-	job, err := b.kubeClient.BatchV1().Jobs(namespace.GetName()).Get(ctx, "loadtest-master", metaV1.GetOptions{})
+	job, err := b.kubeClient.BatchV1().Jobs(namespace.GetName()).Get(ctx, backends.ResourceName(loadTest, "loadtest-master"), metaV1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -118,6 +129,14 @@ func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTes
 	loadTestStatus.Phase = determineLoadTestPhaseFromJob(job.Status)
 	loadTestStatus.JobStatus = job.Status
 
+	pods, err := b.kubeClient.CoreV1().Pods(namespace.GetName()).List(ctx, metaV1.ListOptions{
+		LabelSelector: backends.LoadTestSelector(loadTest, ""),
+	})
+	if err != nil {
+		return err
+	}
+
+	backends.UpdateLoadTestStatusDetails(loadTestStatus, pods.Items)
 	return nil
 }
 
@@ -133,20 +152,18 @@ func (b *Backend) newMasterJob(loadTest loadTestV1.LoadTest) *batchV1.Job {
 	// to simulate load test job. Please don't use Fake provider in production.
 	return &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name: "loadtest-master",
-			Labels: map[string]string{
+			Name: backends.ResourceName(loadTest, "loadtest-master"),
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				"app": "loadtest-master",
-			},
-			OwnerReferences: []metaV1.OwnerReference{
-				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
-			},
+			}),
+			OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
 		},
 		Spec: batchV1.JobSpec{
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
+					Labels: backends.LoadTestLabels(loadTest, map[string]string{
 						"app": "loadtest-master",
-					},
+					}),
 				},
 				Spec: coreV1.PodSpec{
 					RestartPolicy: "Never",
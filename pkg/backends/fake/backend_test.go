@@ -28,6 +28,7 @@ func (e *StatusError) Status() metaV1.Status {
 
 func TestSync(t *testing.T) {
 	lt := loadTestV1.LoadTest{}
+	lt.Name = "test-namespace"
 	lt.Status.Namespace = "test-namespace"
 
 	t.Run("namespace not found", func(t *testing.T) {
@@ -93,6 +94,7 @@ func TestSync(t *testing.T) {
 
 func TestSyncStatus(t *testing.T) {
 	lt := loadTestV1.LoadTest{}
+	lt.Name = "test-namespace"
 	lt.Status.Namespace = "test-namespace"
 
 	t.Run("namespace and job already exists, load test is starting", func(t *testing.T) {
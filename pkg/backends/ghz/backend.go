@@ -10,6 +10,7 @@ import (
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
@@ -20,6 +21,8 @@ var (
 	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
 	// ErrRequireTestFile the TestFile filed is required to not be an empty string
 	ErrRequireTestFile = errors.New("LoadTest TestFile is required")
+	// ErrMasterResourcesNotSupported backend runs worker pods only, so master resources can not be set
+	ErrMasterResourcesNotSupported = errors.New("LoadTest MasterResources are not supported, use WorkerResources")
 )
 
 func init() {
@@ -30,14 +33,19 @@ func init() {
 type Backend struct {
 	logger         *zap.Logger
 	kubeClientSet  kubernetes.Interface
+	recorder       record.EventRecorder
 	config         *Config
 	podAnnotations map[string]string
 	nodeSelector   map[string]string
 	tolerations    []coreV1.Toleration
+	metricsExport  backends.MetricsExport
 
 	// defined on SetDefaults
-	image     loadTestV1.ImageDetails
-	resources backends.Resources
+	image            loadTestV1.ImageDetails
+	resources        backends.Resources
+	resourceBounds   backends.ResourceBounds
+	schedulingPolicy backends.SchedulingPolicy
+	runtimeLimits    backends.RuntimeLimits
 }
 
 // Type returns backend type name
@@ -64,6 +72,13 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.MemoryLimits,
 		MemoryRequests: b.config.MemoryRequests,
 	}
+
+	b.resourceBounds = backends.ResourceBounds{
+		MinCPU:    b.config.MinCPU,
+		MaxCPU:    b.config.MaxCPU,
+		MinMemory: b.config.MinMemory,
+		MaxMemory: b.config.MaxMemory,
+	}
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -76,6 +91,11 @@ func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
 	b.kubeClientSet = kubeClientSet
 }
 
+// SetEventRecorder receives a copy of eventRecorder
+func (b *Backend) SetEventRecorder(recorder record.EventRecorder) {
+	b.recorder = recorder
+}
+
 // SetLogger receives a copy of logger
 func (b *Backend) SetLogger(logger *zap.Logger) {
 	b.logger = logger
@@ -91,6 +111,21 @@ func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.tolerations = tolerations
 }
 
+// SetSchedulingPolicy receives a copy of scheduling policy
+func (b *Backend) SetSchedulingPolicy(policy backends.SchedulingPolicy) {
+	b.schedulingPolicy = policy
+}
+
+// SetRuntimeLimits receives a copy of runtime limits
+func (b *Backend) SetRuntimeLimits(limits backends.RuntimeLimits) {
+	b.runtimeLimits = limits
+}
+
+// SetMetricsExport receives a copy of metrics export
+func (b *Backend) SetMetricsExport(export backends.MetricsExport) {
+	b.metricsExport = export
+}
+
 // TransformLoadTestSpec use given spec to validate and return a new one or error
 func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	if nil == spec.DistributedPods {
@@ -105,6 +140,30 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireTestFile
 	}
 
+	if spec.MasterResources != nil {
+		return ErrMasterResourcesNotSupported
+	}
+
+	if err := backends.ValidateResources(b.resources, spec.WorkerResources, b.resourceBounds); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateScheduling(spec.Scheduling, b.schedulingPolicy); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateThresholds(spec.Thresholds); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateSecretEnvVars(spec.SecretEnvVars); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateCallbacks(spec.Callbacks); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig.Image == "" || spec.MasterConfig.Tag == "" {
 		spec.MasterConfig.Image = b.image.Image
 		spec.MasterConfig.Tag = b.image.Tag
@@ -119,7 +178,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		BatchV1().
 		Jobs(loadTest.Status.Namespace).
 		List(ctx, metaV1.ListOptions{
-			FieldSelector: fmt.Sprintf("metadata.name=%s", loadTestJobName),
+			FieldSelector: fmt.Sprintf("metadata.name=%s", backends.ResourceName(loadTest, loadTestJobName)),
 		})
 	if err != nil {
 		b.logger.Error("Error on listing jobs", zap.Error(err))
@@ -137,7 +196,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 	)
 
 	// Create testfile ConfigMap
-	tfCfgMap, err := NewFileConfigMap(loadTestFileConfigMapName, configFileName, loadTest.Spec.TestFile)
+	tfCfgMap, err := NewFileConfigMap(backends.ResourceName(loadTest, loadTestFileConfigMapName), configFileName, loadTest.Spec.TestFile)
 	if err != nil {
 		b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
 		return err
@@ -146,7 +205,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 	// Prepare testdata ConfigMap
 	if len(loadTest.Spec.TestData) != 0 {
-		tdCfgMap, err = NewFileConfigMap(loadTestDataConfigMapName, testdataFileName, loadTest.Spec.TestData)
+		tdCfgMap, err = NewFileConfigMap(backends.ResourceName(loadTest, loadTestDataConfigMapName), testdataFileName, loadTest.Spec.TestData)
 		if err != nil {
 			b.logger.Error("Error creating testdata configmap resource", zap.Error(err))
 			return err
@@ -156,12 +215,31 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 	// Create testfile and testdata configmaps
 	for _, cfg := range configMaps {
+		cfg.Labels = backends.LoadTestLabels(loadTest, nil)
+		cfg.OwnerReferences = backends.LoadTestOwnerReferences(loadTest)
 		_, err = b.kubeClientSet.
 			CoreV1().
 			ConfigMaps(loadTest.Status.Namespace).
 			Create(ctx, cfg, metaV1.CreateOptions{})
 		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 			b.logger.Error("Error creating configmap", zap.String("configmap", cfg.GetName()), zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "configmap "+cfg.GetName(), err)
+			return err
+		}
+	}
+
+	if b.metricsExport.Enabled() {
+		collectorConfigMap, err := b.metricsExport.NewLogMetricsCollectorConfigMap(loadTest, metricsReceivers(), metricsCounts())
+		if err != nil {
+			return err
+		}
+		_, err = b.kubeClientSet.
+			CoreV1().
+			ConfigMaps(loadTest.Status.Namespace).
+			Create(ctx, collectorConfigMap, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			b.logger.Error("Error on creating metrics collector configmap", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "configmap "+collectorConfigMap.GetName(), err)
 			return err
 		}
 	}
@@ -182,12 +260,16 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 	// Create Job
 	job := b.NewJob(loadTest, volumes, mounts, reportURL)
+	if b.metricsExport.Enabled() {
+		addMetricsExport(job, b.metricsExport, loadTest)
+	}
 	_, err = b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTest.Status.Namespace).
 		Create(ctx, job, metaV1.CreateOptions{})
 	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 		b.logger.Error("Error on creating master job", zap.Error(err))
+		backends.RecordCreateFailed(b.recorder, &loadTest, "job "+job.GetName(), err)
 		return err
 	}
 
@@ -195,7 +277,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 }
 
 // SyncStatus checks ghz resources and updates the status of the LoadTest resource
-func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
 	if loadTestStatus.Phase == "" {
 		loadTestStatus.Phase = loadTestV1.LoadTestCreating
 		return nil
@@ -208,12 +290,24 @@ func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTes
 	job, err := b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTestStatus.Namespace).
-		Get(ctx, loadTestJobName, metaV1.GetOptions{})
+		Get(ctx, backends.ResourceName(loadTest, loadTestJobName), metaV1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
 	loadTestStatus.Phase = determineLoadTestStatusFromJobs(job)
 	loadTestStatus.JobStatus = job.Status
+
+	pods, err := b.kubeClientSet.
+		CoreV1().
+		Pods(loadTestStatus.Namespace).
+		List(ctx, metaV1.ListOptions{
+			LabelSelector: backends.LoadTestSelector(loadTest, ""),
+		})
+	if err != nil {
+		return err
+	}
+
+	backends.UpdateLoadTestStatusDetails(loadTestStatus, pods.Items)
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -97,7 +98,7 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, loadTestV1.LoadTestCreating, loadTest.Status.Phase)
 
 	// Simulate that job finished successfully
-	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, backends.ResourceName(loadTest, loadTestJobName), metaV1.GetOptions{})
 	require.NoError(t, err, "Error when getting jobs")
 	job.Status.Succeeded = 1
 	_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metaV1.UpdateOptions{})
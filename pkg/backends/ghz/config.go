@@ -8,4 +8,8 @@ type Config struct {
 	CPURequests    string `envconfig:"GHZ_CPU_REQUESTS"`
 	MemoryLimits   string `envconfig:"GHZ_MEMORY_LIMITS"`
 	MemoryRequests string `envconfig:"GHZ_MEMORY_REQUESTS"`
+	MinCPU         string `envconfig:"GHZ_MIN_CPU"`
+	MaxCPU         string `envconfig:"GHZ_MAX_CPU"`
+	MinMemory      string `envconfig:"GHZ_MIN_MEMORY"`
+	MaxMemory      string `envconfig:"GHZ_MAX_MEMORY"`
 }
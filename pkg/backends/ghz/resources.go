@@ -31,6 +31,28 @@ var defaultArgs = []string{
 	"--format=html",
 }
 
+// envArgs maps load test env vars to ghz flags overriding the config file
+var envArgs = []struct {
+	envVar string
+	flag   string
+}{
+	{envVar: "GHZ_CONCURRENCY", flag: "--concurrency"},
+	{envVar: "GHZ_TOTAL_REQUESTS", flag: "--total"},
+	{envVar: "GHZ_RPS", flag: "--rps"},
+}
+
+func buildArgs(envVars map[string]string) []string {
+	args := make([]string, len(defaultArgs))
+	copy(args, defaultArgs)
+
+	for _, a := range envArgs {
+		if value, ok := envVars[a.envVar]; ok && value != "" {
+			args = append(args, fmt.Sprintf("%s=%s", a.flag, value))
+		}
+	}
+	return args
+}
+
 // NewJob creates a new job that runs ghz
 func (b *Backend) NewJob(
 	loadTest loadTestV1.LoadTest,
@@ -61,24 +83,25 @@ func (b *Backend) NewJob(
 
 	backoffLimit := int32(0)
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name:      loadTestJobName,
+			Name:      backends.ResourceName(loadTest, loadTestJobName),
 			Namespace: loadTest.Status.Namespace,
-			Labels: map[string]string{
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				"name": loadTestJobName,
-			},
+			}),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		Spec: batchV1.JobSpec{
-			Parallelism:  loadTest.Spec.DistributedPods,
-			Completions:  loadTest.Spec.DistributedPods,
-			BackoffLimit: &backoffLimit,
+			ActiveDeadlineSeconds: b.runtimeLimits.ActiveDeadlineSeconds(loadTest.Spec),
+			Parallelism:           loadTest.Spec.DistributedPods,
+			Completions:           loadTest.Spec.DistributedPods,
+			BackoffLimit:          &backoffLimit,
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
+					Labels: backends.LoadTestLabels(loadTest, map[string]string{
 						"name": loadTestJobName,
-					},
+					}),
 					Annotations: b.podAnnotations,
 				},
 				Spec: coreV1.PodSpec{
@@ -91,8 +114,9 @@ func (b *Backend) NewJob(
 							Name:         "ghz",
 							Image:        imageRef,
 							Env:          envVars,
-							Resources:    backends.BuildResourceRequirements(b.resources),
-							Args:         defaultArgs,
+							EnvFrom:      backends.SecretEnvFrom(loadTest),
+							Resources:    backends.BuildResourceRequirements(backends.MergeResources(b.resources, loadTest.Spec.WorkerResources)),
+							Args:         buildArgs(loadTest.Spec.EnvVars),
 							VolumeMounts: mounts,
 						},
 					},
@@ -100,6 +124,69 @@ func (b *Backend) NewJob(
 			},
 		},
 	}
+
+	backends.ApplyScheduling(&job.Spec.Template.Spec, loadTest.Spec.Scheduling)
+	return job
+}
+
+const (
+	metricsVolumeName = "ghz-metrics"
+	metricsDir        = "/ghz-metrics"
+	// metricsLogFile is the debug log ghz writes a record to for each call, the collector sidecar counts them
+	metricsLogFile = metricsDir + "/debug.log"
+	// metricsLogMessage is the message of the debug log record of a completed call
+	metricsLogMessage = "Received response"
+)
+
+// metricsReceivers returns the collector receivers tailing the ghz debug log
+func metricsReceivers() map[string]interface{} {
+	return map[string]interface{}{
+		"filelog": map[string]interface{}{
+			"include":   []string{metricsLogFile},
+			"start_at":  "beginning",
+			"operators": []map[string]interface{}{{"type": "json_parser"}},
+		},
+	}
+}
+
+// metricsCounts returns the metrics counted from the ghz debug log, calls and calls that returned an error
+func metricsCounts() map[string]interface{} {
+	call := fmt.Sprintf(`attributes["msg"] == %q`, metricsLogMessage)
+	return map[string]interface{}{
+		"ghz.calls": map[string]interface{}{
+			"description": "gRPC calls made by ghz",
+			"conditions":  []string{call},
+		},
+		"ghz.call.errors": map[string]interface{}{
+			"description": "gRPC calls made by ghz that returned an error",
+			"conditions":  []string{call + ` and attributes["error"] != nil`},
+		},
+	}
+}
+
+// addMetricsExport makes ghz write its debug log to a volume shared with the collector sidecar, ghz only writes
+// the report once it has completed, so the collector counts calls from the log while the LoadTest runs
+func addMetricsExport(job *batchV1.Job, export backends.MetricsExport, loadTest loadTestV1.LoadTest) {
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, coreV1.Volume{
+		Name:         metricsVolumeName,
+		VolumeSource: coreV1.VolumeSource{EmptyDir: &coreV1.EmptyDirVolumeSource{}},
+	})
+
+	ghz := &podSpec.Containers[0]
+	ghz.Args = append(ghz.Args, "--debug="+metricsLogFile)
+	ghz.VolumeMounts = append(ghz.VolumeMounts, coreV1.VolumeMount{Name: metricsVolumeName, MountPath: metricsDir})
+
+	export.AddMetricsSidecars(podSpec, loadTest)
+	for i := range podSpec.InitContainers {
+		if podSpec.InitContainers[i].Name == backends.MetricsCollectorContainerName {
+			podSpec.InitContainers[i].VolumeMounts = append(podSpec.InitContainers[i].VolumeMounts, coreV1.VolumeMount{
+				Name:      metricsVolumeName,
+				MountPath: metricsDir,
+				ReadOnly:  true,
+			})
+		}
+	}
 }
 
 // NewFileVolumeAndMount creates a new volume and volume mount for a configmap file
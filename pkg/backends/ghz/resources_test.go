@@ -3,8 +3,10 @@ package ghz
 import (
 	"testing"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -143,3 +145,33 @@ func TestNewFileVolumeAndMount(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildArgs(t *testing.T) {
+	assert.Equal(t, defaultArgs, buildArgs(nil))
+
+	args := buildArgs(map[string]string{
+		"GHZ_CONCURRENCY":    "10",
+		"GHZ_TOTAL_REQUESTS": "1000",
+		"GHZ_RPS":            "",
+		"OTHER":              "value",
+	})
+	assert.Equal(t, append(defaultArgs, "--concurrency=10", "--total=1000"), args)
+	assert.Len(t, defaultArgs, 3)
+}
+
+func TestAddMetricsExport(t *testing.T) {
+	export := backends.MetricsExport{Protocol: backends.MetricsProtocolPrometheus, URL: "http://prometheus:9090/api/v1/write"}
+	job := &batchV1.Job{}
+	job.Spec.Template.Spec.Containers = []coreV1.Container{{Name: "ghz", Args: buildArgs(nil)}}
+
+	addMetricsExport(job, export, loadTestV1.LoadTest{})
+
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, append(buildArgs(nil), "--debug=/ghz-metrics/debug.log"), podSpec.Containers[0].Args)
+	assert.Equal(t, []coreV1.VolumeMount{{Name: "ghz-metrics", MountPath: "/ghz-metrics"}}, podSpec.Containers[0].VolumeMounts)
+	require.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, backends.MetricsCollectorContainerName, podSpec.InitContainers[0].Name)
+	assert.Contains(t, podSpec.InitContainers[0].VolumeMounts, coreV1.VolumeMount{Name: "ghz-metrics", MountPath: "/ghz-metrics", ReadOnly: true})
+	require.Len(t, podSpec.Volumes, 2)
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+}
@@ -16,6 +16,7 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
@@ -46,18 +47,23 @@ func init() {
 
 // Backend is the JMeter implementation of backend interface
 type Backend struct {
-	kubeClientSet   kubernetes.Interface
-	kangalClientSet clientSetV.Interface
-	logger          *zap.Logger
-	namespaceLister coreListersV1.NamespaceLister
-	masterResources backends.Resources
-	workerResources backends.Resources
-	masterConfig    loadTestV1.ImageDetails
-	workerConfig    loadTestV1.ImageDetails
-	config          *Config
-	podAnnotations  map[string]string
-	nodeSelector    map[string]string
-	tolerations     []coreV1.Toleration
+	kubeClientSet    kubernetes.Interface
+	kangalClientSet  clientSetV.Interface
+	logger           *zap.Logger
+	recorder         record.EventRecorder
+	namespaceLister  coreListersV1.NamespaceLister
+	masterResources  backends.Resources
+	workerResources  backends.Resources
+	resourceBounds   backends.ResourceBounds
+	schedulingPolicy backends.SchedulingPolicy
+	runtimeLimits    backends.RuntimeLimits
+	metricsExport    backends.MetricsExport
+	masterConfig     loadTestV1.ImageDetails
+	workerConfig     loadTestV1.ImageDetails
+	config           *Config
+	podAnnotations   map[string]string
+	nodeSelector     map[string]string
+	tolerations      []coreV1.Toleration
 }
 
 // Type returns backend type name
@@ -94,6 +100,13 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.WorkerMemoryLimits,
 		MemoryRequests: b.config.WorkerMemoryRequests,
 	}
+
+	b.resourceBounds = backends.ResourceBounds{
+		MinCPU:    b.config.MinCPU,
+		MaxCPU:    b.config.MaxCPU,
+		MinMemory: b.config.MinMemory,
+		MaxMemory: b.config.MaxMemory,
+	}
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -116,6 +129,11 @@ func (b *Backend) SetNamespaceLister(namespacesLister coreListersV1.NamespaceLis
 	b.namespaceLister = namespacesLister
 }
 
+// SetEventRecorder receives a copy of eventRecorder
+func (b *Backend) SetEventRecorder(recorder record.EventRecorder) {
+	b.recorder = recorder
+}
+
 // SetLogger receives a copy of logger
 func (b *Backend) SetLogger(logger *zap.Logger) {
 	b.logger = logger
@@ -131,6 +149,21 @@ func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.tolerations = tolerations
 }
 
+// SetSchedulingPolicy receives a copy of scheduling policy
+func (b *Backend) SetSchedulingPolicy(policy backends.SchedulingPolicy) {
+	b.schedulingPolicy = policy
+}
+
+// SetRuntimeLimits receives a copy of runtime limits
+func (b *Backend) SetRuntimeLimits(limits backends.RuntimeLimits) {
+	b.runtimeLimits = limits
+}
+
+// SetMetricsExport receives a copy of metrics export
+func (b *Backend) SetMetricsExport(export backends.MetricsExport) {
+	b.metricsExport = export
+}
+
 // TransformLoadTestSpec use given spec to validate and return a new one or error
 func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	if nil == spec.DistributedPods {
@@ -145,6 +178,30 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireTestFile
 	}
 
+	if err := backends.ValidateResources(b.masterResources, spec.MasterResources, b.resourceBounds); err != nil {
+		return fmt.Errorf("master %w", err)
+	}
+
+	if err := backends.ValidateResources(b.workerResources, spec.WorkerResources, b.resourceBounds); err != nil {
+		return fmt.Errorf("worker %w", err)
+	}
+
+	if err := backends.ValidateScheduling(spec.Scheduling, b.schedulingPolicy); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateThresholds(spec.Thresholds); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateSecretEnvVars(spec.SecretEnvVars); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateCallbacks(spec.Callbacks); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig.Image == "" || spec.MasterConfig.Tag == "" {
 		spec.MasterConfig.Image = b.masterConfig.Image
 		spec.MasterConfig.Tag = b.masterConfig.Tag
@@ -174,15 +231,19 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		zap.String("namespace", loadTest.Status.Namespace),
 	)
 
-	JMeterServices, err := b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).List(ctx, metaV1.ListOptions{})
+	JMeterServices, err := b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: backends.LoadTestSelector(loadTest, ""),
+	})
 	if err != nil {
 		return err
 	}
 
 	if len(JMeterServices.Items) == 0 {
-		_, err = b.kubeClientSet.CoreV1().ConfigMaps(loadTest.Status.Namespace).Create(ctx, b.NewConfigMap(loadTest), metaV1.CreateOptions{})
+		configMap := b.NewConfigMap(loadTest)
+		_, err = b.kubeClientSet.CoreV1().ConfigMaps(loadTest.Status.Namespace).Create(ctx, configMap, metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
 			logger.Error("Error on creating testfile configmap", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "configmap "+configMap.GetName(), err)
 			return err
 		}
 
@@ -194,9 +255,24 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 		_, err = b.kubeClientSet.CoreV1().Secrets(loadTest.Status.Namespace).Create(ctx, secret, metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
+			backends.RecordCreateFailed(b.recorder, &loadTest, "secret "+secret.GetName(), err)
 			return err
 		}
 
+		if b.metricsExport.Enabled() {
+			collectorConfigMap, err := b.metricsExport.NewMetricsCollectorConfigMap(loadTest, metricsReceivers)
+			if err != nil {
+				logger.Error("Error on creating metrics collector configmap", zap.Error(err))
+				return err
+			}
+			_, err = b.kubeClientSet.CoreV1().ConfigMaps(loadTest.Status.Namespace).Create(ctx, collectorConfigMap, metaV1.CreateOptions{})
+			if err != nil && !kerrors.IsAlreadyExists(err) {
+				logger.Error("Error on creating metrics collector configmap", zap.Error(err))
+				backends.RecordCreateFailed(b.recorder, &loadTest, "configmap "+collectorConfigMap.GetName(), err)
+				return err
+			}
+		}
+
 		configMaps, err := b.NewTestdataConfigMap(loadTest)
 		if err != nil {
 			logger.Error("Error on creating testdata configMaps", zap.Error(err))
@@ -208,23 +284,23 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 			return err
 		}
 
-		_, err = b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).Create(ctx, b.NewJMeterService(), metaV1.CreateOptions{})
+		service := b.NewJMeterService(loadTest)
+		_, err = b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).Create(ctx, service, metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
 			logger.Error("Error on creating new JMeter service", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "service "+service.GetName(), err)
 			return err
 		}
 
+		masterJob := b.NewJMeterMasterJob(loadTest, reportURL, b.podAnnotations)
 		_, err = b.
 			kubeClientSet.
 			BatchV1().
 			Jobs(loadTest.Status.Namespace).
-			Create(
-				ctx,
-				b.NewJMeterMasterJob(loadTest, reportURL, b.podAnnotations),
-				metaV1.CreateOptions{},
-			)
+			Create(ctx, masterJob, metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
 			logger.Error("Error on creating new JMeter master Job", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "job "+masterJob.GetName(), err)
 			return err
 		}
 
@@ -235,6 +311,24 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 // SyncStatus check the JMeter resources and calculate the current status of the LoadTest from them
 func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+	err := b.syncStatusPhase(ctx, loadTest, loadTestStatus)
+	if err != nil {
+		return err
+	}
+
+	pods, err := b.kubeClientSet.CoreV1().Pods(loadTestStatus.Namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: backends.LoadTestSelector(loadTest, ""),
+	})
+	if err != nil {
+		return err
+	}
+
+	backends.UpdateLoadTestStatusDetails(loadTestStatus, pods.Items)
+	return nil
+}
+
+// syncStatusPhase calculates the current phase of the LoadTest from JMeter resources
+func (b *Backend) syncStatusPhase(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
 	// Get the Namespace resource
 	namespace, err := b.namespaceLister.Get(loadTestStatus.Namespace)
 	if err != nil {
@@ -256,7 +350,7 @@ func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest,
 	}
 
 	pods, err := b.kubeClientSet.CoreV1().Pods(namespace.GetName()).List(ctx, metaV1.ListOptions{
-		LabelSelector: LoadTestWorkerLabelSelector,
+		LabelSelector: backends.LoadTestSelector(loadTest, LoadTestWorkerLabelSelector),
 	})
 	if err != nil {
 		return err
@@ -271,6 +365,7 @@ func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest,
 			// If the pod is not yet in the running phase check to see if the
 			// pod start date is greater than the start time.
 			if workerPodHasTimeout(pod.Status.StartTime, *loadTestStatus) {
+				backends.RecordTimeout(b.recorder, &loadTest, fmt.Sprintf("Worker pod %s is not running after %s", pod.GetName(), MaxWaitTimeForPods))
 				loadTestStatus.Phase = loadTestV1.LoadTestFinished
 				return nil
 			}
@@ -286,7 +381,7 @@ func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest,
 		}
 	}
 
-	job, err := b.kubeClientSet.BatchV1().Jobs(namespace.GetName()).Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	job, err := b.kubeClientSet.BatchV1().Jobs(namespace.GetName()).Get(ctx, backends.ResourceName(loadTest, loadTestJobName), metaV1.GetOptions{})
 	if err != nil {
 		return err
 	}
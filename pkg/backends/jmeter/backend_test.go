@@ -14,6 +14,7 @@ import (
 	"k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
 )
@@ -217,6 +218,13 @@ func TestTransformLoadTestSpec(t *testing.T) {
 		assert.Equal(t, spec.WorkerConfig.Image, "worker-image")
 		assert.Equal(t, spec.WorkerConfig.Tag, "worker-tag")
 	})
+
+	t.Run("Resources out of bounds", func(t *testing.T) {
+		jmeter.resourceBounds = backends.ResourceBounds{MaxCPU: "2"}
+		spec.WorkerResources = &loadTestV1.LoadTestResources{CPULimits: "4"}
+		err := jmeter.TransformLoadTestSpec(spec)
+		assert.EqualError(t, err, "worker invalid resources: cpu limits 4 are greater than maximum 2")
+	})
 }
 
 func TestSetDefaults(t *testing.T) {
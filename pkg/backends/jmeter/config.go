@@ -21,4 +21,8 @@ type Config struct {
 	TestDataDecompressImage string        `envconfig:"JMETER_TESTDATA_DECOMPRESS_IMAGE" default:"alpine:latest"`
 	RemoteCustomDataImage   string        `envconfig:"JMETER_WORKER_REMOTE_CUSTOM_DATA_IMAGE" default:"rclone/rclone:latest"`
 	WaitForResourceTimeout  time.Duration `envconfig:"WAIT_FOR_RESOURCE_TIMEOUT" default:"30s"`
+	MinCPU                  string        `envconfig:"JMETER_MIN_CPU"`
+	MaxCPU                  string        `envconfig:"JMETER_MAX_CPU"`
+	MinMemory               string        `envconfig:"JMETER_MIN_MEMORY"`
+	MaxMemory               string        `envconfig:"JMETER_MAX_MEMORY"`
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/hellofresh/kangal/pkg/backends"
 	"github.com/hellofresh/kangal/pkg/core/waitfor"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	loadTestV2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
 )
 
 const (
@@ -49,6 +50,8 @@ const (
 	loadTestSecretLabel = "env-vars-from-file"
 	// loadTestSecretLabelKey is a label key of a secret containing environment variables
 	loadTestSecretLabelKey = "secret-source"
+	// metricsInfluxDBAddress is where the metrics collector sidecar of worker pods receives Backend Listener metrics
+	metricsInfluxDBAddress = "localhost:8086"
 )
 
 var (
@@ -60,6 +63,10 @@ var (
 	loadTestSecretLabels = map[string]string{
 		loadTestSecretLabelKey: loadTestSecretLabel,
 	}
+	// metricsReceivers receive metrics of the InfluxDB Backend Listener, it runs on worker pods
+	metricsReceivers = map[string]interface{}{
+		"influxdb": map[string]interface{}{"endpoint": metricsInfluxDBAddress},
+	}
 )
 
 // NewConfigMap creates a new configMap containing loadtest script
@@ -72,10 +79,11 @@ func (b *Backend) NewConfigMap(loadTest loadTestV1.LoadTest) *coreV1.ConfigMap {
 
 	return &coreV1.ConfigMap{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name: loadTestFile,
-			Labels: map[string]string{
+			Name: backends.ResourceName(loadTest, loadTestFile),
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				"app": "hf-jmeter",
-			},
+			}),
+			OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
 		},
 		Data: data,
 	}
@@ -87,8 +95,9 @@ func (b *Backend) NewSecret(loadTest loadTestV1.LoadTest) (*coreV1.Secret, error
 
 	return &coreV1.Secret{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name:   loadTestEnvVars,
-			Labels: loadTestSecretLabels,
+			Name:            backends.ResourceName(loadTest, loadTestEnvVars),
+			Labels:          backends.LoadTestLabels(loadTest, loadTestSecretLabels),
+			OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
 		},
 		StringData: secretMap,
 	}, nil
@@ -148,11 +157,13 @@ func (b *Backend) NewTestdataConfigMap(loadTest loadTestV1.LoadTest) ([]*coreV1.
 		buf = *new(bytes.Buffer)
 		gzipWriter.Reset(&buf)
 
-		cmName := fmt.Sprintf("%s-%03d", loadTestFile, i)
+		cmName := backends.ResourceName(loadTest, fmt.Sprintf("%s-%03d", loadTestFile, i))
 
 		cMaps[i] = &coreV1.ConfigMap{
 			ObjectMeta: metaV1.ObjectMeta{
-				Name: cmName,
+				Name:            cmName,
+				Labels:          backends.LoadTestLabels(loadTest, nil),
+				OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
 			},
 			Data: data,
 		}
@@ -176,11 +187,9 @@ func (b *Backend) NewPVC(loadTest loadTestV1.LoadTest) *coreV1.PersistentVolumeC
 
 	return &coreV1.PersistentVolumeClaim{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name:   fmt.Sprintf("pvc-%s", loadTestWorkerName),
-			Labels: loadTestWorkerPodLabels,
-			OwnerReferences: []metaV1.OwnerReference{
-				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
-			},
+			Name:            backends.ResourceName(loadTest, fmt.Sprintf("pvc-%s", loadTestWorkerName)),
+			Labels:          backends.LoadTestLabels(loadTest, loadTestWorkerPodLabels),
+			OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
 		},
 		Spec: coreV1.PersistentVolumeClaimSpec{
 			AccessModes: []coreV1.PersistentVolumeAccessMode{coreV1.ReadWriteMany},
@@ -211,16 +220,15 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 
 	pod := &coreV1.Pod{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name:        fmt.Sprintf("%s-%03d", loadTestWorkerName, i),
-			Labels:      loadTestWorkerPodLabels,
-			Annotations: podAnnotations,
-			OwnerReferences: []metaV1.OwnerReference{
-				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
-			},
+			Name:            backends.ResourceName(loadTest, fmt.Sprintf("%s-%03d", loadTestWorkerName, i)),
+			Labels:          backends.LoadTestLabels(loadTest, loadTestWorkerPodLabels),
+			Annotations:     podAnnotations,
+			OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
 		},
 		Spec: coreV1.PodSpec{
-			NodeSelector: b.nodeSelector,
-			Tolerations:  b.tolerations,
+			ActiveDeadlineSeconds: b.runtimeLimits.ActiveDeadlineSeconds(loadTest.Spec),
+			NodeSelector:          b.nodeSelector,
+			Tolerations:           b.tolerations,
 			Affinity: &coreV1.Affinity{
 				PodAntiAffinity: &coreV1.PodAntiAffinity{
 					PreferredDuringSchedulingIgnoredDuringExecution: []coreV1.WeightedPodAffinityTerm{
@@ -269,16 +277,16 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 							MountPath: "/testdata",
 						},
 					},
-					Resources: backends.BuildResourceRequirements(b.workerResources),
-					EnvFrom: []coreV1.EnvFromSource{
+					Resources: backends.BuildResourceRequirements(backends.MergeResources(b.workerResources, loadTest.Spec.WorkerResources)),
+					EnvFrom: append([]coreV1.EnvFromSource{
 						{
 							SecretRef: &coreV1.SecretEnvSource{
 								LocalObjectReference: coreV1.LocalObjectReference{
-									Name: loadTestEnvVars,
+									Name: backends.ResourceName(loadTest, loadTestEnvVars),
 								},
 							},
 						},
-					},
+					}, backends.SecretEnvFrom(loadTest)...),
 				},
 			},
 			Volumes: []coreV1.Volume{
@@ -303,7 +311,7 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 		},
 	}
 
-	if _, ok := loadTest.Spec.EnvVars["JMETER_WORKER_REMOTE_CUSTOM_DATA_ENABLED"]; ok {
+	if loadTestV2.RemoteCustomDataEnabled(loadTest.Spec.EnvVars) {
 		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, coreV1.VolumeMount{
 			Name:      "customdata",
 			MountPath: "/customdata",
@@ -313,7 +321,7 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 				Name: "customdata",
 				VolumeSource: coreV1.VolumeSource{
 					PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{
-						ClaimName: fmt.Sprintf("pvc-%s", loadTestWorkerName),
+						ClaimName: backends.ResourceName(loadTest, fmt.Sprintf("pvc-%s", loadTestWorkerName)),
 					},
 				},
 			},
@@ -343,7 +351,7 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 					{
 						SecretRef: &coreV1.SecretEnvSource{
 							LocalObjectReference: coreV1.LocalObjectReference{
-								Name: loadTestEnvVars,
+								Name: backends.ResourceName(loadTest, loadTestEnvVars),
 							},
 						},
 					},
@@ -352,6 +360,11 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 		}
 	}
 
+	if b.metricsExport.Enabled() {
+		b.metricsExport.AddMetricsSidecars(&pod.Spec, loadTest)
+	}
+
+	backends.ApplyScheduling(&pod.Spec, loadTest.Spec.Scheduling)
 	return pod
 }
 
@@ -373,7 +386,7 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 	jMeterEnvVars := []coreV1.EnvVar{
 		{
 			Name:  "WORKER_SVC_NAME",
-			Value: loadTestWorkerServiceName,
+			Value: backends.ResourceName(loadTest, loadTestWorkerServiceName),
 		},
 		{
 			Name:  "USE_WORKERS",
@@ -388,24 +401,23 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 		})
 	}
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name: loadTestJobName,
-			Labels: map[string]string{
+			Name: backends.ResourceName(loadTest, loadTestJobName),
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				loadTestMasterJobLabelKey: loadTestJobName,
-			},
-			OwnerReferences: []metaV1.OwnerReference{
-				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
-			},
-			Annotations: podAnnotations,
+			}),
+			OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
+			Annotations:     podAnnotations,
 		},
 		Spec: batchV1.JobSpec{
-			BackoffLimit: &one,
+			ActiveDeadlineSeconds: b.runtimeLimits.ActiveDeadlineSeconds(loadTest.Spec),
+			BackoffLimit:          &one,
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
+					Labels: backends.LoadTestLabels(loadTest, map[string]string{
 						loadTestMasterJobLabelKey: loadTestJobName,
-					},
+					}),
 					Annotations: podAnnotations,
 				},
 
@@ -419,13 +431,14 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 							Image:           imageRef,
 							ImagePullPolicy: "Always",
 							Env:             jMeterEnvVars,
+							EnvFrom:         backends.SecretEnvFrom(loadTest),
 							VolumeMounts: []coreV1.VolumeMount{
 								{
 									Name:      "tests",
 									MountPath: "/tests",
 								},
 							},
-							Resources: backends.BuildResourceRequirements(b.masterResources),
+							Resources: backends.BuildResourceRequirements(backends.MergeResources(b.masterResources, loadTest.Spec.MasterResources)),
 						},
 					},
 					Volumes: []coreV1.Volume{
@@ -434,7 +447,7 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 							VolumeSource: coreV1.VolumeSource{
 								ConfigMap: &coreV1.ConfigMapVolumeSource{
 									LocalObjectReference: coreV1.LocalObjectReference{
-										Name: loadTestFile,
+										Name: backends.ResourceName(loadTest, loadTestFile),
 									},
 								},
 							},
@@ -444,19 +457,23 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 			},
 		},
 	}
+
+	backends.ApplyScheduling(&job.Spec.Template.Spec, loadTest.Spec.Scheduling)
+	return job
 }
 
 // NewJMeterService creates a new services to talk to jmeter worker pods
-func (b *Backend) NewJMeterService() *coreV1.Service {
+func (b *Backend) NewJMeterService(loadTest loadTestV1.LoadTest) *coreV1.Service {
 	return &coreV1.Service{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name: loadTestWorkerServiceName,
-			Labels: map[string]string{
+			Name: backends.ResourceName(loadTest, loadTestWorkerServiceName),
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				"app": LoadTestLabel,
-			},
+			}),
+			OwnerReferences: backends.LoadTestOwnerReferences(loadTest),
 		},
 		Spec: coreV1.ServiceSpec{
-			Selector:  loadTestWorkerPodLabels,
+			Selector:  backends.LoadTestLabels(loadTest, loadTestWorkerPodLabels),
 			ClusterIP: "None",
 			Ports: []coreV1.ServicePort{
 				{
@@ -488,6 +505,7 @@ func (b *Backend) CreatePodsWithTestdata(ctx context.Context, configMaps []*core
 		configMap, err := b.kubeClientSet.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
 			logger.Error("Error on creating testdata configMaps", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, loadTest, "configmap "+cm.GetName(), err)
 			return err
 		}
 
@@ -499,13 +517,14 @@ func (b *Backend) CreatePodsWithTestdata(ctx context.Context, configMaps []*core
 			}
 		}
 
-		if _, ok := loadTest.Spec.EnvVars["JMETER_WORKER_REMOTE_CUSTOM_DATA_ENABLED"]; ok {
+		if loadTestV2.RemoteCustomDataEnabled(loadTest.Spec.EnvVars) {
 			logger.Info("Remote custom data enabled, creating PVC")
 
 			pvc := b.NewPVC(*loadTest)
 			_, err = b.kubeClientSet.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metaV1.CreateOptions{})
 			if err != nil && !kerrors.IsAlreadyExists(err) {
 				logger.Error("Error on creating pvc", zap.Error(err))
+				backends.RecordCreateFailed(b.recorder, loadTest, "persistentvolumeclaim "+pvc.GetName(), err)
 				return err
 			}
 
@@ -523,6 +542,7 @@ func (b *Backend) CreatePodsWithTestdata(ctx context.Context, configMaps []*core
 		_, err = b.kubeClientSet.CoreV1().Pods(namespace).Create(ctx, pod, metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
 			logger.Error("Error on creating distributed pods", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, loadTest, "pod "+pod.GetName(), err)
 			return err
 		}
 
@@ -543,7 +563,10 @@ func (b *Backend) CreatePodsWithTestdata(ctx context.Context, configMaps []*core
 			logger.Warn("unable to watch pod state", zap.Error(err))
 			continue
 		}
-		waitfor.Resource(watchObj, (waitfor.Condition{}).PodRunning, b.config.WaitForResourceTimeout)
+		if _, err = waitfor.Resource(watchObj, (waitfor.Condition{}).PodRunning, b.config.WaitForResourceTimeout); err != nil {
+			logger.Warn("pod is not running yet", zap.String("pod", pod.GetName()), zap.Error(err))
+			backends.RecordTimeout(b.recorder, loadTest, fmt.Sprintf("Pod %s is not running after %s", pod.GetName(), b.config.WaitForResourceTimeout))
+		}
 	}
 	logger.Info("Created pods with test data")
 	return nil
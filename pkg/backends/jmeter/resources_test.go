@@ -148,3 +148,20 @@ func TestPodResourceConfiguration(t *testing.T) {
 	assert.Equal(t, c.workerResources.MemoryLimits, workerPod.Spec.Containers[0].Resources.Limits.Memory().String())
 	assert.Equal(t, c.workerResources.MemoryRequests, workerPod.Spec.Containers[0].Resources.Requests.Memory().String())
 }
+
+func TestPodMetricsExport(t *testing.T) {
+	c := &Backend{
+		logger: zaptest.NewLogger(t),
+		config: &Config{},
+	}
+
+	workerPod := c.NewPod(loadTestV1.LoadTest{}, 0, &coreV1.ConfigMap{}, map[string]string{"": ""})
+	for _, container := range workerPod.Spec.InitContainers {
+		assert.NotEqual(t, backends.MetricsCollectorContainerName, container.Name)
+	}
+
+	c.metricsExport = backends.MetricsExport{Protocol: backends.MetricsProtocolPrometheus, URL: "http://prometheus:9090/api/v1/write"}
+	workerPod = c.NewPod(loadTestV1.LoadTest{}, 0, &coreV1.ConfigMap{}, map[string]string{"": ""})
+	collector := workerPod.Spec.InitContainers[len(workerPod.Spec.InitContainers)-1]
+	assert.Equal(t, backends.MetricsCollectorContainerName, collector.Name)
+}
@@ -10,6 +10,7 @@ import (
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
@@ -20,6 +21,8 @@ var (
 	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
 	// ErrRequireTestFile the TestFile filed is required to not be an empty string
 	ErrRequireTestFile = errors.New("LoadTest TestFile is required")
+	// ErrMasterResourcesNotSupported backend runs worker pods only, so master resources can not be set
+	ErrMasterResourcesNotSupported = errors.New("LoadTest MasterResources are not supported, use WorkerResources")
 )
 
 func init() {
@@ -30,14 +33,19 @@ func init() {
 type Backend struct {
 	logger         *zap.Logger
 	kubeClientSet  kubernetes.Interface
+	recorder       record.EventRecorder
 	config         *Config
 	podAnnotations map[string]string
 	podTolerations []coreV1.Toleration
 
 	nodeSelector map[string]string
 	// defined on SetDefaults
-	image     loadTestV1.ImageDetails
-	resources backends.Resources
+	image            loadTestV1.ImageDetails
+	resources        backends.Resources
+	resourceBounds   backends.ResourceBounds
+	schedulingPolicy backends.SchedulingPolicy
+	runtimeLimits    backends.RuntimeLimits
+	metricsExport    backends.MetricsExport
 }
 
 // Type returns backend type name
@@ -64,6 +72,13 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.MemoryLimits,
 		MemoryRequests: b.config.MemoryRequests,
 	}
+
+	b.resourceBounds = backends.ResourceBounds{
+		MinCPU:    b.config.MinCPU,
+		MaxCPU:    b.config.MaxCPU,
+		MinMemory: b.config.MinMemory,
+		MaxMemory: b.config.MaxMemory,
+	}
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -76,11 +91,31 @@ func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.podTolerations = tolerations
 }
 
+// SetSchedulingPolicy receives a copy of scheduling policy
+func (b *Backend) SetSchedulingPolicy(policy backends.SchedulingPolicy) {
+	b.schedulingPolicy = policy
+}
+
+// SetRuntimeLimits receives a copy of runtime limits
+func (b *Backend) SetRuntimeLimits(limits backends.RuntimeLimits) {
+	b.runtimeLimits = limits
+}
+
+// SetMetricsExport receives a copy of metrics export
+func (b *Backend) SetMetricsExport(export backends.MetricsExport) {
+	b.metricsExport = export
+}
+
 // SetKubeClientSet receives a copy of kubeClientSet
 func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
 	b.kubeClientSet = kubeClientSet
 }
 
+// SetEventRecorder receives a copy of eventRecorder
+func (b *Backend) SetEventRecorder(recorder record.EventRecorder) {
+	b.recorder = recorder
+}
+
 // SetLogger receives a copy of logger
 func (b *Backend) SetLogger(logger *zap.Logger) {
 	b.logger = logger
@@ -105,6 +140,30 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireTestFile
 	}
 
+	if spec.MasterResources != nil {
+		return ErrMasterResourcesNotSupported
+	}
+
+	if err := backends.ValidateResources(b.resources, spec.WorkerResources, b.resourceBounds); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateScheduling(spec.Scheduling, b.schedulingPolicy); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateThresholds(spec.Thresholds); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateSecretEnvVars(spec.SecretEnvVars); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateCallbacks(spec.Callbacks); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig.Image == "" || spec.MasterConfig.Tag == "" {
 		spec.MasterConfig.Image = b.image.Image
 		spec.MasterConfig.Tag = b.image.Tag
@@ -119,7 +178,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		BatchV1().
 		Jobs(loadTest.Status.Namespace).
 		List(ctx, metaV1.ListOptions{
-			FieldSelector: fmt.Sprintf("metadata.name=%s", loadTestJobName),
+			FieldSelector: fmt.Sprintf("metadata.name=%s", backends.ResourceName(loadTest, loadTestJobName)),
 		})
 	if err != nil {
 		b.logger.Error("Error on listing jobs", zap.Error(err))
@@ -137,7 +196,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 	)
 
 	// Create testfile ConfigMap
-	tfCfgMap, err := NewFileConfigMap(loadTestFileConfigMapName, scriptTestFileName, loadTest.Spec.TestFile)
+	tfCfgMap, err := NewFileConfigMap(backends.ResourceName(loadTest, loadTestFileConfigMapName), scriptTestFileName, loadTest.Spec.TestFile)
 	if err != nil {
 		b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
 		return err
@@ -146,7 +205,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 	// Prepare testdata ConfigMap
 	if len(loadTest.Spec.TestData) != 0 {
-		tdCfgMap, err = NewFileConfigMap(loadTestDataConfigMapName, testdataFileName, loadTest.Spec.TestData)
+		tdCfgMap, err = NewFileConfigMap(backends.ResourceName(loadTest, loadTestDataConfigMapName), testdataFileName, loadTest.Spec.TestData)
 		if err != nil {
 			b.logger.Error("Error creating testdata configmap resource", zap.Error(err))
 			return err
@@ -156,12 +215,15 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 	// Create testfile and testdata configmaps
 	for _, cfg := range configMaps {
+		cfg.Labels = backends.LoadTestLabels(loadTest, nil)
+		cfg.OwnerReferences = backends.LoadTestOwnerReferences(loadTest)
 		_, err = b.kubeClientSet.
 			CoreV1().
 			ConfigMaps(loadTest.Status.Namespace).
 			Create(ctx, cfg, metaV1.CreateOptions{})
 		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 			b.logger.Error("Error creating configmap", zap.String("configmap", cfg.GetName()), zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "configmap "+cfg.GetName(), err)
 			return err
 		}
 	}
@@ -189,6 +251,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 			Create(ctx, secret, metaV1.CreateOptions{})
 		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 			b.logger.Error("Error on creating secret", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "secret "+secret.GetName(), err)
 			return err
 		}
 	}
@@ -202,6 +265,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 			Create(ctx, job, metaV1.CreateOptions{})
 		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 			b.logger.Error("Error on creating master job", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "job "+job.GetName(), err)
 			return err
 		}
 	}
@@ -210,7 +274,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 }
 
 // SyncStatus checks k6 resources and updates the status of the LoadTest resource
-func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
 	if loadTestStatus.Phase == "" {
 		loadTestStatus.Phase = loadTestV1.LoadTestCreating
 		return nil
@@ -223,12 +287,26 @@ func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTes
 	jobs, err := b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTestStatus.Namespace).
-		List(ctx, metaV1.ListOptions{})
+		List(ctx, metaV1.ListOptions{
+			LabelSelector: backends.LoadTestSelector(loadTest, ""),
+		})
 	if err != nil {
 		return err
 	}
 
 	loadTestStatus.Phase = determineLoadTestPhaseFromJobs(jobs.Items)
 	loadTestStatus.JobStatus = determineLoadTestStatusFromJobs(jobs.Items)
+
+	pods, err := b.kubeClientSet.
+		CoreV1().
+		Pods(loadTestStatus.Namespace).
+		List(ctx, metaV1.ListOptions{
+			LabelSelector: backends.LoadTestSelector(loadTest, ""),
+		})
+	if err != nil {
+		return err
+	}
+
+	backends.UpdateLoadTestStatusDetails(loadTestStatus, pods.Items)
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,7 +99,7 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, loadTestV1.LoadTestCreating, loadTest.Status.Phase)
 
 	// Simulate that job finished successfully
-	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, jobName(index), metaV1.GetOptions{})
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, backends.ResourceName(loadTest, jobName(index)), metaV1.GetOptions{})
 	require.NoError(t, err, "Error when getting jobs")
 	job.Status.Succeeded = 1
 	_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metaV1.UpdateOptions{})
@@ -109,3 +110,33 @@ func TestSyncStatus(t *testing.T) {
 	require.NoError(t, err, "SyncStatus error")
 	assert.Equal(t, loadTestV1.LoadTestFinished, loadTest.Status.Phase)
 }
+
+func TestTransformLoadTestSpec(t *testing.T) {
+	b := Backend{
+		resourceBounds: backends.ResourceBounds{MaxMemory: "1Gi"},
+	}
+	distributedPods := int32(1)
+
+	t.Run("Master resources", func(t *testing.T) {
+		spec := &loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte("test"),
+			MasterResources: &loadTestV1.LoadTestResources{CPULimits: "1"},
+		}
+		err := b.TransformLoadTestSpec(spec)
+		assert.EqualError(t, err, ErrMasterResourcesNotSupported.Error())
+	})
+
+	t.Run("Worker resources", func(t *testing.T) {
+		spec := &loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte("test"),
+			WorkerResources: &loadTestV1.LoadTestResources{MemoryLimits: "2Gi"},
+		}
+		err := b.TransformLoadTestSpec(spec)
+		assert.ErrorIs(t, err, backends.ErrInvalidResources)
+
+		spec.WorkerResources.MemoryLimits = "512Mi"
+		assert.NoError(t, b.TransformLoadTestSpec(spec))
+	})
+}
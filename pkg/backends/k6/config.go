@@ -8,4 +8,8 @@ type Config struct {
 	CPURequests    string `envconfig:"K6_CPU_REQUESTS"`
 	MemoryLimits   string `envconfig:"K6_MEMORY_LIMITS"`
 	MemoryRequests string `envconfig:"K6_MEMORY_REQUESTS"`
+	MinCPU         string `envconfig:"K6_MIN_CPU"`
+	MaxCPU         string `envconfig:"K6_MAX_CPU"`
+	MinMemory      string `envconfig:"K6_MIN_MEMORY"`
+	MaxMemory      string `envconfig:"K6_MAX_MEMORY"`
 }
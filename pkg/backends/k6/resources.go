@@ -3,6 +3,9 @@ package k6
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
@@ -72,6 +75,7 @@ func (b *Backend) NewJob(
 			},
 		})
 	}
+	envFrom = append(envFrom, backends.SecretEnvFrom(loadTest)...)
 
 	args := make([]string, len(defaultArgs))
 	copy(args, defaultArgs)
@@ -83,29 +87,35 @@ func (b *Backend) NewJob(
 		args = append(args, segmentArgs(index, *loadTest.Spec.DistributedPods)...)
 		args = append(args, sequenceArgs(*loadTest.Spec.DistributedPods)...)
 	}
+	if b.metricsExport.Enabled() {
+		metricsArgs, metricsEnvVars := metricsOutput(b.metricsExport, loadTest)
+		args = append(args, metricsArgs...)
+		envVars = append(envVars, metricsEnvVars...)
+	}
 
 	backoffLimit := int32(0)
 	distributedPod := int32(1)
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name:      jobName(index),
+			Name:      backends.ResourceName(loadTest, jobName(index)),
 			Namespace: loadTest.Status.Namespace,
-			Labels: map[string]string{
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				"name":           loadTestJobName,
 				loadTestLabelKey: loadTestWorkerLabelValue,
-			},
+			}),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		Spec: batchV1.JobSpec{
-			Parallelism:  &distributedPod,
-			Completions:  &distributedPod,
-			BackoffLimit: &backoffLimit,
+			ActiveDeadlineSeconds: b.runtimeLimits.ActiveDeadlineSeconds(loadTest.Spec),
+			Parallelism:           &distributedPod,
+			Completions:           &distributedPod,
+			BackoffLimit:          &backoffLimit,
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
+					Labels: backends.LoadTestLabels(loadTest, map[string]string{
 						"name":           loadTestJobName,
 						loadTestLabelKey: loadTestWorkerLabelValue,
-					},
+					}),
 					Annotations: b.podAnnotations,
 				},
 				Spec: coreV1.PodSpec{
@@ -135,7 +145,7 @@ func (b *Backend) NewJob(
 							Name:         "k6",
 							Image:        imageRef,
 							Env:          envVars,
-							Resources:    backends.BuildResourceRequirements(b.resources),
+							Resources:    backends.BuildResourceRequirements(backends.MergeResources(b.resources, loadTest.Spec.WorkerResources)),
 							Args:         args,
 							VolumeMounts: mounts,
 							EnvFrom:      envFrom,
@@ -145,6 +155,9 @@ func (b *Backend) NewJob(
 			},
 		},
 	}
+
+	backends.ApplyScheduling(&job.Spec.Template.Spec, loadTest.Spec.Scheduling)
+	return job
 }
 
 // NewFileVolumeAndMount creates a new volume and volume mount for a configmap file
@@ -193,6 +206,43 @@ func NewFileConfigMap(cfgName, filename string, content []byte) (*coreV1.ConfigM
 	}, nil
 }
 
+// metricsOutput returns the args and env vars of k6 output pushing metrics, metrics are tagged with
+// the loadtest labels and the pod name
+func metricsOutput(export backends.MetricsExport, loadTest loadTestV1.LoadTest) ([]string, []coreV1.EnvVar) {
+	labels := backends.MetricsLabels(loadTest)
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, 2*len(keys)+4)
+	for _, key := range keys {
+		args = append(args, "--tag", fmt.Sprintf("%s=%s", key, labels[key]))
+	}
+	args = append(args, "--tag", backends.MetricsPodLabel+"=$(POD_NAME)")
+
+	envVars := []coreV1.EnvVar{backends.MetricsPodNameEnvVar()}
+	if export.Protocol == backends.MetricsProtocolPrometheus {
+		return append(args, "--out", "experimental-prometheus-rw"), append(envVars,
+			coreV1.EnvVar{Name: "K6_PROMETHEUS_RW_SERVER_URL", Value: export.URL},
+			coreV1.EnvVar{Name: "K6_PROMETHEUS_RW_PUSH_INTERVAL", Value: export.Interval.String()},
+			coreV1.EnvVar{Name: "K6_PROMETHEUS_RW_TREND_STATS", Value: "p(50),p(95),p(99),avg,max"},
+		)
+	}
+
+	// URL was validated by the controller config
+	u, _ := url.Parse(export.URL)
+	return append(args, "--out", "experimental-opentelemetry"), append(envVars,
+		coreV1.EnvVar{Name: "K6_OTEL_EXPORTER_TYPE", Value: "http"},
+		coreV1.EnvVar{Name: "K6_OTEL_HTTP_EXPORTER_ENDPOINT", Value: u.Host},
+		coreV1.EnvVar{Name: "K6_OTEL_HTTP_EXPORTER_URL_PATH", Value: u.EscapedPath()},
+		coreV1.EnvVar{Name: "K6_OTEL_HTTP_EXPORTER_INSECURE", Value: strconv.FormatBool(u.Scheme == "http")},
+		coreV1.EnvVar{Name: "K6_OTEL_FLUSH_INTERVAL", Value: export.Interval.String()},
+		coreV1.EnvVar{Name: "K6_OTEL_SERVICE_NAME", Value: "kangal"},
+	)
+}
+
 func segmentArgs(index, total int32) []string {
 	args := make([]string, 0)
 	args = append(args, "--execution-segment")
@@ -276,9 +326,9 @@ func newSecret(loadTest loadTestV1.LoadTest, envs map[string]string) *coreV1.Sec
 	return &coreV1.Secret{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: name,
-			Labels: map[string]string{
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				loadTestLabelKey: name,
-			},
+			}),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		StringData: envs,
@@ -2,7 +2,9 @@ package k6
 
 import (
 	"testing"
+	"time"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
 	batchV1 "k8s.io/api/batch/v1"
@@ -143,3 +145,53 @@ func TestNewFileVolumeAndMount(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsOutput(t *testing.T) {
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{Tags: loadTestV1.LoadTestTags{"team": "kangal"}},
+	}
+	tags := []string{"--tag", "loadtest=loadtest-name", "--tag", "team=kangal", "--tag", "pod=$(POD_NAME)"}
+
+	for _, ti := range []struct {
+		tag          string
+		export       backends.MetricsExport
+		expectedArgs []string
+		expectedEnv  map[string]string
+	}{
+		{
+			tag:          "prometheus remote write",
+			export:       backends.MetricsExport{Protocol: backends.MetricsProtocolPrometheus, URL: "http://prometheus:9090/api/v1/write", Interval: 5 * time.Second},
+			expectedArgs: append(tags, "--out", "experimental-prometheus-rw"),
+			expectedEnv: map[string]string{
+				"K6_PROMETHEUS_RW_SERVER_URL":    "http://prometheus:9090/api/v1/write",
+				"K6_PROMETHEUS_RW_PUSH_INTERVAL": "5s",
+			},
+		},
+		{
+			tag:          "otlp",
+			export:       backends.MetricsExport{Protocol: backends.MetricsProtocolOTLP, URL: "https://otel.example.com/v1/metrics", Interval: 5 * time.Second},
+			expectedArgs: append(tags, "--out", "experimental-opentelemetry"),
+			expectedEnv: map[string]string{
+				"K6_OTEL_HTTP_EXPORTER_ENDPOINT": "otel.example.com",
+				"K6_OTEL_HTTP_EXPORTER_URL_PATH": "/v1/metrics",
+				"K6_OTEL_HTTP_EXPORTER_INSECURE": "false",
+				"K6_OTEL_FLUSH_INTERVAL":         "5s",
+			},
+		},
+	} {
+		t.Run(ti.tag, func(t *testing.T) {
+			args, envVars := metricsOutput(ti.export, loadTest)
+			assert.Equal(t, ti.expectedArgs, args)
+
+			env := make(map[string]string, len(envVars))
+			for _, envVar := range envVars {
+				env[envVar.Name] = envVar.Value
+			}
+			assert.Contains(t, env, "POD_NAME")
+			for name, value := range ti.expectedEnv {
+				assert.Equal(t, value, env[name], name)
+			}
+		})
+	}
+}
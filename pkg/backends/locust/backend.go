@@ -10,6 +10,7 @@ import (
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
@@ -30,15 +31,20 @@ func init() {
 type Backend struct {
 	logger         *zap.Logger
 	kubeClientSet  kubernetes.Interface
+	recorder       record.EventRecorder
 	config         *Config
 	podAnnotations map[string]string
 	podTolerations []coreV1.Toleration
 	nodeSelector   map[string]string
 
 	// defined on SetDefaults
-	image           loadTestV1.ImageDetails
-	masterResources backends.Resources
-	workerResources backends.Resources
+	image            loadTestV1.ImageDetails
+	masterResources  backends.Resources
+	workerResources  backends.Resources
+	resourceBounds   backends.ResourceBounds
+	schedulingPolicy backends.SchedulingPolicy
+	runtimeLimits    backends.RuntimeLimits
+	metricsExport    backends.MetricsExport
 }
 
 // Type returns backend type name
@@ -77,6 +83,13 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.MasterMemoryLimits,
 		MemoryRequests: b.config.MasterMemoryRequests,
 	}
+
+	b.resourceBounds = backends.ResourceBounds{
+		MinCPU:    b.config.MinCPU,
+		MaxCPU:    b.config.MaxCPU,
+		MinMemory: b.config.MinMemory,
+		MaxMemory: b.config.MaxMemory,
+	}
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -89,11 +102,31 @@ func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.podTolerations = tolerations
 }
 
+// SetSchedulingPolicy receives a copy of scheduling policy
+func (b *Backend) SetSchedulingPolicy(policy backends.SchedulingPolicy) {
+	b.schedulingPolicy = policy
+}
+
+// SetRuntimeLimits receives a copy of runtime limits
+func (b *Backend) SetRuntimeLimits(limits backends.RuntimeLimits) {
+	b.runtimeLimits = limits
+}
+
+// SetMetricsExport receives a copy of metrics export
+func (b *Backend) SetMetricsExport(export backends.MetricsExport) {
+	b.metricsExport = export
+}
+
 // SetKubeClientSet receives a copy of kubeClientSet
 func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
 	b.kubeClientSet = kubeClientSet
 }
 
+// SetEventRecorder receives a copy of eventRecorder
+func (b *Backend) SetEventRecorder(recorder record.EventRecorder) {
+	b.recorder = recorder
+}
+
 // SetLogger receives a copy of logger
 func (b *Backend) SetLogger(logger *zap.Logger) {
 	b.logger = logger
@@ -118,6 +151,30 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireTestFile
 	}
 
+	if err := backends.ValidateResources(b.masterResources, spec.MasterResources, b.resourceBounds); err != nil {
+		return fmt.Errorf("master %w", err)
+	}
+
+	if err := backends.ValidateResources(b.workerResources, spec.WorkerResources, b.resourceBounds); err != nil {
+		return fmt.Errorf("worker %w", err)
+	}
+
+	if err := backends.ValidateScheduling(spec.Scheduling, b.schedulingPolicy); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateThresholds(spec.Thresholds); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateSecretEnvVars(spec.SecretEnvVars); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateCallbacks(spec.Callbacks); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig.Image == "" || spec.MasterConfig.Tag == "" {
 		spec.MasterConfig.Image = b.image.Image
 		spec.MasterConfig.Tag = b.image.Tag
@@ -155,9 +212,26 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		Create(ctx, configMap, metaV1.CreateOptions{})
 	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 		b.logger.Error("Error on creating testfile configmap", zap.Error(err))
+		backends.RecordCreateFailed(b.recorder, &loadTest, "configmap "+configMap.GetName(), err)
 		return err
 	}
 
+	if b.metricsExport.Enabled() {
+		collectorConfigMap, err := b.metricsExport.NewMetricsCollectorConfigMap(loadTest, metricsReceivers(b.metricsExport))
+		if err != nil {
+			return err
+		}
+		_, err = b.kubeClientSet.
+			CoreV1().
+			ConfigMaps(loadTest.Status.Namespace).
+			Create(ctx, collectorConfigMap, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			b.logger.Error("Error on creating metrics collector configmap", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "configmap "+collectorConfigMap.GetName(), err)
+			return err
+		}
+	}
+
 	var secret *coreV1.Secret
 
 	if loadTest.Spec.EnvVars != nil {
@@ -168,17 +242,23 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 			Create(ctx, secret, metaV1.CreateOptions{})
 		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 			b.logger.Error("Error on creating secret", zap.Error(err))
+			backends.RecordCreateFailed(b.recorder, &loadTest, "secret "+secret.GetName(), err)
 			return err
 		}
 	}
 
-	masterJob := newMasterJob(loadTest, configMap, secret, reportURL, b.masterResources, b.podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.MasterConfig, b.logger)
+	masterJob := newMasterJob(loadTest, configMap, secret, reportURL, backends.MergeResources(b.masterResources, loadTest.Spec.MasterResources), b.podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.MasterConfig, b.logger)
+	masterJob.Spec.ActiveDeadlineSeconds = b.runtimeLimits.ActiveDeadlineSeconds(loadTest.Spec)
+	if b.metricsExport.Enabled() {
+		addMetricsExport(masterJob, b.metricsExport, loadTest)
+	}
 	_, err = b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTest.Status.Namespace).
 		Create(ctx, masterJob, metaV1.CreateOptions{})
 	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 		b.logger.Error("Error on creating master job", zap.Error(err))
+		backends.RecordCreateFailed(b.recorder, &loadTest, "job "+masterJob.GetName(), err)
 		return err
 	}
 
@@ -186,16 +266,19 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 	_, err = b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).Create(ctx, masterService, metaV1.CreateOptions{})
 	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 		b.logger.Error("Error on creating master service", zap.Error(err))
+		backends.RecordCreateFailed(b.recorder, &loadTest, "service "+masterService.GetName(), err)
 		return err
 	}
 
-	workerJob := newWorkerJob(loadTest, configMap, secret, masterService, b.workerResources, b.podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.WorkerConfig, b.logger)
+	workerJob := newWorkerJob(loadTest, configMap, secret, masterService, backends.MergeResources(b.workerResources, loadTest.Spec.WorkerResources), b.podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.WorkerConfig, b.logger)
+	workerJob.Spec.ActiveDeadlineSeconds = b.runtimeLimits.ActiveDeadlineSeconds(loadTest.Spec)
 	_, err = b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTest.Status.Namespace).
 		Create(ctx, workerJob, metaV1.CreateOptions{})
 	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
 		b.logger.Error("Error on creating worker job", zap.Error(err))
+		backends.RecordCreateFailed(b.recorder, &loadTest, "job "+workerJob.GetName(), err)
 		return err
 	}
 
@@ -243,5 +326,16 @@ func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest,
 	loadTestStatus.Phase = determineLoadTestStatusFromJobs(masterJob, workerJob)
 	loadTestStatus.JobStatus = masterJob.Status
 
+	pods, err := b.kubeClientSet.
+		CoreV1().
+		Pods(loadTestStatus.Namespace).
+		List(ctx, metaV1.ListOptions{
+			LabelSelector: backends.LoadTestSelector(loadTest, ""),
+		})
+	if err != nil {
+		return err
+	}
+
+	backends.UpdateLoadTestStatusDetails(loadTestStatus, pods.Items)
 	return nil
 }
@@ -13,4 +13,8 @@ type Config struct {
 	WorkerCPURequests    string `envconfig:"LOCUST_WORKER_CPU_REQUESTS"`
 	WorkerMemoryLimits   string `envconfig:"LOCUST_WORKER_MEMORY_LIMITS"`
 	WorkerMemoryRequests string `envconfig:"LOCUST_WORKER_MEMORY_REQUESTS"`
+	MinCPU               string `envconfig:"LOCUST_MIN_CPU"`
+	MaxCPU               string `envconfig:"LOCUST_MAX_CPU"`
+	MinMemory            string `envconfig:"LOCUST_MIN_MEMORY"`
+	MaxMemory            string `envconfig:"LOCUST_MAX_MEMORY"`
 }
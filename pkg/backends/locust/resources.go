@@ -32,6 +32,7 @@ func newConfigMap(loadTest loadTestV1.LoadTest) *coreV1.ConfigMap {
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:            name,
 			Namespace:       loadTest.Status.Namespace,
+			Labels:          backends.LoadTestLabels(loadTest, nil),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		BinaryData: map[string][]byte{
@@ -52,9 +53,9 @@ func newSecret(loadTest loadTestV1.LoadTest, envs map[string]string) *coreV1.Sec
 	return &coreV1.Secret{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: name,
-			Labels: map[string]string{
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				loadTestLabelKey: name,
-			},
+			}),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		StringData: envs,
@@ -114,28 +115,29 @@ func newMasterJob(
 			},
 		})
 	}
+	envFrom = append(envFrom, backends.SecretEnvFrom(loadTest)...)
 
 	// Locust does not support recovering after a failure
 	backoffLimit := int32(0)
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      name,
 			Namespace: loadTest.Status.Namespace,
-			Labels: map[string]string{
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				"name":           name,
 				loadTestLabelKey: loadTestMasterLabelValue,
-			},
+			}),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		Spec: batchV1.JobSpec{
 			BackoffLimit: &backoffLimit,
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
+					Labels: backends.LoadTestLabels(loadTest, map[string]string{
 						"name":           name,
 						loadTestLabelKey: loadTestMasterLabelValue,
-					},
+					}),
 					Annotations: podAnnotations,
 				},
 				Spec: coreV1.PodSpec{
@@ -175,6 +177,53 @@ func newMasterJob(
 			},
 		},
 	}
+
+	backends.ApplyScheduling(&job.Spec.Template.Spec, loadTest.Spec.Scheduling)
+	return job
+}
+
+// locustExporterAddress is where the exporter sidecar of the master pod exposes Locust stats as Prometheus metrics
+const locustExporterAddress = "localhost:9646"
+
+// metricsReceivers returns the collector receivers scraping the exporter sidecar
+func metricsReceivers(export backends.MetricsExport) map[string]interface{} {
+	return map[string]interface{}{
+		"prometheus": map[string]interface{}{
+			"config": map[string]interface{}{
+				"scrape_configs": []map[string]interface{}{
+					{
+						"job_name":        "locust",
+						"scrape_interval": export.Interval.String(),
+						"static_configs":  []map[string]interface{}{{"targets": []string{locustExporterAddress}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// addMetricsExport adds the exporter and collector sidecars to the master pod. The exporter reads stats from
+// Locust web UI, so the master runs with the web UI and starts and quits without user input instead of headless
+func addMetricsExport(masterJob *batchV1.Job, export backends.MetricsExport, loadTest loadTestV1.LoadTest) {
+	podSpec := &masterJob.Spec.Template.Spec
+
+	locust := &podSpec.Containers[0]
+	envVars := make([]coreV1.EnvVar, 0, len(locust.Env)+1)
+	for _, envVar := range locust.Env {
+		if envVar.Name != "LOCUST_HEADLESS" {
+			envVars = append(envVars, envVar)
+		}
+	}
+	locust.Env = append(envVars,
+		coreV1.EnvVar{Name: "LOCUST_AUTOSTART", Value: "true"},
+		coreV1.EnvVar{Name: "LOCUST_AUTOQUIT", Value: "0"},
+	)
+
+	export.AddMetricsSidecars(podSpec, loadTest, coreV1.Container{
+		Name:  backends.MetricsExporterContainerName,
+		Image: export.LocustExporterImage,
+		Args:  []string{"--locust.uri=http://localhost:8089"},
+	})
 }
 
 func newMasterService(loadTest loadTestV1.LoadTest, masterJob *batchV1.Job) *coreV1.Service {
@@ -186,9 +235,9 @@ func newMasterService(loadTest loadTestV1.LoadTest, masterJob *batchV1.Job) *cor
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      name,
 			Namespace: loadTest.Status.Namespace,
-			Labels: map[string]string{
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				loadTestLabelKey: name,
-			},
+			}),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		Spec: coreV1.ServiceSpec{
@@ -250,17 +299,18 @@ func newWorkerJob(
 			},
 		})
 	}
+	envFrom = append(envFrom, backends.SecretEnvFrom(loadTest)...)
 
 	// Locust does not support recovering after a failure
 	backoffLimit := int32(0)
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      name,
 			Namespace: loadTest.Status.Namespace,
-			Labels: map[string]string{
+			Labels: backends.LoadTestLabels(loadTest, map[string]string{
 				loadTestLabelKey: name,
-			},
+			}),
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		Spec: batchV1.JobSpec{
@@ -269,9 +319,9 @@ func newWorkerJob(
 			BackoffLimit: &backoffLimit,
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
+					Labels: backends.LoadTestLabels(loadTest, map[string]string{
 						loadTestLabelKey: loadTestWorkerLabelValue,
-					},
+					}),
 					Annotations: podAnnotations,
 				},
 				Spec: coreV1.PodSpec{
@@ -311,6 +361,9 @@ func newWorkerJob(
 			},
 		},
 	}
+
+	backends.ApplyScheduling(&job.Spec.Template.Spec, loadTest.Spec.Scheduling)
+	return job
 }
 
 // determineLoadTestStatusFromJobs reads existing job statuses and determines what the loadtest status should be
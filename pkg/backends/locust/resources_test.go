@@ -5,7 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
 
@@ -106,3 +108,27 @@ func TestGetLoadTestStatusFromJobs(t *testing.T) {
 		assert.Equal(t, scenario.Expected, actual)
 	}
 }
+
+func TestAddMetricsExport(t *testing.T) {
+	export := backends.MetricsExport{Protocol: backends.MetricsProtocolPrometheus, URL: "http://prometheus:9090/api/v1/write"}
+	masterJob := &batchV1.Job{}
+	masterJob.Spec.Template.Spec.Containers = []coreV1.Container{{
+		Name: "locust",
+		Env: []coreV1.EnvVar{
+			{Name: "LOCUST_HEADLESS", Value: "true"},
+			{Name: "LOCUST_MODE_MASTER", Value: "true"},
+		},
+	}}
+
+	addMetricsExport(masterJob, export, loadTestV1.LoadTest{})
+
+	podSpec := masterJob.Spec.Template.Spec
+	assert.Equal(t, []coreV1.EnvVar{
+		{Name: "LOCUST_MODE_MASTER", Value: "true"},
+		{Name: "LOCUST_AUTOSTART", Value: "true"},
+		{Name: "LOCUST_AUTOQUIT", Value: "0"},
+	}, podSpec.Containers[0].Env)
+	assert.Len(t, podSpec.InitContainers, 2)
+	assert.Equal(t, backends.MetricsExporterContainerName, podSpec.InitContainers[0].Name)
+	assert.Equal(t, backends.MetricsCollectorContainerName, podSpec.InitContainers[1].Name)
+}
@@ -0,0 +1,238 @@
+package backends
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	// MetricsProtocolPrometheus pushes metrics with Prometheus remote write
+	MetricsProtocolPrometheus = "prometheus"
+	// MetricsProtocolOTLP pushes metrics with OTLP over HTTP
+	MetricsProtocolOTLP = "otlp"
+
+	// MetricsCollectorContainerName is the name of the sidecar pushing metrics of backends without native export
+	MetricsCollectorContainerName = "metrics-collector"
+	// MetricsExporterContainerName is the name of the sidecar exposing metrics of backends without metrics endpoint
+	MetricsExporterContainerName = "metrics-exporter"
+
+	// MetricsLoadTestLabel is the label of exported metrics with the LoadTest name
+	MetricsLoadTestLabel = "loadtest"
+	// MetricsPodLabel is the label of exported metrics with the load generator pod name
+	MetricsPodLabel = "pod"
+
+	metricsCollectorConfigMapName = "metrics-collector"
+	metricsCollectorConfigFile    = "config.yaml"
+	metricsCollectorConfigDir     = "/etc/kangal-metrics"
+)
+
+var (
+	// ErrInvalidMetricsProtocol is returned when the metrics export protocol is not prometheus or otlp
+	ErrInvalidMetricsProtocol = errors.New("metrics export protocol should be prometheus or otlp")
+	// ErrInvalidMetricsURL is returned when the metrics export URL is not an absolute http or https URL
+	ErrInvalidMetricsURL = errors.New("metrics export URL should be an absolute http or https URL")
+)
+
+// MetricsExport configures live metrics pushed by load generators while LoadTests run
+type MetricsExport struct {
+	// Protocol is prometheus for Prometheus remote write or otlp for OTLP over HTTP, empty disables the export
+	Protocol string `envconfig:"METRICS_EXPORT_PROTOCOL"`
+	// URL is the remote write or the OTLP metrics endpoint
+	URL string `envconfig:"METRICS_EXPORT_URL"`
+	// Interval is how often metrics are pushed
+	Interval time.Duration `envconfig:"METRICS_EXPORT_INTERVAL" default:"10s"`
+	// CollectorImage is the OpenTelemetry Collector image of the sidecar pushing metrics of JMeter, Locust and ghz
+	CollectorImage string `envconfig:"METRICS_COLLECTOR_IMAGE" default:"otel/opentelemetry-collector-contrib:0.111.0"`
+	// LocustExporterImage is the image of the sidecar exposing Locust stats as Prometheus metrics
+	LocustExporterImage string `envconfig:"METRICS_LOCUST_EXPORTER_IMAGE" default:"containersol/locust_exporter:v0.5.0"`
+}
+
+// Enabled returns true when load generators push metrics
+func (m MetricsExport) Enabled() bool {
+	return m.Protocol != ""
+}
+
+// Validate checks the export can be configured when it is enabled
+func (m MetricsExport) Validate() error {
+	if !m.Enabled() {
+		return nil
+	}
+	if m.Protocol != MetricsProtocolPrometheus && m.Protocol != MetricsProtocolOTLP {
+		return ErrInvalidMetricsProtocol
+	}
+
+	u, err := url.Parse(m.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidMetricsURL
+	}
+	return nil
+}
+
+// MetricsLabels returns the labels of LoadTest metrics, the LoadTest tags and its name
+func MetricsLabels(loadTest loadTestV1.LoadTest) map[string]string {
+	labels := make(map[string]string, len(loadTest.Spec.Tags)+1)
+	for key, value := range loadTest.Spec.Tags {
+		labels[key] = value
+	}
+	labels[MetricsLoadTestLabel] = loadTest.GetName()
+	return labels
+}
+
+// MetricsPodNameEnvVar is the environment variable with the pod name, so it can be used in container args as $(POD_NAME)
+func MetricsPodNameEnvVar() coreV1.EnvVar {
+	return coreV1.EnvVar{
+		Name: "POD_NAME",
+		ValueFrom: &coreV1.EnvVarSource{
+			FieldRef: &coreV1.ObjectFieldSelector{FieldPath: "metadata.name"},
+		},
+	}
+}
+
+// NewMetricsCollectorConfigMap returns the OpenTelemetry Collector config of the sidecar pushing metrics
+// of the given receivers, metrics are labeled with the LoadTest name, tags and the pod name
+func (m MetricsExport) NewMetricsCollectorConfigMap(loadTest loadTestV1.LoadTest, receivers map[string]interface{}) (*coreV1.ConfigMap, error) {
+	return m.newMetricsCollectorConfigMap(loadTest, map[string]interface{}{
+		"receivers": receivers,
+	}, map[string]interface{}{
+		"metrics": m.metricsPipeline(sortedKeys(receivers)),
+	})
+}
+
+// NewLogMetricsCollectorConfigMap returns the OpenTelemetry Collector config of the sidecar counting log records
+// of the given receivers, for load generators logging each request without reporting metrics while running.
+// counts are the metrics of the count connector with the conditions of the log records they count
+func (m MetricsExport) NewLogMetricsCollectorConfigMap(loadTest loadTestV1.LoadTest, receivers, counts map[string]interface{}) (*coreV1.ConfigMap, error) {
+	metrics := m.metricsPipeline([]string{"count"})
+	// count connector emits delta sums, Prometheus remote write only exports cumulative ones
+	metrics["processors"] = append([]string{"deltatocumulative"}, metrics["processors"].([]string)...)
+
+	return m.newMetricsCollectorConfigMap(loadTest, map[string]interface{}{
+		"receivers":  receivers,
+		"connectors": map[string]interface{}{"count": map[string]interface{}{"logs": counts}},
+		"processors": map[string]interface{}{"deltatocumulative": map[string]interface{}{}},
+	}, map[string]interface{}{
+		"logs":    map[string]interface{}{"receivers": sortedKeys(receivers), "exporters": []string{"count"}},
+		"metrics": metrics,
+	})
+}
+
+func (m MetricsExport) metricsPipeline(receivers []string) map[string]interface{} {
+	return map[string]interface{}{
+		"receivers":  receivers,
+		"processors": []string{"attributes/kangal", "batch"},
+		"exporters":  []string{m.exporterName()},
+	}
+}
+
+func (m MetricsExport) exporterName() string {
+	if m.Protocol == MetricsProtocolOTLP {
+		return "otlphttp"
+	}
+	return "prometheusremotewrite"
+}
+
+func (m MetricsExport) newMetricsCollectorConfigMap(loadTest loadTestV1.LoadTest, components, pipelines map[string]interface{}) (*coreV1.ConfigMap, error) {
+	labels := MetricsLabels(loadTest)
+	actions := make([]map[string]string, 0, len(labels)+1)
+	for _, key := range sortedKeys(labels) {
+		actions = append(actions, map[string]string{"key": key, "value": labels[key], "action": "upsert"})
+	}
+	actions = append(actions, map[string]string{"key": MetricsPodLabel, "value": "${env:POD_NAME}", "action": "upsert"})
+
+	exporter := map[string]interface{}{"endpoint": m.URL}
+	if m.Protocol == MetricsProtocolOTLP {
+		exporter = map[string]interface{}{"metrics_endpoint": m.URL}
+	}
+
+	processors := map[string]interface{}{
+		"attributes/kangal": map[string]interface{}{"actions": actions},
+		"batch":             map[string]interface{}{"timeout": m.Interval.String()},
+	}
+	config := map[string]interface{}{
+		"processors": processors,
+		"exporters":  map[string]interface{}{m.exporterName(): exporter},
+		"service":    map[string]interface{}{"pipelines": pipelines},
+	}
+	for key, component := range components {
+		if key == "processors" {
+			for name, processor := range component.(map[string]interface{}) {
+				processors[name] = processor
+			}
+			continue
+		}
+		config[key] = component
+	}
+
+	// JSON is valid YAML, the collector reads it as is
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("could not build metrics collector config: %w", err)
+	}
+
+	return &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            ResourceName(loadTest, metricsCollectorConfigMapName),
+			Namespace:       loadTest.Status.Namespace,
+			Labels:          LoadTestLabels(loadTest, nil),
+			OwnerReferences: LoadTestOwnerReferences(loadTest),
+		},
+		Data: map[string]string{metricsCollectorConfigFile: string(data)},
+	}, nil
+}
+
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AddMetricsSidecars adds the sidecars to the pod spec as init containers restarted always, so they run next to
+// the load generator and are stopped once it has completed. The collector config volume is added as well
+func (m MetricsExport) AddMetricsSidecars(podSpec *coreV1.PodSpec, loadTest loadTestV1.LoadTest, sidecars ...coreV1.Container) {
+	restartAlways := coreV1.ContainerRestartPolicyAlways
+	volumeName := metricsCollectorConfigMapName + "-config"
+
+	collector := coreV1.Container{
+		Name:  MetricsCollectorContainerName,
+		Image: m.CollectorImage,
+		Args:  []string{"--config=" + metricsCollectorConfigDir + "/" + metricsCollectorConfigFile},
+		Env:   []coreV1.EnvVar{MetricsPodNameEnvVar()},
+		VolumeMounts: []coreV1.VolumeMount{
+			{Name: volumeName, MountPath: metricsCollectorConfigDir, ReadOnly: true},
+		},
+	}
+
+	for _, sidecar := range append(sidecars, collector) {
+		sidecar.RestartPolicy = &restartAlways
+		podSpec.InitContainers = append(podSpec.InitContainers, sidecar)
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, coreV1.Volume{
+		Name: volumeName,
+		VolumeSource: coreV1.VolumeSource{
+			ConfigMap: &coreV1.ConfigMapVolumeSource{
+				LocalObjectReference: coreV1.LocalObjectReference{
+					Name: ResourceName(loadTest, metricsCollectorConfigMapName),
+				},
+			},
+		},
+	})
+}
+
+// isMetricsSidecar returns true for containers of metrics sidecars, they are stopped once the load generator
+// has completed and do not affect the LoadTest status
+func isMetricsSidecar(containerName string) bool {
+	return containerName == MetricsCollectorContainerName || containerName == MetricsExporterContainerName
+}
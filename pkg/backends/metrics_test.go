@@ -0,0 +1,143 @@
+package backends
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestMetricsExportValidate(t *testing.T) {
+	for _, tc := range []struct {
+		export MetricsExport
+		err    error
+	}{
+		{MetricsExport{}, nil},
+		{MetricsExport{Protocol: MetricsProtocolPrometheus, URL: "http://prometheus:9090/api/v1/write"}, nil},
+		{MetricsExport{Protocol: MetricsProtocolOTLP, URL: "https://otel.example.com/v1/metrics"}, nil},
+		{MetricsExport{Protocol: "graphite", URL: "http://graphite:2003"}, ErrInvalidMetricsProtocol},
+		{MetricsExport{Protocol: MetricsProtocolOTLP}, ErrInvalidMetricsURL},
+		{MetricsExport{Protocol: MetricsProtocolOTLP, URL: "otel:4318"}, ErrInvalidMetricsURL},
+	} {
+		assert.ErrorIs(t, tc.export.Validate(), tc.err, tc.export)
+	}
+}
+
+func TestNewMetricsCollectorConfigMap(t *testing.T) {
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{Tags: loadTestV1.LoadTestTags{"service": "checkout"}},
+		Status:     loadTestV1.LoadTestStatus{Namespace: "loadtest-name"},
+	}
+	receivers := map[string]interface{}{"influxdb": map[string]interface{}{"endpoint": "localhost:8086"}}
+
+	type collectorConfig struct {
+		Processors map[string]struct {
+			Actions []map[string]string `json:"actions"`
+		} `json:"processors"`
+		Exporters map[string]map[string]string `json:"exporters"`
+		Service   struct {
+			Pipelines map[string]map[string][]string `json:"pipelines"`
+		} `json:"service"`
+	}
+
+	for _, tc := range []struct {
+		protocol string
+		exporter string
+		endpoint string
+	}{
+		{MetricsProtocolPrometheus, "prometheusremotewrite", "endpoint"},
+		{MetricsProtocolOTLP, "otlphttp", "metrics_endpoint"},
+	} {
+		protocol, exporter := tc.protocol, tc.exporter
+		t.Run(protocol, func(t *testing.T) {
+			export := MetricsExport{Protocol: protocol, URL: "http://metrics:4318/v1/metrics", Interval: 5 * time.Second}
+
+			configMap, err := export.NewMetricsCollectorConfigMap(loadTest, receivers)
+			require.NoError(t, err)
+			assert.Equal(t, "metrics-collector", configMap.GetName())
+			assert.Equal(t, "loadtest-name", configMap.GetLabels()[loadTestV1.LoadTestNameLabel])
+
+			var config collectorConfig
+			require.NoError(t, json.Unmarshal([]byte(configMap.Data[metricsCollectorConfigFile]), &config))
+			assert.Equal(t, map[string]string{tc.endpoint: "http://metrics:4318/v1/metrics"}, config.Exporters[exporter])
+			assert.Equal(t, []string{"influxdb"}, config.Service.Pipelines["metrics"]["receivers"])
+			assert.Equal(t, []string{exporter}, config.Service.Pipelines["metrics"]["exporters"])
+			assert.Equal(t, []map[string]string{
+				{"key": "loadtest", "value": "loadtest-name", "action": "upsert"},
+				{"key": "service", "value": "checkout", "action": "upsert"},
+				{"key": "pod", "value": "${env:POD_NAME}", "action": "upsert"},
+			}, config.Processors["attributes/kangal"].Actions)
+		})
+	}
+}
+
+func TestNewLogMetricsCollectorConfigMap(t *testing.T) {
+	export := MetricsExport{Protocol: MetricsProtocolPrometheus, URL: "http://prometheus:9090/api/v1/write", Interval: 5 * time.Second}
+	receivers := map[string]interface{}{"filelog": map[string]interface{}{"include": []string{"/logs/debug.log"}}}
+	counts := map[string]interface{}{"calls": map[string]interface{}{"conditions": []string{`attributes["msg"] == "call"`}}}
+
+	configMap, err := export.NewLogMetricsCollectorConfigMap(loadTestV1.LoadTest{}, receivers, counts)
+	require.NoError(t, err)
+
+	var config struct {
+		Processors map[string]interface{}            `json:"processors"`
+		Connectors map[string]map[string]interface{} `json:"connectors"`
+		Service    struct {
+			Pipelines map[string]map[string][]string `json:"pipelines"`
+		} `json:"service"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data[metricsCollectorConfigFile]), &config))
+	assert.Contains(t, config.Processors, "deltatocumulative")
+	assert.Contains(t, config.Connectors["count"]["logs"], "calls")
+	assert.Equal(t, map[string][]string{"receivers": {"filelog"}, "exporters": {"count"}}, config.Service.Pipelines["logs"])
+	assert.Equal(t, map[string][]string{
+		"receivers":  {"count"},
+		"processors": {"deltatocumulative", "attributes/kangal", "batch"},
+		"exporters":  {"prometheusremotewrite"},
+	}, config.Service.Pipelines["metrics"])
+}
+
+func TestAddMetricsSidecars(t *testing.T) {
+	export := MetricsExport{Protocol: MetricsProtocolPrometheus, URL: "http://prometheus:9090/api/v1/write", CollectorImage: "otel/collector"}
+	podSpec := coreV1.PodSpec{
+		InitContainers: []coreV1.Container{{Name: "init"}},
+		Containers:     []coreV1.Container{{Name: "loadtest"}},
+	}
+
+	export.AddMetricsSidecars(&podSpec, loadTestV1.LoadTest{}, coreV1.Container{Name: MetricsExporterContainerName})
+
+	require.Len(t, podSpec.InitContainers, 3)
+	assert.Nil(t, podSpec.InitContainers[0].RestartPolicy)
+	for _, sidecar := range podSpec.InitContainers[1:] {
+		require.NotNil(t, sidecar.RestartPolicy)
+		assert.Equal(t, coreV1.ContainerRestartPolicyAlways, *sidecar.RestartPolicy)
+	}
+	collector := podSpec.InitContainers[2]
+	assert.Equal(t, MetricsCollectorContainerName, collector.Name)
+	assert.Equal(t, "otel/collector", collector.Image)
+	require.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, "metrics-collector", podSpec.Volumes[0].ConfigMap.Name)
+}
+
+func TestDetectPodsFailureIgnoresMetricsSidecars(t *testing.T) {
+	pods := []coreV1.Pod{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-master"},
+		Status: coreV1.PodStatus{
+			Phase: coreV1.PodSucceeded,
+			InitContainerStatuses: []coreV1.ContainerStatus{{
+				Name:  MetricsExporterContainerName,
+				State: coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{ExitCode: 143, Reason: "Error"}},
+			}},
+		},
+	}}
+
+	_, _, failed := DetectPodsFailure(pods)
+	assert.False(t, failed)
+}
@@ -0,0 +1,54 @@
+package backends
+
+import (
+	"fmt"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// IsSharedNamespace returns true when LoadTest runs in a namespace shared with other LoadTests,
+// the controller records it when it assigns the namespace in single namespace mode
+func IsSharedNamespace(loadTest loadTestV1.LoadTest) bool {
+	return loadTest.Status.SharedNamespace
+}
+
+// ResourceName returns name of LoadTest resource, in shared namespace it is prefixed with LoadTest name
+func ResourceName(loadTest loadTestV1.LoadTest, name string) string {
+	if !IsSharedNamespace(loadTest) {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", loadTest.GetName(), name)
+}
+
+// LoadTestLabels returns a copy of given labels with LoadTest name label added
+func LoadTestLabels(loadTest loadTestV1.LoadTest, labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[loadTestV1.LoadTestNameLabel] = loadTest.GetName()
+	return result
+}
+
+// LoadTestSelector returns label selector matching LoadTest resources, in shared namespace
+// it is restricted to resources labeled with LoadTest name
+func LoadTestSelector(loadTest loadTestV1.LoadTest, selector string) string {
+	if !IsSharedNamespace(loadTest) {
+		return selector
+	}
+
+	nameSelector := fmt.Sprintf("%s=%s", loadTestV1.LoadTestNameLabel, loadTest.GetName())
+	if selector == "" {
+		return nameSelector
+	}
+	return selector + "," + nameSelector
+}
+
+// LoadTestOwnerReferences returns owner references making LoadTest resources garbage collected with it
+func LoadTestOwnerReferences(loadTest loadTestV1.LoadTest) []metaV1.OwnerReference {
+	return []metaV1.OwnerReference{
+		*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+	}
+}
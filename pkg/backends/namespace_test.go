@@ -0,0 +1,57 @@
+package backends_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSharedNamespace(t *testing.T) {
+	loadTest := loadTestV1.LoadTest{}
+	loadTest.Name = "loadtest-name"
+
+	t.Run("namespace per loadtest", func(t *testing.T) {
+		loadTest.Status.Namespace = "loadtest-name"
+		assert.False(t, backends.IsSharedNamespace(loadTest))
+
+		// the mode is recorded by the controller, it is not inferred from the namespace name
+		loadTest.Status.Namespace = "kangal-loadtests"
+
+		assert.False(t, backends.IsSharedNamespace(loadTest))
+		assert.Equal(t, "loadtest-master", backends.ResourceName(loadTest, "loadtest-master"))
+		assert.Equal(t, "app=loadtest-master", backends.LoadTestSelector(loadTest, "app=loadtest-master"))
+		assert.Equal(t, "", backends.LoadTestSelector(loadTest, ""))
+	})
+
+	t.Run("shared namespace", func(t *testing.T) {
+		loadTest.Status.Namespace = "kangal-loadtests"
+		loadTest.Status.SharedNamespace = true
+
+		assert.True(t, backends.IsSharedNamespace(loadTest))
+		assert.Equal(t, "loadtest-name-loadtest-master", backends.ResourceName(loadTest, "loadtest-master"))
+		assert.Equal(t, "app=loadtest-master,kangal.hellofresh.com/loadtest=loadtest-name", backends.LoadTestSelector(loadTest, "app=loadtest-master"))
+		assert.Equal(t, "kangal.hellofresh.com/loadtest=loadtest-name", backends.LoadTestSelector(loadTest, ""))
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		labels := map[string]string{"app": "loadtest-master"}
+
+		assert.Equal(t, map[string]string{
+			"app":                            "loadtest-master",
+			"kangal.hellofresh.com/loadtest": "loadtest-name",
+		}, backends.LoadTestLabels(loadTest, labels))
+		assert.Len(t, labels, 1)
+	})
+
+	t.Run("owner references", func(t *testing.T) {
+		refs := backends.LoadTestOwnerReferences(loadTest)
+
+		assert.Len(t, refs, 1)
+		assert.Equal(t, "LoadTest", refs[0].Kind)
+		assert.Equal(t, "loadtest-name", refs[0].Name)
+		assert.True(t, *refs[0].Controller)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	kubeCoreV1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	clientSetV "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned"
 )
@@ -56,6 +57,39 @@ func WithTolerations(tolerations []kubeCoreV1.Toleration) Option {
 	}
 }
 
+// WithSchedulingPolicy adds given scheduling policy to each registered backend that implements BackendSetSchedulingPolicy
+func WithSchedulingPolicy(policy SchedulingPolicy) Option {
+	return func(b *registry) {
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetSchedulingPolicy); ok {
+				iface.SetSchedulingPolicy(policy)
+			}
+		}
+	}
+}
+
+// WithRuntimeLimits adds given runtime limits to each registered backend that implements BackendSetRuntimeLimits
+func WithRuntimeLimits(limits RuntimeLimits) Option {
+	return func(b *registry) {
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetRuntimeLimits); ok {
+				iface.SetRuntimeLimits(limits)
+			}
+		}
+	}
+}
+
+// WithMetricsExport adds given metrics export to each registered backend that implements BackendSetMetricsExport
+func WithMetricsExport(export MetricsExport) Option {
+	return func(b *registry) {
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetMetricsExport); ok {
+				iface.SetMetricsExport(export)
+			}
+		}
+	}
+}
+
 // WithKubeClientSet adds given kubeClientSet to each registered backend that implements BackendKubeClientSet
 func WithKubeClientSet(kubeClientSet kubernetes.Interface) Option {
 	return func(b *registry) {
@@ -88,3 +122,14 @@ func WithNamespaceLister(namespaceLister coreListersV1.NamespaceLister) Option {
 		}
 	}
 }
+
+// WithEventRecorder adds given eventRecorder to each registered backend that implements BackendSetEventRecorder
+func WithEventRecorder(eventRecorder record.EventRecorder) Option {
+	return func(b *registry) {
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetEventRecorder); ok {
+				iface.SetEventRecorder(eventRecorder)
+			}
+		}
+	}
+}
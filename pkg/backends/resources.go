@@ -1,10 +1,18 @@
 package backends
 
 import (
+	"errors"
+	"fmt"
+
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
 
+// ErrInvalidResources is the error returned when LoadTest resources are not valid or out of bounds
+var ErrInvalidResources = errors.New("invalid resources")
+
 // Resources contains resources limits/requests
 type Resources struct {
 	CPULimits      string
@@ -40,3 +48,93 @@ func BuildResourceRequirements(resources Resources) coreV1.ResourceRequirements
 		Requests: requests,
 	}
 }
+
+// ResourceBounds contains minimum and maximum resources a LoadTest is allowed to request,
+// empty values are not checked
+type ResourceBounds struct {
+	MinCPU    string
+	MaxCPU    string
+	MinMemory string
+	MaxMemory string
+}
+
+// MergeResources returns defaults with values set in LoadTest resources overridden
+func MergeResources(defaults Resources, override *loadTestV1.LoadTestResources) Resources {
+	if override == nil {
+		return defaults
+	}
+
+	if override.CPULimits != "" {
+		defaults.CPULimits = override.CPULimits
+	}
+	if override.CPURequests != "" {
+		defaults.CPURequests = override.CPURequests
+	}
+	if override.MemoryLimits != "" {
+		defaults.MemoryLimits = override.MemoryLimits
+	}
+	if override.MemoryRequests != "" {
+		defaults.MemoryRequests = override.MemoryRequests
+	}
+
+	return defaults
+}
+
+// ValidateResources checks LoadTest resources are valid quantities within the bounds and that requests
+// do not exceed limits once merged with the defaults, so a request is not compared only to another overridden value
+func ValidateResources(defaults Resources, override *loadTestV1.LoadTestResources, bounds ResourceBounds) error {
+	if override == nil {
+		return nil
+	}
+	merged := MergeResources(defaults, override)
+
+	for _, r := range []struct {
+		name           string
+		requests       string
+		limits         string
+		mergedRequests string
+		mergedLimits   string
+		min            string
+		max            string
+	}{
+		{name: "cpu", requests: override.CPURequests, limits: override.CPULimits, mergedRequests: merged.CPURequests, mergedLimits: merged.CPULimits, min: bounds.MinCPU, max: bounds.MaxCPU},
+		{name: "memory", requests: override.MemoryRequests, limits: override.MemoryLimits, mergedRequests: merged.MemoryRequests, mergedLimits: merged.MemoryLimits, min: bounds.MinMemory, max: bounds.MaxMemory},
+	} {
+		if _, err := checkQuantityBounds(r.name+" requests", r.requests, r.min, r.max); err != nil {
+			return err
+		}
+		if _, err := checkQuantityBounds(r.name+" limits", r.limits, r.min, r.max); err != nil {
+			return err
+		}
+
+		// defaults which are not valid quantities are not set on containers
+		requests, requestsErr := resource.ParseQuantity(r.mergedRequests)
+		limits, limitsErr := resource.ParseQuantity(r.mergedLimits)
+		if requestsErr == nil && limitsErr == nil && requests.Cmp(limits) > 0 {
+			return fmt.Errorf("%w: %s requests %s are greater than limits %s", ErrInvalidResources, r.name, r.mergedRequests, r.mergedLimits)
+		}
+	}
+
+	return nil
+}
+
+func checkQuantityBounds(name, value, min, max string) (*resource.Quantity, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %q is not a valid quantity", ErrInvalidResources, name, value)
+	}
+
+	if minQuantity, err := resource.ParseQuantity(min); err == nil && quantity.Cmp(minQuantity) < 0 {
+		return nil, fmt.Errorf("%w: %s %s are less than minimum %s", ErrInvalidResources, name, value, min)
+	}
+
+	if maxQuantity, err := resource.ParseQuantity(max); err == nil && quantity.Cmp(maxQuantity) > 0 {
+		return nil, fmt.Errorf("%w: %s %s are greater than maximum %s", ErrInvalidResources, name, value, max)
+	}
+
+	return &quantity, nil
+}
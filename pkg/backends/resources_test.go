@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
 
 func TestBuildResourceRequirements(t *testing.T) {
@@ -18,3 +19,100 @@ func TestBuildResourceRequirements(t *testing.T) {
 	assert.Equal(t, 2, len(req.Limits))
 	assert.Equal(t, 2, len(req.Requests))
 }
+
+func TestMergeResources(t *testing.T) {
+	defaults := backends.Resources{
+		CPULimits:      "1",
+		CPURequests:    "500m",
+		MemoryLimits:   "1Gi",
+		MemoryRequests: "512Mi",
+	}
+
+	assert.Equal(t, defaults, backends.MergeResources(defaults, nil))
+	assert.Equal(t, backends.Resources{
+		CPULimits:      "2",
+		CPURequests:    "500m",
+		MemoryLimits:   "1Gi",
+		MemoryRequests: "1Gi",
+	}, backends.MergeResources(defaults, &loadTestV1.LoadTestResources{
+		CPULimits:      "2",
+		MemoryRequests: "1Gi",
+	}))
+}
+
+func TestValidateResources(t *testing.T) {
+	bounds := backends.ResourceBounds{
+		MinCPU:    "100m",
+		MaxCPU:    "2",
+		MaxMemory: "4Gi",
+	}
+	defaults := backends.Resources{
+		CPULimits:      "1",
+		CPURequests:    "500m",
+		MemoryLimits:   "2Gi",
+		MemoryRequests: "1Gi",
+	}
+
+	for _, tt := range []struct {
+		name          string
+		resources     *loadTestV1.LoadTestResources
+		expectedError string
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name: "within bounds",
+			resources: &loadTestV1.LoadTestResources{
+				CPULimits:      "2",
+				CPURequests:    "1",
+				MemoryLimits:   "4Gi",
+				MemoryRequests: "64Mi",
+			},
+		},
+		{
+			name:          "invalid quantity",
+			resources:     &loadTestV1.LoadTestResources{CPURequests: "one"},
+			expectedError: `invalid resources: cpu requests "one" is not a valid quantity`,
+		},
+		{
+			name:          "less than minimum",
+			resources:     &loadTestV1.LoadTestResources{CPURequests: "50m"},
+			expectedError: "invalid resources: cpu requests 50m are less than minimum 100m",
+		},
+		{
+			name:          "greater than maximum",
+			resources:     &loadTestV1.LoadTestResources{MemoryLimits: "8Gi"},
+			expectedError: "invalid resources: memory limits 8Gi are greater than maximum 4Gi",
+		},
+		{
+			name:          "requests greater than limits",
+			resources:     &loadTestV1.LoadTestResources{MemoryRequests: "2Gi", MemoryLimits: "1Gi"},
+			expectedError: "invalid resources: memory requests 2Gi are greater than limits 1Gi",
+		},
+		{
+			name:          "requests greater than default limits",
+			resources:     &loadTestV1.LoadTestResources{CPURequests: "2"},
+			expectedError: "invalid resources: cpu requests 2 are greater than limits 1",
+		},
+		{
+			name:          "limits less than default requests",
+			resources:     &loadTestV1.LoadTestResources{MemoryLimits: "512Mi"},
+			expectedError: "invalid resources: memory requests 1Gi are greater than limits 512Mi",
+		},
+		{
+			name:      "requests and limits overridden",
+			resources: &loadTestV1.LoadTestResources{CPURequests: "2", CPULimits: "2"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := backends.ValidateResources(defaults, tt.resources, bounds)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, backends.ErrInvalidResources)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package backends
+
+import (
+	"time"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// RuntimeLimits bound how long a LoadTest can run before it is terminated
+type RuntimeLimits struct {
+	// GracePeriod is added to LoadTest duration to allow pods to start and upload reports
+	GracePeriod time.Duration `envconfig:"RUNTIME_GRACE_PERIOD" default:"5m"`
+	// MaxRuntime is the global maximum, it is used as well when LoadTest duration is not set
+	MaxRuntime time.Duration `envconfig:"MAX_RUNTIME" default:"24h"`
+}
+
+// Deadline returns for how long LoadTest may run, zero means no limit
+func (l RuntimeLimits) Deadline(spec loadTestV1.LoadTestSpec) time.Duration {
+	var deadline time.Duration
+	if spec.Duration > 0 {
+		deadline = spec.Duration + l.GracePeriod
+	}
+
+	if l.MaxRuntime > 0 && (deadline == 0 || deadline > l.MaxRuntime) {
+		deadline = l.MaxRuntime
+	}
+
+	return deadline
+}
+
+// ActiveDeadlineSeconds returns LoadTest deadline to be set on jobs and pods, nil means no limit
+func (l RuntimeLimits) ActiveDeadlineSeconds(spec loadTestV1.LoadTestSpec) *int64 {
+	deadline := l.Deadline(spec)
+	if deadline == 0 {
+		return nil
+	}
+
+	seconds := int64(deadline / time.Second)
+	return &seconds
+}
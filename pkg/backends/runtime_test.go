@@ -0,0 +1,59 @@
+package backends_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestRuntimeLimitsDeadline(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		limits   backends.RuntimeLimits
+		duration time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "no limits",
+			duration: time.Minute,
+			expected: time.Minute,
+		},
+		{
+			name:   "no limits and no duration",
+			limits: backends.RuntimeLimits{},
+		},
+		{
+			name:     "duration and grace period",
+			limits:   backends.RuntimeLimits{GracePeriod: 5 * time.Minute, MaxRuntime: time.Hour},
+			duration: 10 * time.Minute,
+			expected: 15 * time.Minute,
+		},
+		{
+			name:     "global maximum",
+			limits:   backends.RuntimeLimits{GracePeriod: 5 * time.Minute, MaxRuntime: time.Hour},
+			duration: 2 * time.Hour,
+			expected: time.Hour,
+		},
+		{
+			name:     "global maximum without duration",
+			limits:   backends.RuntimeLimits{GracePeriod: 5 * time.Minute, MaxRuntime: time.Hour},
+			expected: time.Hour,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := loadTestV1.LoadTestSpec{Duration: tt.duration}
+			assert.Equal(t, tt.expected, tt.limits.Deadline(spec))
+
+			seconds := tt.limits.ActiveDeadlineSeconds(spec)
+			if tt.expected == 0 {
+				assert.Nil(t, seconds)
+				return
+			}
+			assert.Equal(t, int64(tt.expected/time.Second), *seconds)
+		})
+	}
+}
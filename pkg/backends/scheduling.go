@@ -0,0 +1,114 @@
+package backends
+
+import (
+	"errors"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// ErrSchedulingNotAllowed is the error returned when LoadTest scheduling constraints are not allowed by admin
+var ErrSchedulingNotAllowed = errors.New("scheduling not allowed")
+
+// SchedulingPolicy is the allowlist of scheduling constraints a LoadTest can set
+type SchedulingPolicy struct {
+	AllowedNodeSelectorKeys []string `envconfig:"ALLOWED_NODE_SELECTOR_KEYS"`
+	AllowedTolerationKeys   []string `envconfig:"ALLOWED_TOLERATION_KEYS"`
+	AllowedPriorityClasses  []string `envconfig:"ALLOWED_PRIORITY_CLASSES"`
+	AllowAffinity           bool     `envconfig:"ALLOW_AFFINITY" default:"false"`
+	AllowTopologySpread     bool     `envconfig:"ALLOW_TOPOLOGY_SPREAD" default:"false"`
+}
+
+// ValidateScheduling checks LoadTest scheduling constraints are allowed by the policy
+func ValidateScheduling(scheduling *loadTestV1.LoadTestScheduling, policy SchedulingPolicy) error {
+	if scheduling == nil {
+		return nil
+	}
+
+	for key := range scheduling.NodeSelector {
+		if !contains(policy.AllowedNodeSelectorKeys, key) {
+			return fmt.Errorf("%w: node selector key %q", ErrSchedulingNotAllowed, key)
+		}
+	}
+
+	for _, toleration := range scheduling.Tolerations {
+		if !contains(policy.AllowedTolerationKeys, toleration.Key) {
+			return fmt.Errorf("%w: toleration key %q", ErrSchedulingNotAllowed, toleration.Key)
+		}
+	}
+
+	if scheduling.PriorityClassName != "" && !contains(policy.AllowedPriorityClasses, scheduling.PriorityClassName) {
+		return fmt.Errorf("%w: priority class %q", ErrSchedulingNotAllowed, scheduling.PriorityClassName)
+	}
+
+	if scheduling.Affinity != nil && !policy.AllowAffinity {
+		return fmt.Errorf("%w: affinity", ErrSchedulingNotAllowed)
+	}
+
+	if len(scheduling.TopologySpreadConstraints) > 0 && !policy.AllowTopologySpread {
+		return fmt.Errorf("%w: topology spread constraints", ErrSchedulingNotAllowed)
+	}
+
+	return nil
+}
+
+// ApplyScheduling merges LoadTest scheduling constraints into pod spec built with global defaults
+func ApplyScheduling(podSpec *coreV1.PodSpec, scheduling *loadTestV1.LoadTestScheduling) {
+	if scheduling == nil {
+		return
+	}
+
+	if len(scheduling.NodeSelector) > 0 {
+		// global node selector is shared by all LoadTests, so it is copied before merging
+		nodeSelector := make(map[string]string, len(podSpec.NodeSelector)+len(scheduling.NodeSelector))
+		for k, v := range podSpec.NodeSelector {
+			nodeSelector[k] = v
+		}
+		for k, v := range scheduling.NodeSelector {
+			nodeSelector[k] = v
+		}
+		podSpec.NodeSelector = nodeSelector
+	}
+
+	if len(scheduling.Tolerations) > 0 {
+		tolerations := make([]coreV1.Toleration, 0, len(podSpec.Tolerations)+len(scheduling.Tolerations))
+		tolerations = append(tolerations, podSpec.Tolerations...)
+		podSpec.Tolerations = append(tolerations, scheduling.Tolerations...)
+	}
+
+	if scheduling.Affinity != nil {
+		affinity := &coreV1.Affinity{}
+		if podSpec.Affinity != nil {
+			affinity = podSpec.Affinity.DeepCopy()
+		}
+		if scheduling.Affinity.NodeAffinity != nil {
+			affinity.NodeAffinity = scheduling.Affinity.NodeAffinity.DeepCopy()
+		}
+		if scheduling.Affinity.PodAffinity != nil {
+			affinity.PodAffinity = scheduling.Affinity.PodAffinity.DeepCopy()
+		}
+		if scheduling.Affinity.PodAntiAffinity != nil {
+			affinity.PodAntiAffinity = scheduling.Affinity.PodAntiAffinity.DeepCopy()
+		}
+		podSpec.Affinity = affinity
+	}
+
+	for _, constraint := range scheduling.TopologySpreadConstraints {
+		podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, *constraint.DeepCopy())
+	}
+
+	if scheduling.PriorityClassName != "" {
+		podSpec.PriorityClassName = scheduling.PriorityClassName
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
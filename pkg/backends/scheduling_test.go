@@ -0,0 +1,115 @@
+package backends_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestValidateScheduling(t *testing.T) {
+	policy := backends.SchedulingPolicy{
+		AllowedNodeSelectorKeys: []string{"pool"},
+		AllowedTolerationKeys:   []string{"dedicated"},
+		AllowedPriorityClasses:  []string{"loadtest"},
+		AllowTopologySpread:     true,
+	}
+
+	for _, tt := range []struct {
+		name          string
+		scheduling    *loadTestV1.LoadTestScheduling
+		expectedError string
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name: "allowed",
+			scheduling: &loadTestV1.LoadTestScheduling{
+				NodeSelector:              map[string]string{"pool": "spot"},
+				Tolerations:               []coreV1.Toleration{{Key: "dedicated", Operator: coreV1.TolerationOpExists}},
+				TopologySpreadConstraints: []coreV1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}},
+				PriorityClassName:         "loadtest",
+			},
+		},
+		{
+			name:          "node selector key",
+			scheduling:    &loadTestV1.LoadTestScheduling{NodeSelector: map[string]string{"kubernetes.io/hostname": "node-1"}},
+			expectedError: `scheduling not allowed: node selector key "kubernetes.io/hostname"`,
+		},
+		{
+			name:          "toleration without key",
+			scheduling:    &loadTestV1.LoadTestScheduling{Tolerations: []coreV1.Toleration{{Operator: coreV1.TolerationOpExists}}},
+			expectedError: `scheduling not allowed: toleration key ""`,
+		},
+		{
+			name:          "priority class",
+			scheduling:    &loadTestV1.LoadTestScheduling{PriorityClassName: "system-cluster-critical"},
+			expectedError: `scheduling not allowed: priority class "system-cluster-critical"`,
+		},
+		{
+			name:          "affinity",
+			scheduling:    &loadTestV1.LoadTestScheduling{Affinity: &coreV1.Affinity{}},
+			expectedError: "scheduling not allowed: affinity",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := backends.ValidateScheduling(tt.scheduling, policy)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, backends.ErrSchedulingNotAllowed)
+			}
+		})
+	}
+}
+
+func TestApplyScheduling(t *testing.T) {
+	globalNodeSelector := map[string]string{"pool": "default", "team": "platform"}
+	globalTolerations := []coreV1.Toleration{{Key: "loadtest", Operator: coreV1.TolerationOpExists}}
+	antiAffinity := &coreV1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []coreV1.WeightedPodAffinityTerm{{Weight: 1}},
+	}
+	nodeAffinity := &coreV1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &coreV1.NodeSelector{},
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		podSpec := coreV1.PodSpec{NodeSelector: globalNodeSelector, Tolerations: globalTolerations}
+		backends.ApplyScheduling(&podSpec, nil)
+		assert.Equal(t, coreV1.PodSpec{NodeSelector: globalNodeSelector, Tolerations: globalTolerations}, podSpec)
+	})
+
+	t.Run("merged with global defaults", func(t *testing.T) {
+		podSpec := coreV1.PodSpec{
+			NodeSelector: globalNodeSelector,
+			Tolerations:  globalTolerations,
+			Affinity:     &coreV1.Affinity{PodAntiAffinity: antiAffinity},
+		}
+
+		backends.ApplyScheduling(&podSpec, &loadTestV1.LoadTestScheduling{
+			NodeSelector:              map[string]string{"pool": "spot"},
+			Tolerations:               []coreV1.Toleration{{Key: "spot", Operator: coreV1.TolerationOpExists}},
+			Affinity:                  &coreV1.Affinity{NodeAffinity: nodeAffinity},
+			TopologySpreadConstraints: []coreV1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}},
+			PriorityClassName:         "loadtest",
+		})
+
+		assert.Equal(t, map[string]string{"pool": "spot", "team": "platform"}, podSpec.NodeSelector)
+		assert.Equal(t, []coreV1.Toleration{
+			{Key: "loadtest", Operator: coreV1.TolerationOpExists},
+			{Key: "spot", Operator: coreV1.TolerationOpExists},
+		}, podSpec.Tolerations)
+		assert.Equal(t, &coreV1.Affinity{NodeAffinity: nodeAffinity, PodAntiAffinity: antiAffinity}, podSpec.Affinity)
+		assert.Len(t, podSpec.TopologySpreadConstraints, 1)
+		assert.Equal(t, "loadtest", podSpec.PriorityClassName)
+
+		// global defaults are shared between load tests and must not be modified
+		assert.Equal(t, map[string]string{"pool": "default", "team": "platform"}, globalNodeSelector)
+		assert.Len(t, globalTolerations, 1)
+	})
+}
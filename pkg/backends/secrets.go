@@ -0,0 +1,66 @@
+package backends
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// secretEnvVarsName is the name of the Secret with values of LoadTest secret env vars
+const secretEnvVarsName = "loadtest-secret-env-vars"
+
+var (
+	// ErrSecretEnvVarInvalid is returned when secret env var name or reference is not valid
+	ErrSecretEnvVarInvalid = errors.New("invalid secret env var")
+	// ErrSecretEnvVarDuplicate is returned when the same env var name is used more than once
+	ErrSecretEnvVarDuplicate = errors.New("duplicate secret env var")
+)
+
+// SecretEnvVarsName returns the name of the Secret the controller copies LoadTest secret env vars values to
+func SecretEnvVarsName(loadTest loadTestV1.LoadTest) string {
+	return ResourceName(loadTest, secretEnvVarsName)
+}
+
+// SecretEnvFrom returns the env source of LoadTest secret env vars to be added to LoadTest containers,
+// nil when LoadTest has no secret env vars
+func SecretEnvFrom(loadTest loadTestV1.LoadTest) []coreV1.EnvFromSource {
+	if len(loadTest.Spec.SecretEnvVars) == 0 {
+		return nil
+	}
+
+	return []coreV1.EnvFromSource{
+		{
+			SecretRef: &coreV1.SecretEnvSource{
+				LocalObjectReference: coreV1.LocalObjectReference{
+					Name: SecretEnvVarsName(loadTest),
+				},
+			},
+		},
+	}
+}
+
+// ValidateSecretEnvVars checks LoadTest secret env vars names and Secret references
+func ValidateSecretEnvVars(secretEnvVars []loadTestV1.LoadTestSecretEnvVar) error {
+	names := make(map[string]bool, len(secretEnvVars))
+	for _, envVar := range secretEnvVars {
+		if errs := validation.IsEnvVarName(envVar.Name); len(errs) > 0 {
+			return fmt.Errorf("%w name %q: %s", ErrSecretEnvVarInvalid, envVar.Name, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsDNS1123Subdomain(envVar.SecretName); len(errs) > 0 {
+			return fmt.Errorf("%w %s secret name %q: %s", ErrSecretEnvVarInvalid, envVar.Name, envVar.SecretName, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsConfigMapKey(envVar.Key); len(errs) > 0 {
+			return fmt.Errorf("%w %s secret key %q: %s", ErrSecretEnvVarInvalid, envVar.Name, envVar.Key, strings.Join(errs, ", "))
+		}
+		if names[envVar.Name] {
+			return fmt.Errorf("%w %q", ErrSecretEnvVarDuplicate, envVar.Name)
+		}
+		names[envVar.Name] = true
+	}
+	return nil
+}
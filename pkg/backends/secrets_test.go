@@ -0,0 +1,76 @@
+package backends_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSecretEnvFrom(t *testing.T) {
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Status:     loadTestV1.LoadTestStatus{Namespace: "shared", SharedNamespace: true},
+	}
+	assert.Nil(t, backends.SecretEnvFrom(loadTest))
+
+	loadTest.Spec.SecretEnvVars = []loadTestV1.LoadTestSecretEnvVar{{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"}}
+	envFrom := backends.SecretEnvFrom(loadTest)
+	if assert.Len(t, envFrom, 1) {
+		assert.Equal(t, "loadtest-name-loadtest-secret-env-vars", envFrom[0].SecretRef.Name)
+	}
+}
+
+func TestValidateSecretEnvVars(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		secretEnvVars []loadTestV1.LoadTestSecretEnvVar
+		expectedErr   error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			secretEnvVars: []loadTestV1.LoadTestSecretEnvVar{
+				{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"},
+				{Name: "DB_PASSWORD", SecretName: "db", Key: "db.password"},
+			},
+		},
+		{
+			name:          "invalid name",
+			secretEnvVars: []loadTestV1.LoadTestSecretEnvVar{{Name: "API TOKEN", SecretName: "api-credentials", Key: "token"}},
+			expectedErr:   backends.ErrSecretEnvVarInvalid,
+		},
+		{
+			name:          "invalid secret name",
+			secretEnvVars: []loadTestV1.LoadTestSecretEnvVar{{Name: "API_TOKEN", SecretName: "API_Credentials", Key: "token"}},
+			expectedErr:   backends.ErrSecretEnvVarInvalid,
+		},
+		{
+			name:          "missing key",
+			secretEnvVars: []loadTestV1.LoadTestSecretEnvVar{{Name: "API_TOKEN", SecretName: "api-credentials"}},
+			expectedErr:   backends.ErrSecretEnvVarInvalid,
+		},
+		{
+			name: "duplicate name",
+			secretEnvVars: []loadTestV1.LoadTestSecretEnvVar{
+				{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"},
+				{Name: "API_TOKEN", SecretName: "other-credentials", Key: "token"},
+			},
+			expectedErr: backends.ErrSecretEnvVarDuplicate,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := backends.ValidateSecretEnvVars(tt.secretEnvVars)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
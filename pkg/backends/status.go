@@ -0,0 +1,225 @@
+package backends
+
+import (
+	"fmt"
+	"strings"
+
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// deadlineExceededReason is the reason set by Kubernetes on jobs and pods which exceeded activeDeadlineSeconds
+const deadlineExceededReason = "DeadlineExceeded"
+
+// waitingReasons maps container waiting reasons that will never recover by themselves to LoadTest reasons
+var waitingReasons = map[string]string{
+	"ErrImagePull":               loadTestV1.LoadTestReasonImagePullFailure,
+	"ImagePullBackOff":           loadTestV1.LoadTestReasonImagePullFailure,
+	"InvalidImageName":           loadTestV1.LoadTestReasonImagePullFailure,
+	"ErrImageNeverPull":          loadTestV1.LoadTestReasonImagePullFailure,
+	"CreateContainerConfigError": loadTestV1.LoadTestReasonContainerConfigError,
+	"CreateContainerError":       loadTestV1.LoadTestReasonContainerConfigError,
+	"CrashLoopBackOff":           loadTestV1.LoadTestReasonCrashLoop,
+}
+
+// progressReasons are the reasons used for non failed phases
+var progressReasons = map[string]bool{
+	loadTestV1.LoadTestReasonCreating:    true,
+	loadTestV1.LoadTestReasonPodsPending: true,
+	loadTestV1.LoadTestReasonRunning:     true,
+	loadTestV1.LoadTestReasonCompleted:   true,
+}
+
+// DetectPodsFailure inspects pods and their containers states and returns the reason and message
+// explaining why the loadtest can not succeed. Last return value is false when no failure was found
+func DetectPodsFailure(pods []coreV1.Pod) (string, string, bool) {
+	for _, pod := range pods {
+		// containers of pods which exceeded their deadline are killed, do not report them as failed
+		if pod.Status.Phase == coreV1.PodFailed && pod.Status.Reason == deadlineExceededReason {
+			return loadTestV1.LoadTestReasonDeadlineExceeded, fmt.Sprintf("pod %s failed: %s", pod.GetName(), pod.Status.Message), true
+		}
+
+		statuses := make([]coreV1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+		for _, cs := range statuses {
+			if isMetricsSidecar(cs.Name) {
+				continue
+			}
+			if reason, message, ok := detectContainerFailure(pod.GetName(), cs); ok {
+				return reason, message, true
+			}
+		}
+
+		if pod.Status.Phase == coreV1.PodFailed && pod.Status.Reason != "" {
+			return pod.Status.Reason, fmt.Sprintf("pod %s failed: %s", pod.GetName(), pod.Status.Message), true
+		}
+	}
+
+	return "", "", false
+}
+
+func detectContainerFailure(podName string, cs coreV1.ContainerStatus) (string, string, bool) {
+	if waiting := cs.State.Waiting; waiting != nil {
+		if reason, ok := waitingReasons[waiting.Reason]; ok {
+			return reason, fmt.Sprintf("container %s in pod %s is waiting: %s", cs.Name, podName, joinReasonMessage(waiting.Reason, waiting.Message)), true
+		}
+	}
+
+	for _, terminated := range []*coreV1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+		if terminated == nil {
+			continue
+		}
+		if terminated.Reason == "OOMKilled" {
+			return loadTestV1.LoadTestReasonOOMKilled, fmt.Sprintf("container %s in pod %s was killed for exceeding its memory limit", cs.Name, podName), true
+		}
+		if terminated.ExitCode != 0 {
+			return loadTestV1.LoadTestReasonContainerFailed, fmt.Sprintf("container %s in pod %s exited with code %d: %s", cs.Name, podName, terminated.ExitCode, joinReasonMessage(terminated.Reason, terminated.Message)), true
+		}
+	}
+
+	return "", "", false
+}
+
+func joinReasonMessage(reason, message string) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", reason, message))
+}
+
+// detectJobFailure returns the reason and message of a failed job condition
+func detectJobFailure(status batchV1.JobStatus) (string, string, bool) {
+	for _, condition := range status.Conditions {
+		if condition.Type == batchV1.JobFailed && condition.Status == coreV1.ConditionTrue {
+			if condition.Reason == deadlineExceededReason {
+				return loadTestV1.LoadTestReasonDeadlineExceeded, fmt.Sprintf("job failed: %s", condition.Message), true
+			}
+			return loadTestV1.LoadTestReasonJobFailed, fmt.Sprintf("%s: %s", condition.Reason, condition.Message), true
+		}
+	}
+	return "", "", false
+}
+
+// JobDeadlineExceeded returns the message of job failed condition when the job exceeded its active deadline
+func JobDeadlineExceeded(status batchV1.JobStatus) (string, bool) {
+	if reason, message, ok := detectJobFailure(status); ok && reason == loadTestV1.LoadTestReasonDeadlineExceeded {
+		return message, true
+	}
+	return "", false
+}
+
+// earliestPodStartTime returns the start time of the pod which started first
+func earliestPodStartTime(pods []coreV1.Pod) *metaV1.Time {
+	var startTime *metaV1.Time
+	for _, pod := range pods {
+		if pod.Status.StartTime == nil {
+			continue
+		}
+		if startTime == nil || pod.Status.StartTime.Before(startTime) {
+			startTime = pod.Status.StartTime.DeepCopy()
+		}
+	}
+	return startTime
+}
+
+// UpdateLoadTestStatusDetails fills start and completion time, reason, message and conditions
+// of the given status based on its current phase and the state of the loadtest pods.
+// Backends should call it from SyncStatus once the phase was determined
+func UpdateLoadTestStatusDetails(status *loadTestV1.LoadTestStatus, pods []coreV1.Pod) {
+	// terminal phases details are recorded only once, pods may already be gone
+	if isTerminalPhase(status.Phase) && status.CompletionTime != nil {
+		return
+	}
+
+	now := metaV1.Now()
+
+	if status.StartTime == nil && (status.Phase == loadTestV1.LoadTestRunning || isTerminalPhase(status.Phase)) {
+		switch startTime := earliestPodStartTime(pods); {
+		case startTime != nil:
+			status.StartTime = startTime
+		case status.JobStatus.StartTime != nil:
+			status.StartTime = status.JobStatus.StartTime.DeepCopy()
+		default:
+			status.StartTime = &now
+		}
+	}
+
+	if status.CompletionTime == nil && isTerminalPhase(status.Phase) {
+		if status.JobStatus.CompletionTime != nil {
+			status.CompletionTime = status.JobStatus.CompletionTime.DeepCopy()
+		} else {
+			status.CompletionTime = &now
+		}
+	}
+
+	reason, message := loadTestV1.LoadTestReasonCreating, "loadtest resources are being created"
+	switch status.Phase {
+	case loadTestV1.LoadTestStarting:
+		reason, message = loadTestV1.LoadTestReasonPodsPending, "waiting for loadtest pods to be running"
+		// surface problems early, e.g. image can not be pulled while pods are still pending
+		if failureReason, failureMessage, ok := DetectPodsFailure(pods); ok {
+			reason, message = failureReason, failureMessage
+		}
+	case loadTestV1.LoadTestRunning:
+		reason, message = loadTestV1.LoadTestReasonRunning, "loadtest pods are running"
+	case loadTestV1.LoadTestFinished:
+		reason, message = loadTestV1.LoadTestReasonCompleted, "loadtest has finished"
+	case loadTestV1.LoadTestErrored:
+		reason, message = loadTestV1.LoadTestReasonJobFailed, "loadtest has errored"
+		if status.Reason != "" && !progressReasons[status.Reason] {
+			// keep the failure reason already set by the backend or controller
+			reason, message = status.Reason, status.Message
+		}
+		if failureReason, failureMessage, ok := DetectPodsFailure(pods); ok {
+			reason, message = failureReason, failureMessage
+		} else if failureReason, failureMessage, ok := detectJobFailure(status.JobStatus); ok {
+			reason, message = failureReason, failureMessage
+		}
+	}
+
+	status.Reason = reason
+	status.Message = message
+
+	setCondition(status, loadTestV1.LoadTestConditionReady, status.Phase == loadTestV1.LoadTestRunning, reason, message)
+	if isTerminalPhase(status.Phase) {
+		setCondition(status, loadTestV1.LoadTestConditionComplete, status.Phase == loadTestV1.LoadTestFinished, reason, message)
+		setCondition(status, loadTestV1.LoadTestConditionFailed, status.Phase == loadTestV1.LoadTestErrored, reason, message)
+	}
+}
+
+func setCondition(status *loadTestV1.LoadTestStatus, conditionType loadTestV1.LoadTestConditionType, value bool, reason, message string) {
+	conditionStatus := metaV1.ConditionFalse
+	if value {
+		conditionStatus = metaV1.ConditionTrue
+	}
+
+	meta.SetStatusCondition(&status.Conditions, metaV1.Condition{
+		Type:    conditionType.String(),
+		Status:  conditionStatus,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+func isTerminalPhase(phase loadTestV1.LoadTestPhase) bool {
+	return phase == loadTestV1.LoadTestFinished || phase == loadTestV1.LoadTestErrored
+}
+
+// SetLoadTestErrored marks LoadTest as errored with the given reason and message, e.g. when it was terminated by the controller
+func SetLoadTestErrored(status *loadTestV1.LoadTestStatus, reason, message string) {
+	now := metaV1.Now()
+
+	status.Phase = loadTestV1.LoadTestErrored
+	status.Reason = reason
+	status.Message = message
+	if status.CompletionTime == nil {
+		status.CompletionTime = &now
+	}
+
+	setCondition(status, loadTestV1.LoadTestConditionReady, false, reason, message)
+	setCondition(status, loadTestV1.LoadTestConditionComplete, false, reason, message)
+	setCondition(status, loadTestV1.LoadTestConditionFailed, true, reason, message)
+}
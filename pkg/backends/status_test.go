@@ -0,0 +1,213 @@
+package backends_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func podWithContainerState(name string, state coreV1.ContainerState) coreV1.Pod {
+	return coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name},
+		Status: coreV1.PodStatus{
+			ContainerStatuses: []coreV1.ContainerStatus{
+				{Name: "main", State: state},
+			},
+		},
+	}
+}
+
+func TestDetectPodsFailure(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		pods           []coreV1.Pod
+		expectedReason string
+		expectedFound  bool
+	}{
+		{
+			name:          "no pods",
+			pods:          nil,
+			expectedFound: false,
+		},
+		{
+			name: "pod is creating",
+			pods: []coreV1.Pod{
+				podWithContainerState("worker", coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: "ContainerCreating"}}),
+			},
+			expectedFound: false,
+		},
+		{
+			name: "image pull failure",
+			pods: []coreV1.Pod{
+				podWithContainerState("worker", coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}}),
+			},
+			expectedReason: loadTestV1.LoadTestReasonImagePullFailure,
+			expectedFound:  true,
+		},
+		{
+			name: "oom killed",
+			pods: []coreV1.Pod{
+				podWithContainerState("worker", coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}),
+			},
+			expectedReason: loadTestV1.LoadTestReasonOOMKilled,
+			expectedFound:  true,
+		},
+		{
+			name: "non zero exit code",
+			pods: []coreV1.Pod{
+				podWithContainerState("master", coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}),
+			},
+			expectedReason: loadTestV1.LoadTestReasonContainerFailed,
+			expectedFound:  true,
+		},
+		{
+			name: "deadline exceeded",
+			pods: []coreV1.Pod{
+				func() coreV1.Pod {
+					pod := podWithContainerState("worker", coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{Reason: "Error", ExitCode: 137}})
+					pod.Status.Phase = coreV1.PodFailed
+					pod.Status.Reason = "DeadlineExceeded"
+					return pod
+				}(),
+			},
+			expectedReason: loadTestV1.LoadTestReasonDeadlineExceeded,
+			expectedFound:  true,
+		},
+		{
+			name: "successful exit code",
+			pods: []coreV1.Pod{
+				podWithContainerState("master", coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{Reason: "Completed", ExitCode: 0}}),
+			},
+			expectedFound: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, message, found := backends.DetectPodsFailure(tt.pods)
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expectedReason, reason)
+			if found {
+				assert.NotEmpty(t, message)
+			}
+		})
+	}
+}
+
+func TestUpdateLoadTestStatusDetails(t *testing.T) {
+	startTime := metaV1.NewTime(time.Now().Add(-time.Hour))
+	completionTime := metaV1.NewTime(time.Now().Add(-time.Minute))
+
+	t.Run("running", func(t *testing.T) {
+		pod := podWithContainerState("worker", coreV1.ContainerState{Running: &coreV1.ContainerStateRunning{}})
+		pod.Status.StartTime = &startTime
+
+		status := &loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning}
+		backends.UpdateLoadTestStatusDetails(status, []coreV1.Pod{pod})
+
+		assert.Equal(t, startTime.Unix(), status.StartTime.Unix())
+		assert.Nil(t, status.CompletionTime)
+		assert.Equal(t, loadTestV1.LoadTestReasonRunning, status.Reason)
+		assert.True(t, meta.IsStatusConditionTrue(status.Conditions, loadTestV1.LoadTestConditionReady.String()))
+		assert.Nil(t, meta.FindStatusCondition(status.Conditions, loadTestV1.LoadTestConditionFailed.String()))
+	})
+
+	t.Run("finished", func(t *testing.T) {
+		status := &loadTestV1.LoadTestStatus{
+			Phase: loadTestV1.LoadTestFinished,
+			JobStatus: batchV1.JobStatus{
+				StartTime:      &startTime,
+				CompletionTime: &completionTime,
+			},
+		}
+		backends.UpdateLoadTestStatusDetails(status, nil)
+
+		assert.Equal(t, startTime.Unix(), status.StartTime.Unix())
+		assert.Equal(t, completionTime.Unix(), status.CompletionTime.Unix())
+		assert.Equal(t, loadTestV1.LoadTestReasonCompleted, status.Reason)
+		assert.True(t, meta.IsStatusConditionTrue(status.Conditions, loadTestV1.LoadTestConditionComplete.String()))
+		assert.True(t, meta.IsStatusConditionFalse(status.Conditions, loadTestV1.LoadTestConditionFailed.String()))
+	})
+
+	t.Run("errored by pod failure", func(t *testing.T) {
+		pod := podWithContainerState("worker", coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}})
+
+		status := &loadTestV1.LoadTestStatus{
+			Phase:  loadTestV1.LoadTestErrored,
+			Reason: loadTestV1.LoadTestReasonRunning,
+		}
+		backends.UpdateLoadTestStatusDetails(status, []coreV1.Pod{pod})
+
+		assert.NotNil(t, status.CompletionTime)
+		assert.Equal(t, loadTestV1.LoadTestReasonOOMKilled, status.Reason)
+		assert.NotEmpty(t, status.Message)
+
+		failed := meta.FindStatusCondition(status.Conditions, loadTestV1.LoadTestConditionFailed.String())
+		assert.NotNil(t, failed)
+		assert.Equal(t, metaV1.ConditionTrue, failed.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonOOMKilled, failed.Reason)
+	})
+
+	t.Run("errored by job failure", func(t *testing.T) {
+		status := &loadTestV1.LoadTestStatus{
+			Phase: loadTestV1.LoadTestErrored,
+			JobStatus: batchV1.JobStatus{
+				Conditions: []batchV1.JobCondition{
+					{Type: batchV1.JobFailed, Status: coreV1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+				},
+			},
+		}
+		backends.UpdateLoadTestStatusDetails(status, nil)
+
+		assert.Equal(t, loadTestV1.LoadTestReasonJobFailed, status.Reason)
+		assert.Contains(t, status.Message, "BackoffLimitExceeded")
+	})
+
+	t.Run("terminal details are recorded once", func(t *testing.T) {
+		status := &loadTestV1.LoadTestStatus{
+			Phase:          loadTestV1.LoadTestErrored,
+			CompletionTime: &completionTime,
+			Reason:         loadTestV1.LoadTestReasonImagePullFailure,
+			Message:        "image not found",
+		}
+		backends.UpdateLoadTestStatusDetails(status, nil)
+
+		assert.Equal(t, loadTestV1.LoadTestReasonImagePullFailure, status.Reason)
+		assert.Equal(t, "image not found", status.Message)
+		assert.Empty(t, status.Conditions)
+	})
+}
+
+func TestJobDeadlineExceeded(t *testing.T) {
+	_, ok := backends.JobDeadlineExceeded(batchV1.JobStatus{})
+	assert.False(t, ok)
+
+	_, ok = backends.JobDeadlineExceeded(batchV1.JobStatus{Conditions: []batchV1.JobCondition{
+		{Type: batchV1.JobFailed, Status: coreV1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+	}})
+	assert.False(t, ok)
+
+	message, ok := backends.JobDeadlineExceeded(batchV1.JobStatus{Conditions: []batchV1.JobCondition{
+		{Type: batchV1.JobFailed, Status: coreV1.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"},
+	}})
+	assert.True(t, ok)
+	assert.Equal(t, "job failed: Job was active longer than specified deadline", message)
+}
+
+func TestSetLoadTestErrored(t *testing.T) {
+	status := &loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning}
+
+	backends.SetLoadTestErrored(status, loadTestV1.LoadTestReasonDeadlineExceeded, "terminated")
+
+	assert.Equal(t, loadTestV1.LoadTestErrored, status.Phase)
+	assert.Equal(t, loadTestV1.LoadTestReasonDeadlineExceeded, status.Reason)
+	assert.NotNil(t, status.CompletionTime)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, loadTestV1.LoadTestConditionFailed.String()))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, loadTestV1.LoadTestConditionReady.String()))
+}
@@ -0,0 +1,12 @@
+package backends
+
+import (
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+// ValidateThresholds checks LoadTest thresholds can be evaluated once the loadtest has finished
+func ValidateThresholds(thresholds *loadTestV1.LoadTestThresholds) error {
+	_, err := results.ParseThresholds(thresholds)
+	return err
+}
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+)
+
+const (
+	// loadTestFinalizer blocks loadtest deletion until its logs and metadata are archived
+	loadTestFinalizer = "kangal.hellofresh.com/archive"
+
+	archiveLogsFileName     = "logs.tar.gz"
+	archiveMetadataFileName = "metadata.json"
+	redactedValue           = "<redacted>"
+	logsTruncatedNote       = "\n[kangal: log truncated to the first %d bytes]\n"
+
+	eventReasonArchived         = "Archived"
+	eventReasonArchiveFailed    = "ArchiveFailed"
+	eventReasonArchiveAbandoned = "ArchiveAbandoned"
+)
+
+// Archiver stores loadtest artifacts before the loadtest is deleted
+type Archiver interface {
+	// Archive stores content as a file of the given loadtest archive
+	Archive(ctx context.Context, loadTestName, fileName string, content []byte, contentType string) error
+}
+
+// loadTestMetadata is archived together with the loadtest logs
+type loadTestMetadata struct {
+	Name              string                    `json:"name"`
+	Labels            map[string]string         `json:"labels,omitempty"`
+	CreationTimestamp metaV1.Time               `json:"creationTimestamp"`
+	DeletionTimestamp *metaV1.Time              `json:"deletionTimestamp,omitempty"`
+	Spec              loadTestV1.LoadTestSpec   `json:"spec"`
+	Status            loadTestV1.LoadTestStatus `json:"status"`
+	TestFile          *report.FileDigest        `json:"testFile,omitempty"`
+	TestData          *report.FileDigest        `json:"testData,omitempty"`
+}
+
+// hasFinalizer returns true when the loadtest is protected by the archive finalizer
+func hasFinalizer(loadTest *loadTestV1.LoadTest) bool {
+	return slices.Contains(loadTest.GetFinalizers(), loadTestFinalizer)
+}
+
+// addFinalizer protects the loadtest from being deleted before it is archived
+func (c *Controller) addFinalizer(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	loadTest.SetFinalizers(append(loadTest.GetFinalizers(), loadTestFinalizer))
+
+	_, err := c.kangalClientSet.KangalV1().LoadTests().Update(ctx, loadTest, metaV1.UpdateOptions{})
+	return err
+}
+
+// finalizeLoadTest archives logs and metadata of a loadtest being deleted and then removes the finalizer
+func (c *Controller) finalizeLoadTest(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if !hasFinalizer(loadTest) {
+		return nil
+	}
+
+	logger := c.logger.With(zap.String("loadtest", loadTest.GetName()))
+
+	// archiving may have been disabled after the finalizer was added
+	if c.archiver != nil {
+		err := c.archiveLoadTest(ctx, loadTest)
+		switch {
+		case err == nil:
+			logger.Info("Archived loadtest logs and metadata")
+			c.recorder.Event(loadTest, coreV1.EventTypeNormal, eventReasonArchived, "Archived logs and metadata to the report bucket")
+		case archiveExpired(loadTest, c.cfg.ArchiveTimeout, time.Now()):
+			// unreachable report storage must not block loadtest deletion forever
+			logger.Error("Giving up archiving loadtest", zap.Error(err), zap.Duration("timeout", c.cfg.ArchiveTimeout))
+			c.recorder.Eventf(loadTest, coreV1.EventTypeWarning, eventReasonArchiveAbandoned, "Gave up archiving logs and metadata after %s: %v", c.cfg.ArchiveTimeout, err)
+		default:
+			logger.Error("Failed to archive loadtest", zap.Error(err))
+			c.recorder.Eventf(loadTest, coreV1.EventTypeWarning, eventReasonArchiveFailed, "Failed to archive logs and metadata: %v", err)
+			return err
+		}
+	}
+
+	loadTest.SetFinalizers(slices.DeleteFunc(loadTest.GetFinalizers(), func(finalizer string) bool {
+		return finalizer == loadTestFinalizer
+	}))
+
+	_, err := c.kangalClientSet.KangalV1().LoadTests().Update(ctx, loadTest, metaV1.UpdateOptions{})
+	return err
+}
+
+// archiveExpired returns true when the loadtest has been waiting for archiving longer than timeout
+func archiveExpired(loadTest *loadTestV1.LoadTest, timeout time.Duration, now time.Time) bool {
+	deletedAt := loadTest.GetDeletionTimestamp()
+	return deletedAt != nil && now.Sub(deletedAt.Time) >= timeout
+}
+
+// archiveLoadTest uploads logs of every pod in the loadtest namespace and the loadtest metadata
+func (c *Controller) archiveLoadTest(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if loadTest.Status.Namespace != "" {
+		logs, err := c.collectPodLogs(ctx, *loadTest)
+		if err != nil {
+			return fmt.Errorf("could not collect pod logs: %w", err)
+		}
+
+		err = c.archiver.Archive(ctx, loadTest.GetName(), archiveLogsFileName, logs, "application/gzip")
+		if err != nil {
+			return fmt.Errorf("could not archive pod logs: %w", err)
+		}
+	}
+
+	metadata, err := newLoadTestMetadata(loadTest)
+	if err != nil {
+		return fmt.Errorf("could not build metadata: %w", err)
+	}
+
+	err = c.archiver.Archive(ctx, loadTest.GetName(), archiveMetadataFileName, metadata, "application/json")
+	if err != nil {
+		return fmt.Errorf("could not archive metadata: %w", err)
+	}
+
+	return nil
+}
+
+// collectPodLogs returns gzipped tarball with logs of every loadtest container, one <pod>/<container>.log file each.
+// Logs longer than ArchiveLogsMaxBytes are cut to their first bytes followed by a truncation note
+func (c *Controller) collectPodLogs(ctx context.Context, loadTest loadTestV1.LoadTest) ([]byte, error) {
+	namespace := loadTest.Status.Namespace
+	maxBytes := c.cfg.ArchiveLogsMaxBytes
+	pods, err := c.kubeClientSet.CoreV1().Pods(namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: backends.LoadTestSelector(loadTest, ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for _, pod := range pods.Items {
+		containers := make([]coreV1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+		containers = append(containers, pod.Spec.InitContainers...)
+		containers = append(containers, pod.Spec.Containers...)
+
+		for _, container := range containers {
+			opts := &coreV1.PodLogOptions{Container: container.Name}
+			if maxBytes > 0 {
+				// one more byte tells whether the log was truncated
+				limitBytes := maxBytes + 1
+				opts.LimitBytes = &limitBytes
+			}
+
+			logs, err := c.kubeClientSet.CoreV1().Pods(namespace).GetLogs(pod.GetName(), opts).DoRaw(ctx)
+			if err != nil {
+				// container may have never started, keep the rest of the logs
+				c.logger.Warn("Could not get container logs",
+					zap.String("namespace", namespace),
+					zap.String("pod", pod.GetName()),
+					zap.String("container", container.Name),
+					zap.Error(err),
+				)
+				continue
+			}
+
+			if maxBytes > 0 && int64(len(logs)) > maxBytes {
+				c.logger.Warn("Truncated container logs",
+					zap.String("namespace", namespace),
+					zap.String("pod", pod.GetName()),
+					zap.String("container", container.Name),
+					zap.Int64("maxBytes", maxBytes),
+				)
+				logs = append(logs[:maxBytes:maxBytes], fmt.Sprintf(logsTruncatedNote, maxBytes)...)
+			}
+
+			err = tw.WriteHeader(&tar.Header{
+				Name:    fmt.Sprintf("%s/%s.log", pod.GetName(), container.Name),
+				Mode:    0644,
+				Size:    int64(len(logs)),
+				ModTime: time.Now(),
+			})
+			if err != nil {
+				return nil, err
+			}
+			if _, err := tw.Write(logs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// newLoadTestMetadata returns loadtest spec and status as JSON, environment variable values are redacted
+func newLoadTestMetadata(loadTest *loadTestV1.LoadTest) ([]byte, error) {
+	return json.Marshal(loadTestMetadata{
+		Name:              loadTest.GetName(),
+		Labels:            loadTest.GetLabels(),
+		CreationTimestamp: loadTest.GetCreationTimestamp(),
+		DeletionTimestamp: loadTest.GetDeletionTimestamp(),
+		Spec:              redactedSpec(loadTest),
+		Status:            loadTest.Status,
+		TestFile:          report.NewFileDigest(loadTest.Spec.TestFile),
+		TestData:          report.NewFileDigest(loadTest.Spec.TestData),
+	})
+}
+
+// redactedSpec returns a copy of the loadtest spec with environment variable values redacted and without
+// test file and test data, they often embed credentials and are identified by their digests instead
+func redactedSpec(loadTest *loadTestV1.LoadTest) loadTestV1.LoadTestSpec {
+	spec := *loadTest.Spec.DeepCopy()
+	spec.TestFile = nil
+	spec.TestData = nil
+	for key := range spec.EnvVars {
+		spec.EnvVars[key] = redactedValue
+	}
+	return spec
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+)
+
+type fakeArchiver struct {
+	files map[string][]byte
+	err   error
+}
+
+func (a *fakeArchiver) Archive(_ context.Context, loadTestName, fileName string, content []byte, _ string) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.files[loadTestName+"/"+fileName] = content
+	return nil
+}
+
+func newArchiveTestLoadTest() *loadTestV1.LoadTest {
+	now := metaV1.Now()
+	return &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:              "loadtest-name",
+			Finalizers:        []string{loadTestFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			Type:    loadTestV1.LoadTestTypeJMeter,
+			EnvVars: map[string]string{"API_TOKEN": "secret"},
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Phase:     loadTestV1.LoadTestFinished,
+			Namespace: "loadtest-name",
+		},
+	}
+}
+
+func TestFinalizeLoadTest(t *testing.T) {
+	loadTest := newArchiveTestLoadTest()
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-master", Namespace: "loadtest-name"},
+		Spec: coreV1.PodSpec{
+			Containers: []coreV1.Container{{Name: "jmeter"}},
+		},
+	}
+
+	archiver := &fakeArchiver{files: map[string][]byte{}}
+	kangalClientSet := fake.NewSimpleClientset(loadTest)
+	c := &Controller{
+		kubeClientSet:   k8sfake.NewSimpleClientset(pod),
+		kangalClientSet: kangalClientSet,
+		recorder:        record.NewFakeRecorder(10),
+		archiver:        archiver,
+		logger:          zaptest.NewLogger(t),
+	}
+
+	err := c.finalizeLoadTest(context.Background(), loadTest.DeepCopy())
+	require.NoError(t, err)
+
+	// logs tarball contains one file per container
+	require.Contains(t, archiver.files, "loadtest-name/logs.tar.gz")
+	gz, err := gzip.NewReader(bytes.NewReader(archiver.files["loadtest-name/logs.tar.gz"]))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "loadtest-master/jmeter.log", header.Name)
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+
+	// metadata has spec and status with redacted env vars
+	require.Contains(t, archiver.files, "loadtest-name/metadata.json")
+	var metadata loadTestMetadata
+	require.NoError(t, json.Unmarshal(archiver.files["loadtest-name/metadata.json"], &metadata))
+	assert.Equal(t, "loadtest-name", metadata.Name)
+	assert.Equal(t, redactedValue, metadata.Spec.EnvVars["API_TOKEN"])
+	assert.Equal(t, loadTestV1.LoadTestFinished, metadata.Status.Phase)
+
+	updated, err := kangalClientSet.KangalV1().LoadTests().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.False(t, hasFinalizer(updated))
+}
+
+func TestCollectPodLogsTruncated(t *testing.T) {
+	loadTest := newArchiveTestLoadTest()
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-master", Namespace: "loadtest-name"},
+		Spec: coreV1.PodSpec{
+			Containers: []coreV1.Container{{Name: "jmeter"}},
+		},
+	}
+
+	readLog := func(t *testing.T, maxBytes int64) string {
+		c := &Controller{
+			cfg:           Config{ArchiveLogsMaxBytes: maxBytes},
+			kubeClientSet: k8sfake.NewSimpleClientset(pod),
+			logger:        zaptest.NewLogger(t),
+		}
+
+		logs, err := c.collectPodLogs(context.Background(), *loadTest)
+		require.NoError(t, err)
+
+		gz, err := gzip.NewReader(bytes.NewReader(logs))
+		require.NoError(t, err)
+		tr := tar.NewReader(gz)
+		_, err = tr.Next()
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	// fake clientset returns "fake logs" for every container
+	assert.Equal(t, "fake logs", readLog(t, 0))
+	assert.Equal(t, "fake logs", readLog(t, 9))
+	assert.Equal(t, "fake\n[kangal: log truncated to the first 4 bytes]\n", readLog(t, 4))
+}
+
+func TestFinalizeLoadTestArchiveFailed(t *testing.T) {
+	loadTest := newArchiveTestLoadTest()
+
+	kangalClientSet := fake.NewSimpleClientset(loadTest)
+	c := &Controller{
+		kubeClientSet:   k8sfake.NewSimpleClientset(),
+		kangalClientSet: kangalClientSet,
+		recorder:        record.NewFakeRecorder(10),
+		cfg:             Config{ArchiveTimeout: time.Hour},
+		archiver:        &fakeArchiver{err: errors.New("bucket is not reachable")},
+		logger:          zaptest.NewLogger(t),
+	}
+
+	err := c.finalizeLoadTest(context.Background(), loadTest.DeepCopy())
+	assert.Error(t, err)
+
+	// finalizer is kept so archiving is retried
+	updated, err := kangalClientSet.KangalV1().LoadTests().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, hasFinalizer(updated))
+}
+
+func TestFinalizeLoadTestArchiveAbandoned(t *testing.T) {
+	loadTest := newArchiveTestLoadTest()
+	deletedAt := metaV1.NewTime(time.Now().Add(-time.Hour))
+	loadTest.DeletionTimestamp = &deletedAt
+
+	kangalClientSet := fake.NewSimpleClientset(loadTest)
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{
+		kubeClientSet:   k8sfake.NewSimpleClientset(),
+		kangalClientSet: kangalClientSet,
+		recorder:        recorder,
+		cfg:             Config{ArchiveTimeout: 15 * time.Minute},
+		archiver:        &fakeArchiver{err: errors.New("bucket is not reachable")},
+		logger:          zaptest.NewLogger(t),
+	}
+
+	err := c.finalizeLoadTest(context.Background(), loadTest.DeepCopy())
+	require.NoError(t, err)
+
+	// finalizer is removed so the loadtest is deleted without archive
+	updated, err := kangalClientSet.KangalV1().LoadTests().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.False(t, hasFinalizer(updated))
+
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning ArchiveAbandoned Gave up archiving logs and metadata after 15m0s")
+}
+
+func TestFinalizeLoadTestArchiveDisabled(t *testing.T) {
+	loadTest := newArchiveTestLoadTest()
+
+	kangalClientSet := fake.NewSimpleClientset(loadTest)
+	c := &Controller{
+		kubeClientSet:   k8sfake.NewSimpleClientset(),
+		kangalClientSet: kangalClientSet,
+		recorder:        record.NewFakeRecorder(10),
+		logger:          zaptest.NewLogger(t),
+	}
+
+	err := c.finalizeLoadTest(context.Background(), loadTest.DeepCopy())
+	require.NoError(t, err)
+
+	updated, err := kangalClientSet.KangalV1().LoadTests().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.False(t, hasFinalizer(updated))
+}
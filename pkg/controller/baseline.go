@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+// BaselineComparer compares loadtest results against the results of the most recent passing loadtest with the same type and tags
+type BaselineComparer interface {
+	// Compare compares and stores the comparison, report.ErrSummaryNotFound is returned when the loadtest results
+	// were not summarized yet, report.ErrBaselineNotFound when there is no baseline and report.ErrBaselineUntagged
+	// when the loadtest has no tags
+	Compare(ctx context.Context, loadTestName string, loadTestType loadTestV1.LoadTestType, tags loadTestV1.LoadTestTags) (report.CompareResult, error)
+}
+
+// compareBaseline compares results of a finished loadtest comparing to baseline once
+// and records the result in the Regressed condition
+func (c *Controller) compareBaseline(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	status := &loadTest.Status
+
+	if !loadTest.Spec.CompareBaseline || status.Phase != loadTestV1.LoadTestFinished ||
+		meta.FindStatusCondition(status.Conditions, loadTestV1.LoadTestConditionRegressed.String()) != nil {
+		return nil
+	}
+
+	if c.baselines == nil {
+		c.setBaselineUnavailable(loadTest, "load test history is not recorded")
+		return nil
+	}
+
+	result, err := c.baselines.Compare(ctx, loadTest.GetName(), loadTest.Spec.Type, loadTest.Spec.Tags)
+	switch {
+	case errors.Is(err, report.ErrSummaryNotFound):
+		// report may still be uploading, it is compared again on next sync
+		if status.CompletionTime == nil || time.Since(status.CompletionTime.Time) < reportUploadTimeout {
+			return nil
+		}
+		c.setBaselineUnavailable(loadTest, fmt.Sprintf("results summary was not stored within %s after the loadtest finished", reportUploadTimeout))
+		return nil
+	case errors.Is(err, report.ErrBaselineNotFound), errors.Is(err, report.ErrBaselineUntagged):
+		c.setBaselineUnavailable(loadTest, err.Error())
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not compare to baseline: %w", err)
+	}
+
+	message := regressionMessage(result)
+
+	c.logger.Info("Compared loadtest to baseline",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("baseline", result.Base),
+		zap.Bool("regressed", result.Regressed),
+	)
+
+	if result.Regressed {
+		setRegressedCondition(status, metaV1.ConditionTrue, loadTestV1.LoadTestReasonBaselineRegressed, message)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, loadTestV1.LoadTestReasonBaselineRegressed, message)
+		return nil
+	}
+
+	setRegressedCondition(status, metaV1.ConditionFalse, loadTestV1.LoadTestReasonBaselineMatched, message)
+	c.recorder.Event(loadTest, coreV1.EventTypeNormal, loadTestV1.LoadTestReasonBaselineMatched, message)
+	return nil
+}
+
+// regressionMessage lists the regressed total values, endpoints are listed by name only
+func regressionMessage(result report.CompareResult) string {
+	if !result.Regressed {
+		return fmt.Sprintf("Compared to %s, no regressions", result.Base)
+	}
+
+	total := result.Total
+	var regressions []string
+	if total.ErrorRate.Regressed {
+		regressions = append(regressions, fmt.Sprintf("errorRate %+.4f", total.ErrorRate.Change))
+	}
+	for _, delta := range []struct {
+		name string
+		results.Delta
+	}{{"throughput", total.Throughput}, {"p50", total.P50}, {"p95", total.P95}, {"p99", total.P99}} {
+		if delta.Regressed {
+			regressions = append(regressions, fmt.Sprintf("%s %+.2f%%", delta.name, delta.Relative*100))
+		}
+	}
+
+	for _, endpoint := range result.Endpoints {
+		if endpoint.Regressed {
+			regressions = append(regressions, fmt.Sprintf("endpoint %q", endpoint.Name))
+		}
+	}
+
+	return fmt.Sprintf("Compared to %s, regressed: %s", result.Base, strings.Join(regressions, ", "))
+}
+
+// setBaselineUnavailable records that the loadtest could not be compared, so it is not compared again
+func (c *Controller) setBaselineUnavailable(loadTest *loadTestV1.LoadTest, message string) {
+	setRegressedCondition(&loadTest.Status, metaV1.ConditionUnknown, loadTestV1.LoadTestReasonBaselineUnavailable, message)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, loadTestV1.LoadTestReasonBaselineUnavailable, message)
+}
+
+func setRegressedCondition(status *loadTestV1.LoadTestStatus, conditionStatus metaV1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metaV1.Condition{
+		Type:    loadTestV1.LoadTestConditionRegressed.String(),
+		Status:  conditionStatus,
+		Reason:  reason,
+		Message: message,
+	})
+}
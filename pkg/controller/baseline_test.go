@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+type fakeBaselineComparer struct {
+	result report.CompareResult
+	err    error
+	calls  int
+}
+
+func (b *fakeBaselineComparer) Compare(_ context.Context, _ string, _ loadTestV1.LoadTestType, _ loadTestV1.LoadTestTags) (report.CompareResult, error) {
+	b.calls++
+	return b.result, b.err
+}
+
+func TestCompareBaseline(t *testing.T) {
+	ctx := context.Background()
+
+	newLoadTest := func(phase loadTestV1.LoadTestPhase, finishedAgo time.Duration) *loadTestV1.LoadTest {
+		completionTime := metaV1.NewTime(time.Now().Add(-finishedAgo))
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+			Spec: loadTestV1.LoadTestSpec{
+				Type:            loadTestV1.LoadTestTypeK6,
+				Tags:            loadTestV1.LoadTestTags{"service": "checkout", "scenario": "soak"},
+				CompareBaseline: true,
+			},
+			Status: loadTestV1.LoadTestStatus{
+				Phase:          phase,
+				CompletionTime: &completionTime,
+			},
+		}
+	}
+
+	newController := func(baselines BaselineComparer) *Controller {
+		return &Controller{
+			recorder:  record.NewFakeRecorder(10),
+			baselines: baselines,
+			logger:    zaptest.NewLogger(t),
+		}
+	}
+
+	regressedCondition := func(loadTest *loadTestV1.LoadTest) *metaV1.Condition {
+		return meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionRegressed.String())
+	}
+
+	regressed := report.CompareResult{
+		Base:      "loadtest-baseline",
+		Candidate: "loadtest-name",
+		Comparison: results.Comparison{
+			Total: results.StatsComparison{
+				ErrorRate: results.Delta{Base: 0.01, Candidate: 0.05, Change: 0.04, Regressed: true},
+				P95:       results.Delta{Base: 200, Candidate: 300, Change: 100, Relative: 0.5, Regressed: true},
+				Regressed: true,
+			},
+			Endpoints: []results.EndpointComparison{
+				{Name: "GET /", StatsComparison: results.StatsComparison{Regressed: true}},
+				{Name: "POST /login"},
+			},
+			Regressed: true,
+		},
+	}
+
+	t.Run("regressed", func(t *testing.T) {
+		baselines := &fakeBaselineComparer{result: regressed}
+		c := newController(baselines)
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, c.compareBaseline(ctx, loadTest))
+		require.NoError(t, c.compareBaseline(ctx, loadTest))
+		assert.Equal(t, 1, baselines.calls)
+
+		condition := regressedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionTrue, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonBaselineRegressed, condition.Reason)
+		assert.Equal(t, `Compared to loadtest-baseline, regressed: errorRate +0.0400, p95 +50.00%, endpoint "GET /"`, condition.Message)
+	})
+
+	t.Run("matched", func(t *testing.T) {
+		c := newController(&fakeBaselineComparer{result: report.CompareResult{Base: "loadtest-baseline"}})
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, c.compareBaseline(ctx, loadTest))
+
+		condition := regressedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionFalse, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonBaselineMatched, condition.Reason)
+		assert.Equal(t, "Compared to loadtest-baseline, no regressions", condition.Message)
+	})
+
+	t.Run("not opted in", func(t *testing.T) {
+		baselines := &fakeBaselineComparer{result: regressed}
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+		loadTest.Spec.CompareBaseline = false
+
+		require.NoError(t, newController(baselines).compareBaseline(ctx, loadTest))
+		assert.Zero(t, baselines.calls)
+		assert.Nil(t, regressedCondition(loadTest))
+	})
+
+	t.Run("errored loadtest is not compared", func(t *testing.T) {
+		baselines := &fakeBaselineComparer{result: regressed}
+		loadTest := newLoadTest(loadTestV1.LoadTestErrored, time.Minute)
+
+		require.NoError(t, newController(baselines).compareBaseline(ctx, loadTest))
+		assert.Zero(t, baselines.calls)
+		assert.Nil(t, regressedCondition(loadTest))
+	})
+
+	t.Run("summary is waited for", func(t *testing.T) {
+		c := newController(&fakeBaselineComparer{err: report.ErrSummaryNotFound})
+
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+		require.NoError(t, c.compareBaseline(ctx, loadTest))
+		assert.Nil(t, regressedCondition(loadTest))
+
+		loadTest = newLoadTest(loadTestV1.LoadTestFinished, reportUploadTimeout+time.Minute)
+		require.NoError(t, c.compareBaseline(ctx, loadTest))
+		condition := regressedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionUnknown, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonBaselineUnavailable, condition.Reason)
+	})
+
+	t.Run("baseline not found", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, newController(&fakeBaselineComparer{err: report.ErrBaselineNotFound}).compareBaseline(ctx, loadTest))
+
+		condition := regressedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionUnknown, condition.Status)
+		assert.Equal(t, report.ErrBaselineNotFound.Error(), condition.Message)
+	})
+
+	t.Run("untagged loadtest is not compared", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, newController(&fakeBaselineComparer{err: report.ErrBaselineUntagged}).compareBaseline(ctx, loadTest))
+
+		condition := regressedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionUnknown, condition.Status)
+		assert.Equal(t, report.ErrBaselineUntagged.Error(), condition.Message)
+	})
+
+	t.Run("history is not recorded", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, newController(nil).compareBaseline(ctx, loadTest))
+
+		condition := regressedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionUnknown, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonBaselineUnavailable, condition.Reason)
+	})
+
+	t.Run("store error is retried", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		err := newController(&fakeBaselineComparer{err: errors.New("connection refused")}).compareBaseline(ctx, loadTest)
+		assert.Error(t, err)
+		assert.Nil(t, regressedCondition(loadTest))
+	})
+}
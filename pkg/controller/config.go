@@ -3,8 +3,11 @@ package controller
 import (
 	"time"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	"github.com/hellofresh/kangal/pkg/core/observability"
 	"github.com/hellofresh/kangal/pkg/kubernetes"
+	"github.com/hellofresh/kangal/pkg/report"
+	"github.com/hellofresh/kangal/pkg/results"
 )
 
 // Config is the possible Kangal Controller configurations
@@ -19,12 +22,67 @@ type Config struct {
 	// S3 compatible configuration access keys and endpoints needed to store load test reports
 	KangalProxyURL string `envconfig:"KANGAL_PROXY_URL" default:""`
 
+	// ArchiveLogs enables archiving of load test logs and metadata to the report bucket before load test is deleted
+	ArchiveLogs bool `envconfig:"ARCHIVE_LOGS" default:"false"`
+	// ArchiveTimeout is the time since load test deletion after which failing archiving is given up and load test is deleted
+	ArchiveTimeout time.Duration `envconfig:"ARCHIVE_TIMEOUT" default:"15m"`
+	// ArchiveLogsMaxBytes limits the archived log of each container, longer logs keep their beginning, 0 disables the limit
+	ArchiveLogsMaxBytes int64 `envconfig:"ARCHIVE_LOGS_MAX_BYTES" default:"10485760"`
+	// RecordHistory enables storing metadata of completed load tests in the report storage history catalog
+	RecordHistory bool `envconfig:"RECORD_HISTORY" default:"false"`
+	Report        report.Config
+	// Compare tolerances of load tests comparing to baseline
+	Compare results.Tolerances
+
 	// KubeClientTimeout specifies timeout for each operation done by kube client
 	KubeClientTimeout time.Duration `envconfig:"KUBE_CLIENT_TIMEOUT" default:"5s"`
 
 	// SyncHandlerTimeout specifies the time limit for each sync operation
 	SyncHandlerTimeout time.Duration `envconfig:"SYNC_HANDLER_TIMEOUT" default:"60s"`
 
+	// LeaderElection enables Lease based leader election, so multiple controller replicas can be run with only one active
+	LeaderElection              bool          `envconfig:"LEADER_ELECTION" default:"false"`
+	LeaderElectionID            string        `envconfig:"LEADER_ELECTION_ID" default:"kangal-controller"`
+	LeaderElectionNamespace     string        `envconfig:"LEADER_ELECTION_NAMESPACE" default:"default"`
+	LeaderElectionIdentity      string        `envconfig:"POD_NAME"`
+	LeaderElectionLeaseDuration time.Duration `envconfig:"LEADER_ELECTION_LEASE_DURATION" default:"15s"`
+	LeaderElectionRenewDeadline time.Duration `envconfig:"LEADER_ELECTION_RENEW_DEADLINE" default:"10s"`
+	LeaderElectionRetryPeriod   time.Duration `envconfig:"LEADER_ELECTION_RETRY_PERIOD" default:"2s"`
+
+	// Runtime limits how long load tests can run, jobs get an active deadline and longer running load tests are terminated
+	Runtime backends.RuntimeLimits
+
+	// Notifications configures webhooks notified once loadtests are finished or errored
+	Notifications NotificationsConfig
+
+	// MetricsExport is the destination load generators push live metrics to while load tests run
+	MetricsExport backends.MetricsExport
+
+	// SingleNamespace runs all load tests in this existing namespace instead of creating a namespace per load test
+	SingleNamespace string `envconfig:"SINGLE_NAMESPACE"`
+
+	// SecretEnvVarsNamespace is the namespace of Secrets load tests can reference in secret env vars
+	SecretEnvVarsNamespace string `envconfig:"SECRET_ENV_VARS_NAMESPACE"`
+
+	// Namespace guardrails templates, each file contains the spec of the object in YAML
+	ResourceQuotaTemplate string     `envconfig:"NAMESPACE_RESOURCE_QUOTA_TEMPLATE"`
+	LimitRangeTemplate    string     `envconfig:"NAMESPACE_LIMIT_RANGE_TEMPLATE"`
+	NetworkPolicy         bool       `envconfig:"NAMESPACE_NETWORK_POLICY" default:"false"`
+	NetworkPolicyTemplate string     `envconfig:"NAMESPACE_NETWORK_POLICY_TEMPLATE"`
+	Guardrails            Guardrails `ignored:"true"`
+
+	// NetworkPolicyProxyNamespace is the namespace of the proxy pods, the default network policy allows egress to
+	// pods matching NetworkPolicyProxySelector in it instead of the resolved KANGAL_PROXY_URL addresses
+	NetworkPolicyProxyNamespace string `envconfig:"NAMESPACE_NETWORK_POLICY_PROXY_NAMESPACE"`
+	NetworkPolicyProxySelector  string `envconfig:"NAMESPACE_NETWORK_POLICY_PROXY_SELECTOR" default:"app=kangal-proxy"`
+	// NetworkPolicyTargetCIDRs are the CIDRs the default network policy allows egress to instead of the resolved
+	// loadtest targetURL addresses
+	NetworkPolicyTargetCIDRs []string `envconfig:"NAMESPACE_NETWORK_POLICY_TARGET_CIDRS"`
+	// NetworkPolicyDNSNamespace is the namespace of the cluster DNS pods, the default network policy allows DNS
+	// egress only to pods matching NetworkPolicyDNSSelector in it, empty namespace allows DNS egress to any address
+	NetworkPolicyDNSNamespace string `envconfig:"NAMESPACE_NETWORK_POLICY_DNS_NAMESPACE" default:"kube-system"`
+	NetworkPolicyDNSSelector  string `envconfig:"NAMESPACE_NETWORK_POLICY_DNS_SELECTOR" default:"k8s-app=kube-dns"`
+
 	MasterURL            string
 	KubeConfig           string
 	NamespaceAnnotations map[string]string
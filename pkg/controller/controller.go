@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 
 	"go.uber.org/zap"
@@ -22,35 +23,70 @@ type Runner struct {
 	KubeInformer   kubeInformers.SharedInformerFactory
 	KangalInformer externalversions.SharedInformerFactory
 	StatsReporter  *MetricsReporter
+	Archiver       Archiver
+	ReportReader   ReportReader
+	History        HistoryRecorder
+	Baselines      BaselineComparer
 }
 
 // Run runs an instance of kubernetes kubeController
 func Run(cfg Config, rr Runner) error {
 	stopCh := make(chan struct{})
 
+	identity, err := leaderElectionIdentity(cfg)
+	if err != nil {
+		return fmt.Errorf("could not get leader election identity: %w", err)
+	}
+	leaderState := NewLeaderState(identity)
+
+	recorder := NewEventRecorder(rr.KubeClient, rr.Logger)
+
 	registry := backends.New(
 		backends.WithLogger(rr.Logger),
+		backends.WithEventRecorder(recorder),
 		backends.WithKubeClientSet(rr.KubeClient),
 		backends.WithKangalClientSet(rr.KangalClient),
 		backends.WithNamespaceLister(rr.KubeInformer.Core().V1().Namespaces().Lister()),
 		backends.WithPodAnnotations(cfg.PodAnnotations),
 		backends.WithNodeSelector(cfg.NodeSelectors),
 		backends.WithTolerations(cfg.Tolerations.KubeToleration()),
+		backends.WithRuntimeLimits(cfg.Runtime),
+		backends.WithMetricsExport(cfg.MetricsExport),
 	)
 
-	c := NewController(cfg, rr.KubeClient, rr.KangalClient, rr.KubeInformer, rr.KangalInformer, *rr.StatsReporter, registry, rr.Logger)
+	c := NewController(cfg, rr.KubeClient, rr.KangalClient, rr.KubeInformer, rr.KangalInformer, *rr.StatsReporter, registry, recorder, rr.Archiver, rr.ReportReader, rr.History, rr.Baselines, rr.Logger)
 
-	// notice that there is no need to run Start methods in a separate goroutine. (i.e. go kubeInformerFactory.Start(stopCh)
-	// Start method is non-blocking and runs all registered informers in a dedicated goroutine.
-	rr.KangalInformer.Start(stopCh)
-	rr.KubeInformer.Start(stopCh)
-
-	if err := RunMetricsServer(cfg, rr, stopCh); err != nil {
+	if err := RunMetricsServer(cfg, rr, leaderState, stopCh); err != nil {
 		return fmt.Errorf("could not initialise Metrics Server: %w", err)
 	}
 
-	if err := c.Run(1, stopCh); err != nil {
-		return fmt.Errorf("error running kubeController: %w", err)
+	run := func(stopCh <-chan struct{}) error {
+		// notice that there is no need to run Start methods in a separate goroutine. (i.e. go kubeInformerFactory.Start(stopCh)
+		// Start method is non-blocking and runs all registered informers in a dedicated goroutine.
+		rr.KangalInformer.Start(stopCh)
+		rr.KubeInformer.Start(stopCh)
+
+		if err := c.Run(1, stopCh); err != nil {
+			return fmt.Errorf("error running kubeController: %w", err)
+		}
+		return nil
 	}
-	return nil
+
+	if !cfg.LeaderElection {
+		leaderState.setLeader(identity)
+		rr.StatsReporter.isLeaderStat.Add(context.Background(), 1)
+		return run(stopCh)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return runWithLeaderElection(ctx, cfg, rr.KubeClient, leaderState, *rr.StatsReporter, rr.Logger, run)
 }
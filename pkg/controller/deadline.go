@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	eventReasonDeadlineExceeded = "DeadlineExceeded"
+
+	suspendJobPatch = `{"spec":{"suspend":true}}`
+)
+
+// enforceDeadline terminates loadtest pods and marks the loadtest as errored once it runs longer than its deadline.
+// Jobs activeDeadlineSeconds stop only active jobs, the watchdog covers pending pods and backends which
+// report failed jobs as finished
+func (c *Controller) enforceDeadline(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	status := &loadTest.Status
+
+	switch status.Phase {
+	case loadTestV1.LoadTestErrored:
+		return nil
+	case loadTestV1.LoadTestFinished:
+		if message, ok := backends.JobDeadlineExceeded(status.JobStatus); ok {
+			backends.SetLoadTestErrored(status, loadTestV1.LoadTestReasonDeadlineExceeded, message)
+		}
+		return nil
+	}
+
+	deadline := c.cfg.Runtime.Deadline(loadTest.Spec)
+	if deadline == 0 {
+		return nil
+	}
+
+	startTime := loadTest.GetCreationTimestamp()
+	if status.StartTime != nil {
+		startTime = *status.StartTime
+	}
+	if time.Since(startTime.Time) <= deadline {
+		return nil
+	}
+
+	c.logger.Info("Terminating loadtest due to exceeded deadline",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("phase", status.Phase.String()),
+		zap.Duration("deadline", deadline),
+	)
+
+	if err := c.terminateLoadTest(ctx, loadTest); err != nil {
+		return fmt.Errorf("could not terminate loadtest: %w", err)
+	}
+
+	message := fmt.Sprintf("loadtest was terminated after running longer than %s", deadline)
+	backends.SetLoadTestErrored(status, loadTestV1.LoadTestReasonDeadlineExceeded, message)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, eventReasonDeadlineExceeded, message)
+
+	return nil
+}
+
+// terminateLoadTest suspends loadtest jobs, so their pods are not recreated, and deletes loadtest pods
+func (c *Controller) terminateLoadTest(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	namespace := loadTest.Status.Namespace
+	if namespace == "" {
+		return nil
+	}
+
+	listOptions := metaV1.ListOptions{LabelSelector: backends.LoadTestSelector(*loadTest, "")}
+
+	jobs, err := c.kubeClientSet.BatchV1().Jobs(namespace).List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs.Items {
+		if job.Spec.Suspend != nil && *job.Spec.Suspend {
+			continue
+		}
+		_, err := c.kubeClientSet.BatchV1().Jobs(namespace).Patch(ctx, job.GetName(), types.MergePatchType, []byte(suspendJobPatch), metaV1.PatchOptions{})
+		if err != nil && !k8sAPIErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	pods, err := c.kubeClientSet.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		err := c.kubeClientSet.CoreV1().Pods(namespace).Delete(ctx, pod.GetName(), metaV1.DeleteOptions{})
+		if err != nil && !k8sAPIErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestEnforceDeadline(t *testing.T) {
+	ctx := context.Background()
+	limits := backends.RuntimeLimits{GracePeriod: time.Minute, MaxRuntime: time.Hour}
+
+	newLoadTest := func(phase loadTestV1.LoadTestPhase, startedAgo time.Duration) *loadTestV1.LoadTest {
+		startTime := metaV1.NewTime(time.Now().Add(-startedAgo))
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+			Spec:       loadTestV1.LoadTestSpec{Duration: 10 * time.Minute},
+			Status: loadTestV1.LoadTestStatus{
+				Phase:     phase,
+				Namespace: "loadtest-name",
+				StartTime: &startTime,
+			},
+		}
+	}
+
+	newController := func(kubeClientSet *k8sfake.Clientset) *Controller {
+		return &Controller{
+			cfg:           Config{Runtime: limits},
+			kubeClientSet: kubeClientSet,
+			recorder:      record.NewFakeRecorder(10),
+			logger:        zaptest.NewLogger(t),
+		}
+	}
+
+	t.Run("within deadline", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestRunning, 5*time.Minute)
+
+		require.NoError(t, newController(k8sfake.NewSimpleClientset()).enforceDeadline(ctx, loadTest))
+		assert.Equal(t, loadTestV1.LoadTestRunning, loadTest.Status.Phase)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestRunning, 20*time.Minute)
+		kubeClientSet := k8sfake.NewSimpleClientset(
+			&batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-job", Namespace: "loadtest-name"}},
+			&coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-job-abc", Namespace: "loadtest-name"}},
+		)
+
+		require.NoError(t, newController(kubeClientSet).enforceDeadline(ctx, loadTest))
+		assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+		assert.Equal(t, loadTestV1.LoadTestReasonDeadlineExceeded, loadTest.Status.Reason)
+		assert.NotNil(t, loadTest.Status.CompletionTime)
+
+		job, err := kubeClientSet.BatchV1().Jobs("loadtest-name").Get(ctx, "loadtest-job", metaV1.GetOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, job.Spec.Suspend)
+		assert.True(t, *job.Spec.Suspend)
+
+		pods, err := kubeClientSet.CoreV1().Pods("loadtest-name").List(ctx, metaV1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, pods.Items)
+	})
+
+	t.Run("finished with job deadline exceeded", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, 20*time.Minute)
+		loadTest.Status.JobStatus.Conditions = []batchV1.JobCondition{
+			{Type: batchV1.JobFailed, Status: coreV1.ConditionTrue, Reason: "DeadlineExceeded"},
+		}
+
+		require.NoError(t, newController(k8sfake.NewSimpleClientset()).enforceDeadline(ctx, loadTest))
+		assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+		assert.Equal(t, loadTestV1.LoadTestReasonDeadlineExceeded, loadTest.Status.Reason)
+	})
+
+	t.Run("finished", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, 20*time.Minute)
+
+		require.NoError(t, newController(k8sfake.NewSimpleClientset()).enforceDeadline(ctx, loadTest))
+		assert.Equal(t, loadTestV1.LoadTestFinished, loadTest.Status.Phase)
+	})
+}
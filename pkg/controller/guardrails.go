@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	guardrailsName = "kangal-loadtest"
+	dnsPort        = 53
+)
+
+// Guardrails are the objects created in each loadtest namespace to limit what a loadtest can consume and reach
+type Guardrails struct {
+	// ResourceQuota hard values are per loadtest pod, they are multiplied by the loadtest pods count
+	ResourceQuota *coreV1.ResourceQuotaSpec
+	LimitRange    *coreV1.LimitRangeSpec
+	// NetworkPolicy enables namespace network policy, when NetworkPolicyTemplate is not set egress
+	// is allowed only inside the namespace, to DNS, to loadtest target host, to the proxy and to the metrics export URL
+	NetworkPolicy         bool
+	NetworkPolicyTemplate *networkingV1.NetworkPolicySpec
+	// ProxyPeer selects the proxy pods, the proxy is reached through its service and the policy applies to
+	// the pod addresses, so resolved KANGAL_PROXY_URL addresses only work for proxies exposed outside the cluster
+	ProxyPeer *networkingV1.NetworkPolicyPeer
+	// TargetCIDRs replace the resolved loadtest targetURL addresses, resolved addresses are pinned when the namespace
+	// is created and miss targets changing addresses, e.g. behind a CDN or a cloud load balancer.
+	// Without both, loadtests reach no target, only their own pods, DNS and the proxy
+	TargetCIDRs []string
+	// DNSPeer selects the cluster DNS pods, DNS egress is allowed to any address when it is not set
+	DNSPeer *networkingV1.NetworkPolicyPeer
+}
+
+// LoadGuardrails reads guardrails templates configured by admin
+func LoadGuardrails(cfg Config) (Guardrails, error) {
+	guardrails := Guardrails{
+		NetworkPolicy: cfg.NetworkPolicy,
+	}
+
+	if cfg.ResourceQuotaTemplate != "" {
+		guardrails.ResourceQuota = &coreV1.ResourceQuotaSpec{}
+		if err := readTemplate(cfg.ResourceQuotaTemplate, guardrails.ResourceQuota); err != nil {
+			return Guardrails{}, err
+		}
+	}
+
+	if cfg.LimitRangeTemplate != "" {
+		guardrails.LimitRange = &coreV1.LimitRangeSpec{}
+		if err := readTemplate(cfg.LimitRangeTemplate, guardrails.LimitRange); err != nil {
+			return Guardrails{}, err
+		}
+	}
+
+	if cfg.NetworkPolicyProxyNamespace != "" {
+		peer, err := namespacePodsPeer(cfg.NetworkPolicyProxyNamespace, cfg.NetworkPolicyProxySelector)
+		if err != nil {
+			return Guardrails{}, fmt.Errorf("could not parse proxy selector %q: %w", cfg.NetworkPolicyProxySelector, err)
+		}
+		guardrails.ProxyPeer = peer
+	}
+
+	if cfg.NetworkPolicyDNSNamespace != "" {
+		peer, err := namespacePodsPeer(cfg.NetworkPolicyDNSNamespace, cfg.NetworkPolicyDNSSelector)
+		if err != nil {
+			return Guardrails{}, fmt.Errorf("could not parse DNS selector %q: %w", cfg.NetworkPolicyDNSSelector, err)
+		}
+		guardrails.DNSPeer = peer
+	}
+
+	for _, cidr := range cfg.NetworkPolicyTargetCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return Guardrails{}, fmt.Errorf("invalid target CIDR %q: %w", cidr, err)
+		}
+		guardrails.TargetCIDRs = append(guardrails.TargetCIDRs, cidr)
+	}
+
+	if cfg.NetworkPolicyTemplate != "" {
+		guardrails.NetworkPolicyTemplate = &networkingV1.NetworkPolicySpec{}
+		if err := readTemplate(cfg.NetworkPolicyTemplate, guardrails.NetworkPolicyTemplate); err != nil {
+			return Guardrails{}, err
+		}
+	}
+
+	return guardrails, nil
+}
+
+// namespacePodsPeer selects the pods matching the label selector in the namespace
+func namespacePodsPeer(namespace, podSelector string) (*networkingV1.NetworkPolicyPeer, error) {
+	selector, err := metaV1.ParseToLabelSelector(podSelector)
+	if err != nil {
+		return nil, err
+	}
+	return &networkingV1.NetworkPolicyPeer{
+		NamespaceSelector: &metaV1.LabelSelector{
+			MatchLabels: map[string]string{coreV1.LabelMetadataName: namespace},
+		},
+		PodSelector: selector,
+	}, nil
+}
+
+func readTemplate(path string, into interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read template %s: %w", path, err)
+	}
+
+	if err := yaml.UnmarshalStrict(content, into); err != nil {
+		return fmt.Errorf("could not parse template %s: %w", path, err)
+	}
+	return nil
+}
+
+// createNamespaceGuardrails creates guardrails in loadtest namespace, existing ones are kept as they are
+func (c *Controller) createNamespaceGuardrails(ctx context.Context, loadtest *loadTestV1.LoadTest, namespace string) error {
+	guardrails := c.cfg.Guardrails
+
+	if guardrails.ResourceQuota != nil {
+		quota := newResourceQuota(loadtest, *guardrails.ResourceQuota)
+		_, err := c.kubeClientSet.CoreV1().ResourceQuotas(namespace).Create(ctx, quota, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			return fmt.Errorf("could not create resource quota: %w", err)
+		}
+	}
+
+	if guardrails.LimitRange != nil {
+		limitRange := &coreV1.LimitRange{
+			ObjectMeta: metaV1.ObjectMeta{Name: guardrailsName},
+			Spec:       *guardrails.LimitRange.DeepCopy(),
+		}
+		_, err := c.kubeClientSet.CoreV1().LimitRanges(namespace).Create(ctx, limitRange, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			return fmt.Errorf("could not create limit range: %w", err)
+		}
+	}
+
+	if guardrails.NetworkPolicy {
+		networkPolicy, err := c.newNetworkPolicy(ctx, loadtest)
+		if err != nil {
+			return err
+		}
+		_, err = c.kubeClientSet.NetworkingV1().NetworkPolicies(namespace).Create(ctx, networkPolicy, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			return fmt.Errorf("could not create network policy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newResourceQuota creates resource quota with template hard values multiplied by loadtest pods count, master included
+func newResourceQuota(loadtest *loadTestV1.LoadTest, template coreV1.ResourceQuotaSpec) *coreV1.ResourceQuota {
+	pods := int64(1)
+	if loadtest.Spec.DistributedPods != nil {
+		pods += int64(*loadtest.Spec.DistributedPods)
+	}
+
+	spec := *template.DeepCopy()
+	for name, quantity := range spec.Hard {
+		spec.Hard[name] = *resource.NewMilliQuantity(quantity.MilliValue()*pods, quantity.Format)
+	}
+
+	return &coreV1.ResourceQuota{
+		ObjectMeta: metaV1.ObjectMeta{Name: guardrailsName},
+		Spec:       spec,
+	}
+}
+
+// newNetworkPolicy creates network policy from admin template or the default egress only policy
+func (c *Controller) newNetworkPolicy(ctx context.Context, loadtest *loadTestV1.LoadTest) (*networkingV1.NetworkPolicy, error) {
+	networkPolicy := &networkingV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{Name: guardrailsName},
+	}
+
+	if c.cfg.Guardrails.NetworkPolicyTemplate != nil {
+		networkPolicy.Spec = *c.cfg.Guardrails.NetworkPolicyTemplate.DeepCopy()
+		return networkPolicy, nil
+	}
+
+	udp := coreV1.ProtocolUDP
+	tcp := coreV1.ProtocolTCP
+	port := intstr.FromInt32(dnsPort)
+
+	guardrails := c.cfg.Guardrails
+
+	// target and proxy hosts are resolved by name
+	dns := networkingV1.NetworkPolicyEgressRule{
+		Ports: []networkingV1.NetworkPolicyPort{{Protocol: &udp, Port: &port}, {Protocol: &tcp, Port: &port}},
+	}
+	if guardrails.DNSPeer != nil {
+		dns.To = []networkingV1.NetworkPolicyPeer{*guardrails.DNSPeer.DeepCopy()}
+	}
+
+	networkPolicy.Spec = networkingV1.NetworkPolicySpec{
+		PodSelector: metaV1.LabelSelector{},
+		PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeEgress},
+		Egress: []networkingV1.NetworkPolicyEgressRule{
+			// loadtest pods talk to each other, e.g. master and workers
+			{To: []networkingV1.NetworkPolicyPeer{{PodSelector: &metaV1.LabelSelector{}}}},
+			dns,
+		},
+	}
+
+	// loadtests without targetURL, e.g. JMeter test plans with their own hosts, reach no target
+	// unless target CIDRs are set
+	switch {
+	case len(guardrails.TargetCIDRs) > 0:
+		peers := make([]networkingV1.NetworkPolicyPeer, 0, len(guardrails.TargetCIDRs))
+		for _, cidr := range guardrails.TargetCIDRs {
+			peers = append(peers, networkingV1.NetworkPolicyPeer{IPBlock: &networkingV1.IPBlock{CIDR: cidr}})
+		}
+		networkPolicy.Spec.Egress = append(networkPolicy.Spec.Egress, networkingV1.NetworkPolicyEgressRule{To: peers})
+	case loadtest.Spec.TargetURL != "":
+		peers, err := ipBlockPeers(ctx, loadtest.Spec.TargetURL)
+		if err != nil {
+			return nil, err
+		}
+		networkPolicy.Spec.Egress = append(networkPolicy.Spec.Egress, networkingV1.NetworkPolicyEgressRule{To: peers})
+	default:
+		c.logger.Warn("Network policy allows no load test target, load test has no targetURL and no target CIDRs are set",
+			zap.String("loadtest", loadtest.GetName()))
+	}
+
+	// report upload goes to the proxy
+	switch {
+	case guardrails.ProxyPeer != nil:
+		networkPolicy.Spec.Egress = append(networkPolicy.Spec.Egress, networkingV1.NetworkPolicyEgressRule{
+			To: []networkingV1.NetworkPolicyPeer{*guardrails.ProxyPeer.DeepCopy()},
+		})
+	case c.cfg.KangalProxyURL != "":
+		peers, err := ipBlockPeers(ctx, c.cfg.KangalProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		networkPolicy.Spec.Egress = append(networkPolicy.Spec.Egress, networkingV1.NetworkPolicyEgressRule{To: peers})
+	}
+
+	// live metrics are pushed by load generators and their sidecars
+	if c.cfg.MetricsExport.Enabled() {
+		peers, err := ipBlockPeers(ctx, c.cfg.MetricsExport.URL)
+		if err != nil {
+			return nil, err
+		}
+		networkPolicy.Spec.Egress = append(networkPolicy.Spec.Egress, networkingV1.NetworkPolicyEgressRule{To: peers})
+	}
+
+	c.logger.Debug("Built default network policy", zap.String("loadtest", loadtest.GetName()), zap.Int("rules", len(networkPolicy.Spec.Egress)))
+
+	return networkPolicy, nil
+}
+
+// ipBlockPeers resolves URL host to network policy peers, one per host address
+func ipBlockPeers(ctx context.Context, rawURL string) ([]networkingV1.NetworkPolicyPeer, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse url %q: %w", rawURL, err)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve host %q: %w", u.Hostname(), err)
+	}
+
+	peers := make([]networkingV1.NetworkPolicyPeer, 0, len(addrs))
+	for _, addr := range addrs {
+		bits := 128
+		if addr.IP.To4() != nil {
+			bits = 32
+		}
+		peers = append(peers, networkingV1.NetworkPolicyPeer{
+			IPBlock: &networkingV1.IPBlock{CIDR: fmt.Sprintf("%s/%d", addr.IP.String(), bits)},
+		})
+	}
+
+	return peers, nil
+}
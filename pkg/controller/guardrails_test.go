@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "template.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadGuardrails(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		guardrails, err := LoadGuardrails(Config{})
+		require.NoError(t, err)
+		assert.Equal(t, Guardrails{}, guardrails)
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		guardrails, err := LoadGuardrails(Config{
+			ResourceQuotaTemplate: writeTemplate(t, "hard:\n  limits.cpu: \"2\"\n  pods: \"1\"\n"),
+			LimitRangeTemplate:    writeTemplate(t, "limits:\n- type: Container\n  max:\n    cpu: \"4\"\n"),
+			NetworkPolicy:         true,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, guardrails.ResourceQuota)
+		assert.Equal(t, resource.MustParse("2"), guardrails.ResourceQuota.Hard[coreV1.ResourceLimitsCPU])
+		require.NotNil(t, guardrails.LimitRange)
+		assert.Equal(t, coreV1.LimitTypeContainer, guardrails.LimitRange.Limits[0].Type)
+		assert.True(t, guardrails.NetworkPolicy)
+		assert.Nil(t, guardrails.NetworkPolicyTemplate)
+	})
+
+	t.Run("network policy peers", func(t *testing.T) {
+		guardrails, err := LoadGuardrails(Config{
+			NetworkPolicy:               true,
+			NetworkPolicyProxyNamespace: "kangal",
+			NetworkPolicyProxySelector:  "app=kangal-proxy",
+			NetworkPolicyTargetCIDRs:    []string{"10.1.0.0/16"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, guardrails.ProxyPeer)
+		assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": "kangal"}, guardrails.ProxyPeer.NamespaceSelector.MatchLabels)
+		assert.Equal(t, map[string]string{"app": "kangal-proxy"}, guardrails.ProxyPeer.PodSelector.MatchLabels)
+		assert.Equal(t, []string{"10.1.0.0/16"}, guardrails.TargetCIDRs)
+
+		_, err = LoadGuardrails(Config{NetworkPolicyTargetCIDRs: []string{"10.1.0.0"}})
+		assert.ErrorContains(t, err, "invalid target CIDR")
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := LoadGuardrails(Config{ResourceQuotaTemplate: writeTemplate(t, "hardd:\n  pods: \"1\"\n")})
+		assert.ErrorContains(t, err, "could not parse template")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadGuardrails(Config{LimitRangeTemplate: filepath.Join(t.TempDir(), "missing.yaml")})
+		assert.ErrorContains(t, err, "could not read template")
+	})
+}
+
+func TestCheckOrCreateNamespaceGuardrails(t *testing.T) {
+	ctx := context.Background()
+	distributedPods := int32(3)
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TargetURL:       "http://10.1.2.3:8080/api",
+		},
+	}
+
+	kubeClientSet := k8sfake.NewSimpleClientset()
+	c := &Controller{
+		cfg: Config{
+			KangalProxyURL: "http://192.168.0.10",
+			Guardrails: Guardrails{
+				ResourceQuota: &coreV1.ResourceQuotaSpec{Hard: coreV1.ResourceList{
+					coreV1.ResourceLimitsCPU:    resource.MustParse("500m"),
+					coreV1.ResourceLimitsMemory: resource.MustParse("1Gi"),
+					coreV1.ResourcePods:         resource.MustParse("1"),
+				}},
+				LimitRange:    &coreV1.LimitRangeSpec{Limits: []coreV1.LimitRangeItem{{Type: coreV1.LimitTypeContainer}}},
+				NetworkPolicy: true,
+			},
+		},
+		kubeClientSet: kubeClientSet,
+		recorder:      record.NewFakeRecorder(10),
+		logger:        zaptest.NewLogger(t),
+	}
+
+	require.NoError(t, c.checkOrCreateNamespace(ctx, loadTest))
+	assert.Equal(t, "loadtest-name", loadTest.Status.Namespace)
+
+	quota, err := kubeClientSet.CoreV1().ResourceQuotas("loadtest-name").Get(ctx, guardrailsName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	// template values are multiplied by 4 pods, master included
+	assert.True(t, resource.MustParse("2").Equal(quota.Spec.Hard[coreV1.ResourceLimitsCPU]))
+	assert.True(t, resource.MustParse("4Gi").Equal(quota.Spec.Hard[coreV1.ResourceLimitsMemory]))
+	assert.True(t, resource.MustParse("4").Equal(quota.Spec.Hard[coreV1.ResourcePods]))
+
+	_, err = kubeClientSet.CoreV1().LimitRanges("loadtest-name").Get(ctx, guardrailsName, metaV1.GetOptions{})
+	require.NoError(t, err)
+
+	networkPolicy, err := kubeClientSet.NetworkingV1().NetworkPolicies("loadtest-name").Get(ctx, guardrailsName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []networkingV1.PolicyType{networkingV1.PolicyTypeEgress}, networkPolicy.Spec.PolicyTypes)
+	require.Len(t, networkPolicy.Spec.Egress, 4)
+	assert.Equal(t, "10.1.2.3/32", networkPolicy.Spec.Egress[2].To[0].IPBlock.CIDR)
+	assert.Equal(t, "192.168.0.10/32", networkPolicy.Spec.Egress[3].To[0].IPBlock.CIDR)
+
+	// existing guardrails are kept when namespace is synced again
+	loadTest.Status.Namespace = ""
+	require.NoError(t, c.checkOrCreateNamespace(ctx, loadTest))
+	assert.Equal(t, "loadtest-name", loadTest.Status.Namespace)
+}
+
+func TestNetworkPolicyProxyPeer(t *testing.T) {
+	ctx := context.Background()
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{TargetURL: "http://10.1.2.3:8080/api"},
+	}
+
+	guardrails, err := LoadGuardrails(Config{
+		NetworkPolicy:               true,
+		NetworkPolicyProxyNamespace: "kangal",
+		NetworkPolicyProxySelector:  "app=kangal-proxy",
+		NetworkPolicyTargetCIDRs:    []string{"10.1.0.0/16", "172.16.0.0/12"},
+		NetworkPolicyDNSNamespace:   "kube-system",
+		NetworkPolicyDNSSelector:    "k8s-app=kube-dns",
+	})
+	require.NoError(t, err)
+
+	c := &Controller{
+		// cluster service addresses are translated to pod addresses before the policy is applied,
+		// so the proxy is allowed by its pods instead of its resolved address
+		cfg:    Config{KangalProxyURL: "http://kangal-proxy.kangal.svc", Guardrails: guardrails},
+		logger: zaptest.NewLogger(t),
+	}
+
+	networkPolicy, err := c.newNetworkPolicy(ctx, loadTest)
+	require.NoError(t, err)
+	require.Len(t, networkPolicy.Spec.Egress, 4)
+
+	// DNS is allowed to cluster DNS pods only
+	dns := networkPolicy.Spec.Egress[1]
+	require.Len(t, dns.Ports, 2)
+	require.Len(t, dns.To, 1)
+	assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": "kube-system"}, dns.To[0].NamespaceSelector.MatchLabels)
+	assert.Equal(t, map[string]string{"k8s-app": "kube-dns"}, dns.To[0].PodSelector.MatchLabels)
+
+	targets := networkPolicy.Spec.Egress[2].To
+	require.Len(t, targets, 2)
+	assert.Equal(t, "10.1.0.0/16", targets[0].IPBlock.CIDR)
+	assert.Equal(t, "172.16.0.0/12", targets[1].IPBlock.CIDR)
+
+	proxy := networkPolicy.Spec.Egress[3].To
+	require.Len(t, proxy, 1)
+	assert.Nil(t, proxy[0].IPBlock)
+	assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": "kangal"}, proxy[0].NamespaceSelector.MatchLabels)
+	assert.Equal(t, map[string]string{"app": "kangal-proxy"}, proxy[0].PodSelector.MatchLabels)
+}
+
+func TestNetworkPolicyWithoutTarget(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}
+
+	c := &Controller{
+		cfg:    Config{KangalProxyURL: "http://192.168.0.10", Guardrails: Guardrails{NetworkPolicy: true}},
+		logger: zaptest.NewLogger(t),
+	}
+
+	// no targetURL and no target CIDRs allow no target, only loadtest pods, DNS and the proxy
+	networkPolicy, err := c.newNetworkPolicy(context.Background(), loadTest)
+	require.NoError(t, err)
+	require.Len(t, networkPolicy.Spec.Egress, 3)
+	assert.Empty(t, networkPolicy.Spec.Egress[1].To)
+	assert.Equal(t, "192.168.0.10/32", networkPolicy.Spec.Egress[2].To[0].IPBlock.CIDR)
+}
+
+func TestNetworkPolicyMetricsExport(t *testing.T) {
+	ctx := context.Background()
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{TargetURL: "http://10.1.2.3:8080/api"},
+	}
+
+	c := &Controller{
+		cfg: Config{
+			KangalProxyURL: "http://192.168.0.10",
+			MetricsExport: backends.MetricsExport{
+				Protocol: backends.MetricsProtocolPrometheus,
+				URL:      "http://192.168.0.20:9090/api/v1/write",
+			},
+			Guardrails: Guardrails{NetworkPolicy: true},
+		},
+		logger: zaptest.NewLogger(t),
+	}
+
+	networkPolicy, err := c.newNetworkPolicy(ctx, loadTest)
+	require.NoError(t, err)
+	require.Len(t, networkPolicy.Spec.Egress, 5)
+	assert.Equal(t, "10.1.2.3/32", networkPolicy.Spec.Egress[2].To[0].IPBlock.CIDR)
+	assert.Equal(t, "192.168.0.10/32", networkPolicy.Spec.Egress[3].To[0].IPBlock.CIDR)
+	assert.Equal(t, "192.168.0.20/32", networkPolicy.Spec.Egress[4].To[0].IPBlock.CIDR)
+
+	// metrics export disabled adds no rule even with URL set
+	c.cfg.MetricsExport.Protocol = ""
+	networkPolicy, err = c.newNetworkPolicy(ctx, loadTest)
+	require.NoError(t, err)
+	assert.Len(t, networkPolicy.Spec.Egress, 4)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+)
+
+// HistoryRecorder stores metadata of completed loadtests, so they stay findable after the loadtests are deleted
+type HistoryRecorder interface {
+	// Record stores the loadtest metadata record
+	Record(ctx context.Context, record report.HistoryRecord) error
+}
+
+// recordHistory stores the metadata of a completed loadtest once and records it in the Recorded condition.
+// Loadtests with thresholds or comparing to baseline are recorded after they are evaluated, so the record has the results
+func (c *Controller) recordHistory(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	status := &loadTest.Status
+
+	if c.history == nil ||
+		(status.Phase != loadTestV1.LoadTestFinished && status.Phase != loadTestV1.LoadTestErrored) ||
+		meta.FindStatusCondition(status.Conditions, loadTestV1.LoadTestConditionRecorded.String()) != nil {
+		return nil
+	}
+
+	if !resultsEvaluated(loadTest) {
+		return nil
+	}
+
+	err := c.history.Record(ctx, newHistoryRecord(loadTest))
+	if err != nil {
+		return fmt.Errorf("could not record loadtest history: %w", err)
+	}
+
+	meta.SetStatusCondition(&status.Conditions, metaV1.Condition{
+		Type:    loadTestV1.LoadTestConditionRecorded.String(),
+		Status:  metaV1.ConditionTrue,
+		Reason:  loadTestV1.LoadTestReasonHistoryRecorded,
+		Message: fmt.Sprintf("Loadtest metadata is stored as %s", report.HistoryObjectName(loadTest.GetName())),
+	})
+	return nil
+}
+
+// resultsEvaluated returns false while thresholds or baseline comparison of a finished loadtest are not evaluated yet
+func resultsEvaluated(loadTest *loadTestV1.LoadTest) bool {
+	status := &loadTest.Status
+	if status.Phase != loadTestV1.LoadTestFinished {
+		return true
+	}
+
+	if loadTest.Spec.Thresholds != nil &&
+		meta.FindStatusCondition(status.Conditions, loadTestV1.LoadTestConditionPassed.String()) == nil {
+		return false
+	}
+	if loadTest.Spec.CompareBaseline &&
+		meta.FindStatusCondition(status.Conditions, loadTestV1.LoadTestConditionRegressed.String()) == nil {
+		return false
+	}
+	return true
+}
+
+// newHistoryRecord returns the loadtest metadata record, environment variable values are redacted and test files are digested
+func newHistoryRecord(loadTest *loadTestV1.LoadTest) report.HistoryRecord {
+	return report.HistoryRecord{
+		Name:              loadTest.GetName(),
+		Type:              loadTest.Spec.Type,
+		Tags:              loadTest.Spec.Tags,
+		CreationTimestamp: loadTest.GetCreationTimestamp().Time,
+		Spec:              redactedSpec(loadTest),
+		Status:            loadTest.Status,
+		TestFile:          report.NewFileDigest(loadTest.Spec.TestFile),
+		TestData:          report.NewFileDigest(loadTest.Spec.TestData),
+		ReportKey:         loadTest.GetName(),
+	}
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+)
+
+type fakeHistoryRecorder struct {
+	records []report.HistoryRecord
+	err     error
+}
+
+func (h *fakeHistoryRecorder) Record(_ context.Context, record report.HistoryRecord) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.records = append(h.records, record)
+	return nil
+}
+
+func TestRecordHistory(t *testing.T) {
+	ctx := context.Background()
+
+	newLoadTest := func(phase loadTestV1.LoadTestPhase) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+			Spec: loadTestV1.LoadTestSpec{
+				Type:     loadTestV1.LoadTestTypeK6,
+				Tags:     loadTestV1.LoadTestTags{"team": "kangal"},
+				EnvVars:  map[string]string{"API_TOKEN": "secret"},
+				TestFile: []byte("import http from 'k6/http'"),
+			},
+			Status: loadTestV1.LoadTestStatus{Phase: phase},
+		}
+	}
+
+	recordedCondition := func(loadTest *loadTestV1.LoadTest) *metaV1.Condition {
+		return meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionRecorded.String())
+	}
+
+	t.Run("completed loadtest is recorded once", func(t *testing.T) {
+		history := &fakeHistoryRecorder{}
+		c := &Controller{history: history, logger: zaptest.NewLogger(t)}
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished)
+
+		require.NoError(t, c.recordHistory(ctx, loadTest))
+		require.NoError(t, c.recordHistory(ctx, loadTest))
+
+		require.Len(t, history.records, 1)
+		record := history.records[0]
+		assert.Equal(t, "loadtest-name", record.Name)
+		assert.Equal(t, "loadtest-name", record.ReportKey)
+		assert.Equal(t, loadTestV1.LoadTestTypeK6, record.Type)
+		assert.Equal(t, loadTestV1.LoadTestTags{"team": "kangal"}, record.Tags)
+		assert.Equal(t, redactedValue, record.Spec.EnvVars["API_TOKEN"])
+		assert.Equal(t, "secret", loadTest.Spec.EnvVars["API_TOKEN"])
+		assert.Nil(t, record.Spec.TestFile)
+		assert.Equal(t, report.NewFileDigest(loadTest.Spec.TestFile), record.TestFile)
+		assert.Nil(t, record.TestData)
+
+		condition := recordedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionTrue, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonHistoryRecorded, condition.Reason)
+	})
+
+	t.Run("running loadtest is not recorded", func(t *testing.T) {
+		history := &fakeHistoryRecorder{}
+		c := &Controller{history: history, logger: zaptest.NewLogger(t)}
+		loadTest := newLoadTest(loadTestV1.LoadTestRunning)
+
+		require.NoError(t, c.recordHistory(ctx, loadTest))
+		assert.Empty(t, history.records)
+		assert.Nil(t, recordedCondition(loadTest))
+	})
+
+	t.Run("thresholds are evaluated first", func(t *testing.T) {
+		history := &fakeHistoryRecorder{}
+		c := &Controller{history: history, logger: zaptest.NewLogger(t)}
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished)
+		loadTest.Spec.Thresholds = &loadTestV1.LoadTestThresholds{P95Latency: "500ms"}
+
+		require.NoError(t, c.recordHistory(ctx, loadTest))
+		assert.Empty(t, history.records)
+
+		setPassedCondition(&loadTest.Status, metaV1.ConditionTrue, loadTestV1.LoadTestReasonThresholdsPassed, "ok")
+		require.NoError(t, c.recordHistory(ctx, loadTest))
+		require.Len(t, history.records, 1)
+		assert.NotEmpty(t, history.records[0].Status.Conditions)
+	})
+
+	t.Run("baseline is compared first", func(t *testing.T) {
+		history := &fakeHistoryRecorder{}
+		c := &Controller{history: history, logger: zaptest.NewLogger(t)}
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished)
+		loadTest.Spec.CompareBaseline = true
+
+		require.NoError(t, c.recordHistory(ctx, loadTest))
+		assert.Empty(t, history.records)
+
+		setRegressedCondition(&loadTest.Status, metaV1.ConditionFalse, loadTestV1.LoadTestReasonBaselineMatched, "ok")
+		require.NoError(t, c.recordHistory(ctx, loadTest))
+		require.Len(t, history.records, 1)
+	})
+
+	t.Run("store error is retried", func(t *testing.T) {
+		history := &fakeHistoryRecorder{err: errors.New("store is down")}
+		c := &Controller{history: history, logger: zaptest.NewLogger(t)}
+		loadTest := newLoadTest(loadTestV1.LoadTestErrored)
+
+		assert.Error(t, c.recordHistory(ctx, loadTest))
+		assert.Nil(t, recordedCondition(loadTest))
+	})
+}
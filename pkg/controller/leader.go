@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	leaderRole   = "leader"
+	followerRole = "follower"
+)
+
+// ErrLeaderElectionLost is returned when controller replica stops being the leader
+var ErrLeaderElectionLost = errors.New("leader election lost")
+
+// LeaderState keeps track of current controller replica leadership
+type LeaderState struct {
+	mu       sync.RWMutex
+	identity string
+	leader   string
+	isLeader bool
+}
+
+// NewLeaderState creates LeaderState for the given replica identity
+func NewLeaderState(identity string) *LeaderState {
+	return &LeaderState{identity: identity}
+}
+
+// Identity returns the identity of the current replica
+func (s *LeaderState) Identity() string {
+	return s.identity
+}
+
+// IsLeader returns true when current replica holds the lease
+func (s *LeaderState) IsLeader() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isLeader
+}
+
+// Leader returns the identity of the last observed leader
+func (s *LeaderState) Leader() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.leader
+}
+
+// Role returns leader or follower depending on current replica leadership
+func (s *LeaderState) Role() string {
+	if s.IsLeader() {
+		return leaderRole
+	}
+	return followerRole
+}
+
+func (s *LeaderState) setLeader(identity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leader = identity
+	s.isLeader = identity == s.identity
+}
+
+func (s *LeaderState) stopLeading() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.leader == s.identity {
+		s.leader = ""
+	}
+	s.isLeader = false
+}
+
+// leaderElectionIdentity returns identity used by current replica to acquire the lease
+func leaderElectionIdentity(cfg Config) (string, error) {
+	if cfg.LeaderElectionIdentity != "" {
+		return cfg.LeaderElectionIdentity, nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("could not get hostname: %w", err)
+	}
+	return hostname, nil
+}
+
+// runWithLeaderElection blocks until the lease is acquired and then calls run, standby replicas stay idle.
+// It returns ErrLeaderElectionLost when the lease is lost, as controller can not be safely restarted in-process.
+func runWithLeaderElection(ctx context.Context, cfg Config, kubeClient kubernetes.Interface, state *LeaderState, stats MetricsReporter, logger *zap.Logger, run func(stopCh <-chan struct{}) error) error {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metaV1.ObjectMeta{
+			Name:      cfg.LeaderElectionID,
+			Namespace: cfg.LeaderElectionNamespace,
+		},
+		Client: kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: state.Identity(),
+		},
+	}
+
+	leading := make(chan struct{})
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   cfg.LeaderElectionLeaseDuration,
+		RenewDeadline:   cfg.LeaderElectionRenewDeadline,
+		RetryPeriod:     cfg.LeaderElectionRetryPeriod,
+		Name:            cfg.LeaderElectionID,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				logger.Info("Started leading", zap.String("identity", state.Identity()))
+				state.setLeader(state.Identity())
+				stats.isLeaderStat.Add(ctx, 1)
+				close(leading)
+			},
+			OnStoppedLeading: func() {
+				if !state.IsLeader() {
+					return
+				}
+				logger.Info("Stopped leading", zap.String("identity", state.Identity()))
+				state.stopLeading()
+				stats.isLeaderStat.Add(context.Background(), -1)
+			},
+			OnNewLeader: func(identity string) {
+				logger.Info("New leader elected", zap.String("leader", identity))
+				if identity != state.Identity() {
+					state.setLeader(identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not create leader elector: %w", err)
+	}
+
+	logger.Info("Waiting for leader election",
+		zap.String("lease", cfg.LeaderElectionNamespace+"/"+cfg.LeaderElectionID),
+		zap.String("identity", state.Identity()),
+	)
+
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	electionDone := make(chan struct{})
+	go func() {
+		defer close(electionDone)
+		elector.Run(electionCtx)
+	}()
+
+	select {
+	case <-leading:
+	case <-electionDone:
+		if ctx.Err() == nil {
+			return ErrLeaderElectionLost
+		}
+		return nil
+	}
+
+	stopCh := make(chan struct{})
+	go func() {
+		defer close(stopCh)
+		select {
+		case <-electionDone:
+		case <-ctx.Done():
+		}
+	}()
+
+	runErr := run(stopCh)
+
+	// release the lease before returning
+	cancel()
+	<-electionDone
+
+	if runErr != nil {
+		return runErr
+	}
+	if ctx.Err() == nil {
+		return ErrLeaderElectionLost
+	}
+	return nil
+}
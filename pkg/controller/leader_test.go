@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStatusHandler(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		leader       string
+		expectedRole string
+	}{
+		{
+			name:         "leader",
+			leader:       "kangal-controller-a",
+			expectedRole: leaderRole,
+		},
+		{
+			name:         "follower",
+			leader:       "kangal-controller-b",
+			expectedRole: followerRole,
+		},
+		{
+			name:         "no leader elected yet",
+			expectedRole: followerRole,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewLeaderState("kangal-controller-a")
+			if tt.leader != "" {
+				state.setLeader(tt.leader)
+			}
+
+			request, err := http.NewRequest("GET", "/status", nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+
+			StatusHandler(state)(w, request)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var status StatusResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+			assert.Equal(t, tt.expectedRole, status.Role)
+			assert.Equal(t, "kangal-controller-a", status.Identity)
+			assert.Equal(t, tt.leader, status.Leader)
+		})
+	}
+}
+
+func TestRunWithLeaderElection(t *testing.T) {
+	cfg := Config{
+		LeaderElectionID:            "kangal-controller",
+		LeaderElectionNamespace:     "default",
+		LeaderElectionLeaseDuration: 3 * time.Second,
+		LeaderElectionRenewDeadline: 2 * time.Second,
+		LeaderElectionRetryPeriod:   100 * time.Millisecond,
+	}
+
+	stats, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	state := NewLeaderState("kangal-controller-a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	run := func(stopCh <-chan struct{}) error {
+		close(started)
+		<-stopCh
+		return nil
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runWithLeaderElection(ctx, cfg, kubeClient, state, *stats, zaptest.NewLogger(t), run)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("controller was not started after acquiring the lease")
+	}
+
+	assert.True(t, state.IsLeader())
+	assert.Equal(t, leaderRole, state.Role())
+
+	lease, err := kubeClient.CoordinationV1().Leases("default").Get(context.Background(), "kangal-controller", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "kangal-controller-a", *lease.Spec.HolderIdentity)
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("leader election did not stop after context cancellation")
+	}
+	assert.False(t, state.IsLeader())
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -11,6 +12,7 @@ import (
 	"go.uber.org/zap"
 	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -38,11 +40,22 @@ const (
 	trueString          = "true"
 )
 
+// Event reasons recorded by the controller on LoadTest resources
+const (
+	eventReasonNamespaceCreated = "NamespaceCreated"
+	eventReasonSyncFailed       = "SyncFailed"
+	eventReasonPhaseChanged     = "PhaseChanged"
+	eventReasonTimeout          = "Timeout"
+	eventReasonDeleted          = "Deleted"
+	eventReasonDeleteFailed     = "DeleteFailed"
+)
+
 // MetricsReporter used to interface with the metrics configurations
 type MetricsReporter struct {
 	workQueueDepthStat   metric.Int64UpDownCounter
 	reconcileCountStat   metric.Int64UpDownCounter
 	reconcileLatencyStat metric.Int64Histogram
+	isLeaderStat         metric.Int64UpDownCounter
 }
 
 // NewMetricsReporter contains loadtest metrics definition
@@ -72,10 +85,19 @@ func NewMetricsReporter(meter metric.Meter) (*MetricsReporter, error) {
 		return nil, fmt.Errorf("could not register reconcileLatencyStat metric: %w", err)
 	}
 
+	isLeaderStat, err := meter.Int64UpDownCounter(
+		"kangal_controller_is_leader",
+		metric.WithDescription("Whether the controller replica is the leader (1) or a follower (0)"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register isLeaderStat metric: %w", err)
+	}
+
 	return &MetricsReporter{
 		workQueueDepthStat:   workQueueDepthStat,
 		reconcileCountStat:   reconcileCountStat,
 		reconcileLatencyStat: reconcileLatencyStat,
+		isLeaderStat:         isLeaderStat,
 	}, nil
 }
 
@@ -100,14 +122,24 @@ type Controller struct {
 	// time, and makes it easy to ensure we are never processing the same item
 	// simultaneously in two different workers.
 	workQueue workqueue.RateLimitingInterface
+	// notifyQueue is the queue of loadtests with pending notifications, it is
+	// processed by a separate worker so deliveries do not hold the sync handler
+	notifyQueue workqueue.RateLimitingInterface
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
 
 	statsClient MetricsReporter
 
-	registry backends.Registry
-	logger   *zap.Logger
+	registry     backends.Registry
+	archiver     Archiver
+	reportReader ReportReader
+	history      HistoryRecorder
+	baselines    BaselineComparer
+	// callbackClient posts notifications to user callbacks, webhookClient to admin webhooks
+	callbackClient *http.Client
+	webhookClient  *http.Client
+	logger         *zap.Logger
 }
 
 // NewController returns a new sample controller
@@ -119,6 +151,11 @@ func NewController(
 	kangalInformerFactory externalversions.SharedInformerFactory,
 	statsClient MetricsReporter,
 	registry backends.Registry,
+	recorder record.EventRecorder,
+	archiver Archiver,
+	reportReader ReportReader,
+	history HistoryRecorder,
+	baselines BaselineComparer,
 	logger *zap.Logger,
 ) *Controller {
 	namespaceInformer := kubeInformerFactory.Core().V1().Namespaces()
@@ -127,18 +164,6 @@ func NewController(
 
 	loadTestInformer := kangalInformerFactory.Kangal().V1().LoadTests()
 
-	// Create event broadcaster
-	// Add sample-controller types to the default Kubernetes Scheme so Events can be
-	// logged for sample-controller types.
-	utilRuntime.Must(sampleScheme.AddToScheme(scheme.Scheme))
-	logger.Debug("Creating event broadcaster")
-	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(func(format string, args ...interface{}) {
-		logger.Info(fmt.Sprintf(format, args...))
-	})
-	eventBroadcaster.StartRecordingToSink(&typedCoreV1.EventSinkImpl{Interface: kubeClientSet.CoreV1().Events("")})
-	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, coreV1.EventSource{Component: controllerAgentName})
-
 	controller := &Controller{
 		cfg: cfg,
 
@@ -155,11 +180,19 @@ func NewController(
 		loadtestsSynced: loadTestInformer.Informer().HasSynced,
 
 		workQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LoadTest"),
+		notifyQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LoadTestNotification"),
 		recorder:    recorder,
 		statsClient: statsClient,
 
-		registry: registry,
-		logger:   logger,
+		registry:     registry,
+		archiver:     archiver,
+		reportReader: reportReader,
+		history:      history,
+		baselines:    baselines,
+		logger:       logger,
+
+		callbackClient: newCallbackClient(),
+		webhookClient:  newWebhookClient(),
 	}
 
 	logger.Debug("Setting up event handlers")
@@ -203,6 +236,21 @@ func NewController(
 	return controller
 }
 
+// NewEventRecorder returns an event recorder which records Event resources
+// for LoadTest resources to the Kubernetes API
+func NewEventRecorder(kubeClientSet kubernetes.Interface, logger *zap.Logger) record.EventRecorder {
+	// Add kangal types to the default Kubernetes Scheme so Events can be
+	// logged for kangal types.
+	utilRuntime.Must(sampleScheme.AddToScheme(scheme.Scheme))
+	logger.Debug("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(func(format string, args ...interface{}) {
+		logger.Info(fmt.Sprintf(format, args...))
+	})
+	eventBroadcaster.StartRecordingToSink(&typedCoreV1.EventSinkImpl{Interface: kubeClientSet.CoreV1().Events("")})
+	return eventBroadcaster.NewRecorder(scheme.Scheme, coreV1.EventSource{Component: controllerAgentName})
+}
+
 // Run will set up the event handlers for types we are interested in, as well
 // as syncing informer caches and starting workers. It will block until stopCh
 // is closed, at which point it will shutdown the workQueue and wait for
@@ -210,6 +258,7 @@ func NewController(
 func (c *Controller) Run(numThreads int, stopCh <-chan struct{}) error {
 	defer utilRuntime.HandleCrash()
 	defer c.workQueue.ShutDown()
+	defer c.notifyQueue.ShutDown()
 
 	// Start the informer factories to begin populating the informer caches
 	c.logger.Info("Starting loadtest controller")
@@ -225,6 +274,7 @@ func (c *Controller) Run(numThreads int, stopCh <-chan struct{}) error {
 	for i := 0; i < numThreads; i++ {
 		go wait.Until(c.runWorker, time.Second, stopCh)
 	}
+	go wait.Until(c.runNotificationWorker, time.Second, stopCh)
 
 	c.logger.Debug("Started workers")
 	<-stopCh
@@ -350,10 +400,14 @@ func (c *Controller) syncHandler(key string) error {
 	// copy object before mutate it
 	loadTest := loadTestFromCache.DeepCopy()
 
-	// get report url
-	var reportURL string
-	if c.cfg.KangalProxyURL != "" {
-		reportURL = fmt.Sprintf("%s/load-test/%s/report", c.cfg.KangalProxyURL, loadTest.GetName())
+	// archive loadtest being deleted, nothing else should be synced
+	if loadTest.GetDeletionTimestamp() != nil {
+		return c.finalizeLoadTest(ctx, loadTest)
+	}
+
+	if c.archiver != nil && !hasFinalizer(loadTest) {
+		// finalizer update triggers a new sync of the loadtest
+		return c.addFinalizer(ctx, loadTest)
 	}
 
 	// get backend
@@ -368,32 +422,96 @@ func (c *Controller) syncHandler(key string) error {
 	// check or create namespace
 	err = c.checkOrCreateNamespace(ctx, loadTest)
 	if err != nil {
+		c.recordSyncError(ctx, loadTest, "Failed to create namespace", err)
 		return err
 	}
 
-	// sync backend resources
-	err = backend.Sync(ctx, *loadTest, reportURL)
+	// copy secret env vars before backend creates pods referencing them
+	err = c.syncSecretEnvVars(ctx, loadTest)
 	if err != nil {
+		c.recordSyncError(ctx, loadTest, "Failed to sync secret env vars", err)
 		return err
 	}
 
+	// sync backend resources, errored loadtest does not need them anymore
+	if loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		err = backend.Sync(ctx, *loadTest, c.reportURL(loadTest.GetName()))
+		if err != nil {
+			c.recordSyncError(ctx, loadTest, "Failed to sync resources", err)
+			return err
+		}
+	}
+
 	// sync backend status
 	err = backend.SyncStatus(ctx, *loadTest, &loadTest.Status)
 	if err != nil {
+		c.recordSyncError(ctx, loadTest, "Failed to sync status", err)
+		return err
+	}
+
+	// terminate loadtest running longer than its deadline
+	err = c.enforceDeadline(ctx, loadTest)
+	if err != nil {
+		c.recordSyncError(ctx, loadTest, "Failed to enforce deadline", err)
+		return err
+	}
+
+	// notify callbacks and webhooks once loadtest transitions to finished or errored
+	c.queueNotifications(loadTest, loadTestFromCache.Status.Phase)
+
+	// evaluate thresholds of finished loadtest against its report
+	err = c.evaluateThresholds(ctx, loadTest)
+	if err != nil {
+		c.recordSyncError(ctx, loadTest, "Failed to evaluate thresholds", err)
 		return err
 	}
 
+	// compare results of finished loadtest to its baseline, before the loadtest is recorded as a possible baseline
+	err = c.compareBaseline(ctx, loadTest)
+	if err != nil {
+		c.recordSyncError(ctx, loadTest, "Failed to compare to baseline", err)
+		return err
+	}
+
+	// store metadata of completed loadtest, so it outlives the loadtest
+	err = c.recordHistory(ctx, loadTest)
+	if err != nil {
+		c.recordSyncError(ctx, loadTest, "Failed to record history", err)
+		return err
+	}
+
+	// deliver pending notifications with the evaluated results
+	c.scheduleNotifications(key, loadTest)
+
 	// check and delete stale finished/errored loadtests
-	if c.cfg.CleanUpThreshold != 0 && checkLoadTestLifeTimeExceeded(loadTest, c.cfg.CleanUpThreshold) {
+	if threshold, ok := lifeTimeThreshold(loadTest, c.cfg.CleanUpThreshold); ok && checkLoadTestLifeTimeExceeded(loadTest, threshold) {
 		logger.Info("Deleting loadtest due to exceeded lifetime",
 			zap.String("phase", loadTest.Status.Phase.String()),
+			zap.Duration("threshold", threshold),
 		)
-		c.deleteLoadTest(ctx, key, loadTest)
+		c.deleteLoadTest(ctx, key, loadTest, threshold)
 	}
 
 	return nil
 }
 
+// reportURL returns the proxy url of the loadtest report, empty when the proxy url is not configured
+func (c *Controller) reportURL(name string) string {
+	if c.cfg.KangalProxyURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/load-test/%s/report", c.cfg.KangalProxyURL, name)
+}
+
+// recordSyncError records a warning event on the loadtest, sync handler timeouts are distinguished from other errors
+func (c *Controller) recordSyncError(ctx context.Context, loadTest *loadTestV1.LoadTest, message string, err error) {
+	if ctx.Err() == context.DeadlineExceeded {
+		c.recorder.Eventf(loadTest, coreV1.EventTypeWarning, eventReasonTimeout, "%s: sync did not finish within %s", message, c.cfg.SyncHandlerTimeout)
+		return
+	}
+	c.recorder.Eventf(loadTest, coreV1.EventTypeWarning, eventReasonSyncFailed, "%s: %v", message, err)
+}
+
 // handleObject will take any resource implementing metaV1.Object and attempt
 // to find the LoadTest resource that 'owns' it. It does this by looking at the
 // objects metadata.ownerReferences field for an appropriate OwnerReference.
@@ -457,7 +575,8 @@ func (c *Controller) updateLoadTestStatus(ctx context.Context, key string, loadT
 		zap.String("loadtest", loadTest.GetName()),
 	)
 
-	if loadTest.Status.Phase != loadTestFromCache.Status.Phase {
+	// status details like reason or conditions may change without a phase transition
+	if !equality.Semantic.DeepEqual(loadTest.Status, loadTestFromCache.Status) {
 		logger.Debug("Updating loadtest status",
 			zap.String("new phase", loadTest.Status.Phase.String()),
 			zap.String("previous phase", loadTestFromCache.Status.Phase.String()),
@@ -477,7 +596,30 @@ func (c *Controller) updateLoadTestStatus(ctx context.Context, key string, loadT
 		}
 
 		logger.Debug("Status updated", zap.Any("status", loadTest.Status))
+
+		if loadTest.Status.Phase != loadTestFromCache.Status.Phase {
+			c.recordPhaseChange(loadTest, loadTestFromCache.Status.Phase)
+		}
+	}
+}
+
+// recordPhaseChange records an event on the loadtest about its phase transition
+func (c *Controller) recordPhaseChange(loadTest *loadTestV1.LoadTest, previousPhase loadTestV1.LoadTestPhase) {
+	if previousPhase == "" {
+		previousPhase = "none"
+	}
+
+	eventType := coreV1.EventTypeNormal
+	if loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		eventType = coreV1.EventTypeWarning
+	}
+
+	message := fmt.Sprintf("Phase changed from %s to %s", previousPhase, loadTest.Status.Phase)
+	if loadTest.Status.Reason != "" {
+		message = fmt.Sprintf("%s, reason %s: %s", message, loadTest.Status.Reason, loadTest.Status.Message)
 	}
+
+	c.recorder.Event(loadTest, eventType, eventReasonPhaseChanged, message)
 }
 
 // checkOrCreateNamespace checks if a namespace has been created and if not deletes it
@@ -486,6 +628,13 @@ func (c *Controller) checkOrCreateNamespace(ctx context.Context, loadtest *loadT
 		return nil
 	}
 
+	// in single namespace mode resources are named and labeled per load test, namespace guardrails are not created
+	if c.cfg.SingleNamespace != "" {
+		loadtest.Status.Namespace = c.cfg.SingleNamespace
+		loadtest.Status.SharedNamespace = true
+		return nil
+	}
+
 	logger := c.logger.With(zap.String("loadtest", loadtest.GetName()))
 	for k, v := range loadtest.Spec.Tags {
 		logger = logger.With(zap.String(k, v))
@@ -508,10 +657,16 @@ func (c *Controller) checkOrCreateNamespace(ctx context.Context, loadtest *loadT
 		}
 		namespaceName = namespaceObj.GetName()
 		logger.Info("Created new namespace", zap.String("namespace", namespaceName))
+		c.recorder.Eventf(loadtest, coreV1.EventTypeNormal, eventReasonNamespaceCreated, "Created namespace %s", namespaceName)
 	} else {
 		namespaceName = namespaces.Items[0].Name
 	}
 
+	// namespace is not set in status until guardrails are created, so failed ones are retried on next sync
+	if err := c.createNamespaceGuardrails(ctx, loadtest, namespaceName); err != nil {
+		return err
+	}
+
 	loadtest.Status.Namespace = namespaceName
 	return nil
 }
@@ -535,30 +690,44 @@ func newNamespace(loadtest *loadTestV1.LoadTest, namespaceAnnotations map[string
 	}, nil
 }
 
-// checkLoadTestLifeTimeExceeded returns true if the input loadtest has
-// existed for longer than certain threshold, and its status is Finished or Errored
+// lifeTimeThreshold returns for how long the loadtest is kept after it finished, loadtest TTL takes precedence
+// over the controller cleanup threshold. Last return value is false when clean up is disabled for the loadtest
+func lifeTimeThreshold(loadTest *loadTestV1.LoadTest, cleanUpThreshold time.Duration) (time.Duration, bool) {
+	if ttl := loadTest.Spec.TTLSecondsAfterFinished; ttl != nil {
+		return time.Duration(*ttl) * time.Second, true
+	}
+	return cleanUpThreshold, cleanUpThreshold != 0
+}
+
+// checkLoadTestLifeTimeExceeded returns true if the input loadtest has finished or errored
+// for longer than certain threshold. The lifetime is counted from the loadtest completion time,
+// errored loadtests that never completed count it from their creation
 func checkLoadTestLifeTimeExceeded(loadTest *loadTestV1.LoadTest, deleteThreshold time.Duration) bool {
-	if loadTest.Status.JobStatus.CompletionTime != nil {
-		if time.Since(loadTest.Status.JobStatus.CompletionTime.Time) > deleteThreshold &&
-			(loadTest.Status.Phase == loadTestV1.LoadTestFinished || loadTest.Status.Phase == loadTestV1.LoadTestErrored) {
-			return true
-		}
+	if loadTest.Status.Phase != loadTestV1.LoadTestFinished && loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		return false
 	}
 
-	if loadTest.Status.Phase == loadTestV1.LoadTestErrored &&
-		time.Since(loadTest.ObjectMeta.CreationTimestamp.Time) > deleteThreshold {
-		return true
+	completionTime := loadTest.Status.CompletionTime
+	if completionTime == nil {
+		completionTime = loadTest.Status.JobStatus.CompletionTime
+	}
+	if completionTime != nil {
+		return time.Since(completionTime.Time) > deleteThreshold
 	}
 
-	return false
+	return loadTest.Status.Phase == loadTestV1.LoadTestErrored &&
+		time.Since(loadTest.ObjectMeta.CreationTimestamp.Time) > deleteThreshold
 }
 
-func (c *Controller) deleteLoadTest(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) {
+func (c *Controller) deleteLoadTest(ctx context.Context, key string, loadTest *loadTestV1.LoadTest, threshold time.Duration) {
 	err := c.kangalClientSet.KangalV1().LoadTests().Delete(ctx, loadTest.Name, metaV1.DeleteOptions{})
 	if err == nil {
+		c.recorder.Eventf(loadTest, coreV1.EventTypeNormal, eventReasonDeleted, "Deleted loadtest in phase %s after exceeding its lifetime of %s", loadTest.Status.Phase, threshold)
 		return
 	}
 
+	c.recorder.Eventf(loadTest, coreV1.EventTypeWarning, eventReasonDeleteFailed, "Failed to delete loadtest: %v", err)
+
 	// The LoadTest resource may be conflicted, in which case we stop processing.
 	if errors.IsConflict(err) {
 		c.logger.Error("There is a conflict while deleting the loadtest", zap.Error(err))
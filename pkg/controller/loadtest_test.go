@@ -1,14 +1,20 @@
 package controller
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 	batchV1 "k8s.io/api/batch/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
 )
 
 func TestShouldDeleteLoadtest(t *testing.T) {
@@ -76,6 +82,47 @@ func TestShouldDeleteLoadtest(t *testing.T) {
 			},
 			time.Hour * 2,
 		},
+		{
+			"test errored now after running long",
+			false,
+			loadTestV1.LoadTest{
+				Status: loadTestV1.LoadTestStatus{
+					Phase:          loadTestV1.LoadTestErrored,
+					CompletionTime: &metav1TimeNow,
+				},
+				ObjectMeta: metaV1.ObjectMeta{
+					CreationTimestamp: metav1TimeTwoMonthsAgo,
+				},
+			},
+			time.Minute * 5,
+		},
+		{
+			"test errored long ago after running long",
+			true,
+			loadTestV1.LoadTest{
+				Status: loadTestV1.LoadTestStatus{
+					Phase:          loadTestV1.LoadTestErrored,
+					CompletionTime: &metav1TimeTwoMonthsAgo,
+				},
+				ObjectMeta: metaV1.ObjectMeta{
+					CreationTimestamp: metav1TimeTwoMonthsAgo,
+				},
+			},
+			time.Minute * 5,
+		},
+		{
+			"test running long",
+			false,
+			loadTestV1.LoadTest{
+				Status: loadTestV1.LoadTestStatus{
+					Phase: loadTestV1.LoadTestRunning,
+				},
+				ObjectMeta: metaV1.ObjectMeta{
+					CreationTimestamp: metav1TimeTwoMonthsAgo,
+				},
+			},
+			time.Minute * 5,
+		},
 		{
 			"test errored now, no jobstatus",
 			false,
@@ -100,3 +147,113 @@ func TestShouldDeleteLoadtest(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordPhaseChange(t *testing.T) {
+	var testCases = []struct {
+		Name          string
+		PreviousPhase loadTestV1.LoadTestPhase
+		Status        loadTestV1.LoadTestStatus
+		ExpectedEvent string
+	}{
+		{
+			"created",
+			"",
+			loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestCreating},
+			"Normal PhaseChanged Phase changed from none to creating",
+		},
+		{
+			"running",
+			loadTestV1.LoadTestStarting,
+			loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning},
+			"Normal PhaseChanged Phase changed from starting to running",
+		},
+		{
+			"errored",
+			loadTestV1.LoadTestStarting,
+			loadTestV1.LoadTestStatus{
+				Phase:   loadTestV1.LoadTestErrored,
+				Reason:  loadTestV1.LoadTestReasonImagePullFailure,
+				Message: "container jmeter is waiting: ImagePullBackOff",
+			},
+			"Warning PhaseChanged Phase changed from starting to errored, reason ImagePullFailure: container jmeter is waiting: ImagePullBackOff",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			c := &Controller{recorder: recorder}
+
+			c.recordPhaseChange(&loadTestV1.LoadTest{Status: test.Status}, test.PreviousPhase)
+			assert.Equal(t, test.ExpectedEvent, <-recorder.Events)
+		})
+	}
+}
+
+func TestLifeTimeThreshold(t *testing.T) {
+	ttlSeconds := int32(7200)
+	zeroTTLSeconds := int32(0)
+
+	var testCases = []struct {
+		Name              string
+		TTL               *int32
+		CleanUpThreshold  time.Duration
+		ExpectedThreshold time.Duration
+		ExpectedEnabled   bool
+	}{
+		{"no ttl", nil, time.Hour, time.Hour, true},
+		{"no ttl, clean up disabled", nil, 0, 0, false},
+		{"ttl overrides clean up threshold", &ttlSeconds, time.Hour, 2 * time.Hour, true},
+		{"ttl with clean up disabled", &ttlSeconds, 0, 2 * time.Hour, true},
+		{"zero ttl", &zeroTTLSeconds, time.Hour, 0, true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{Spec: loadTestV1.LoadTestSpec{TTLSecondsAfterFinished: test.TTL}}
+
+			threshold, enabled := lifeTimeThreshold(loadTest, test.CleanUpThreshold)
+			assert.Equal(t, test.ExpectedThreshold, threshold)
+			assert.Equal(t, test.ExpectedEnabled, enabled)
+		})
+	}
+}
+
+func TestDeleteLoadTest(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestFinished},
+	}
+	recorder := record.NewFakeRecorder(1)
+	c := &Controller{
+		kangalClientSet: fake.NewSimpleClientset(loadTest),
+		recorder:        recorder,
+		logger:          zaptest.NewLogger(t),
+	}
+
+	c.deleteLoadTest(context.Background(), "loadtest-name", loadTest, time.Hour)
+	assert.Equal(t, "Normal Deleted Deleted loadtest in phase finished after exceeding its lifetime of 1h0m0s", <-recorder.Events)
+
+	c.deleteLoadTest(context.Background(), "loadtest-name", loadTest, time.Hour)
+	assert.Contains(t, <-recorder.Events, "Warning DeleteFailed Failed to delete loadtest")
+}
+
+func TestCheckOrCreateNamespaceSingleNamespace(t *testing.T) {
+	ctx := context.Background()
+	kubeClientSet := k8sfake.NewSimpleClientset()
+	c := &Controller{
+		cfg:           Config{SingleNamespace: "kangal-loadtests"},
+		kubeClientSet: kubeClientSet,
+		recorder:      record.NewFakeRecorder(1),
+		logger:        zaptest.NewLogger(t),
+	}
+
+	loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}
+	require.NoError(t, c.checkOrCreateNamespace(ctx, loadTest))
+	assert.Equal(t, "kangal-loadtests", loadTest.Status.Namespace)
+	assert.True(t, loadTest.Status.SharedNamespace)
+
+	namespaces, err := kubeClientSet.CoreV1().Namespaces().List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, namespaces.Items)
+}
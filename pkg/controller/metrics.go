@@ -15,8 +15,28 @@ import (
 	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
 )
 
+// StatusResponse is the controller status with current replica leadership
+type StatusResponse struct {
+	StatusText string `json:"status"`
+	Role       string `json:"role"`
+	Identity   string `json:"identity"`
+	Leader     string `json:"leader,omitempty"`
+}
+
+// StatusHandler returns controller status and whether current replica is the leader or a follower
+func StatusHandler(state *LeaderState) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, &StatusResponse{
+			StatusText: "Kangal Controller is running",
+			Role:       state.Role(),
+			Identity:   state.Identity(),
+			Leader:     state.Leader(),
+		})
+	}
+}
+
 // RunMetricsServer starts Prometheus metrics server
-func RunMetricsServer(cfg Config, rr Runner, stopChan chan struct{}) error {
+func RunMetricsServer(cfg Config, rr Runner, state *LeaderState, stopChan chan struct{}) error {
 	r := chi.NewRouter()
 	// Define Middleware
 	r.Use(middleware.RequestID)
@@ -26,7 +46,7 @@ func RunMetricsServer(cfg Config, rr Runner, stopChan chan struct{}) error {
 
 	// Register Routes
 	r.Get("/", cHttp.LivenessHandler("Kangal Controller"))
-	r.Get("/status", cHttp.LivenessHandler("Kangal Controller"))
+	r.Get("/status", StatusHandler(state))
 	r.Handle("/metrics", promhttp.Handler())
 
 	// Run HTTP Server
@@ -35,7 +55,7 @@ func RunMetricsServer(cfg Config, rr Runner, stopChan chan struct{}) error {
 
 	go func() {
 		// Try and run http server, fail on error
-		if err := http.ListenAndServe(address, promhttp.Handler()); err != nil {
+		if err := http.ListenAndServe(address, r); err != nil {
 			rr.Logger.Error("Failed to run HTTP server", zap.Error(err))
 			close(stopChan)
 		}
@@ -0,0 +1,518 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+const (
+	// NotificationSignatureHeader is the header with the HMAC-SHA256 signature of the notification payload
+	NotificationSignatureHeader = "X-Kangal-Signature-256"
+	// NotificationEventHeader is the header with the notification event, e.g. loadtest.finished
+	NotificationEventHeader = "X-Kangal-Event"
+
+	eventReasonNotificationDelivered = "NotificationDelivered"
+	eventReasonNotificationFailed    = "NotificationFailed"
+
+	// notificationFailedMessage is the status message of failed attempts, details are only logged as the status
+	// is readable by anyone who can read the loadtest
+	notificationFailedMessage = "delivery attempt failed"
+)
+
+var (
+	// errNotificationNotSigned is returned when the notification has no signing secret, it is failed without being sent
+	errNotificationNotSigned = errors.New("notification signing secret is not configured")
+	// errAddressNotAllowed is returned when a callback resolves to a loopback, private or link-local address
+	errAddressNotAllowed = errors.New("callback address is not allowed")
+
+	// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, not covered by netip.Addr.IsPrivate
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// NotificationsConfig configures webhooks notified once loadtests are finished or errored
+type NotificationsConfig struct {
+	// Secret is the key the signing keys of callbacks are derived from, it is shared with the proxy returning
+	// the derived keys on loadtest creation. Callbacks are not delivered when it is empty
+	Secret string `envconfig:"NOTIFY_WEBHOOK_SECRET"`
+	// WebhooksFile is the path to a YAML file with webhooks notified of loadtests with matching tags
+	WebhooksFile string `envconfig:"NOTIFY_WEBHOOKS_FILE"`
+	// Timeout of each delivery attempt
+	Timeout time.Duration `envconfig:"NOTIFY_TIMEOUT" default:"10s"`
+	// DeliveryBudget is the time spent delivering notifications of a loadtest before the other loadtests are served
+	DeliveryBudget time.Duration `envconfig:"NOTIFY_DELIVERY_BUDGET" default:"1m"`
+	// MaxAttempts is the number of delivery attempts before the notification is failed
+	MaxAttempts int32 `envconfig:"NOTIFY_MAX_ATTEMPTS" default:"5"`
+	// RetryBackoff is the delay before the second attempt, it is doubled for each next attempt
+	RetryBackoff time.Duration `envconfig:"NOTIFY_RETRY_BACKOFF" default:"30s"`
+	Webhooks     []Webhook     `ignored:"true"`
+}
+
+// Webhook is an admin configured webhook notified of loadtests having all of its tags, webhooks without tags
+// are notified of all loadtests
+type Webhook struct {
+	URL  string                  `json:"url"`
+	Tags loadTestV1.LoadTestTags `json:"tags,omitempty"`
+	// Secret is the HMAC key signing the webhook payloads, NOTIFY_WEBHOOK_SECRET is used when it is empty
+	Secret string `json:"secret,omitempty"`
+}
+
+// matches returns true when the loadtest has all the webhook tags
+func (w Webhook) matches(tags loadTestV1.LoadTestTags) bool {
+	for key, value := range w.Tags {
+		if tags[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadWebhooks reads the webhooks configured by admin
+func LoadWebhooks(cfg NotificationsConfig) ([]Webhook, error) {
+	if cfg.WebhooksFile == "" {
+		return nil, nil
+	}
+
+	var webhooks []Webhook
+	if err := readTemplate(cfg.WebhooksFile, &webhooks); err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		if err := backends.ValidateCallbackURL(webhook.URL); err != nil {
+			return nil, fmt.Errorf("invalid webhook in %s: %w", cfg.WebhooksFile, err)
+		}
+		if webhook.Secret == "" && cfg.Secret == "" {
+			return nil, fmt.Errorf("webhook %s in %s has no secret and NOTIFY_WEBHOOK_SECRET is not set", webhook.URL, cfg.WebhooksFile)
+		}
+	}
+	return webhooks, nil
+}
+
+// NotificationPayload is the JSON body posted to webhooks once a loadtest is finished or errored
+type NotificationPayload struct {
+	// Event is loadtest.finished or loadtest.errored
+	Event          string                   `json:"event"`
+	Name           string                   `json:"name"`
+	Type           loadTestV1.LoadTestType  `json:"type"`
+	Phase          loadTestV1.LoadTestPhase `json:"phase"`
+	Tags           loadTestV1.LoadTestTags  `json:"tags,omitempty"`
+	Reason         string                   `json:"reason,omitempty"`
+	Message        string                   `json:"message,omitempty"`
+	StartTime      *metaV1.Time             `json:"startTime,omitempty"`
+	CompletionTime *metaV1.Time             `json:"completionTime,omitempty"`
+	Conditions     []metaV1.Condition       `json:"conditions,omitempty"`
+	ReportURL      string                   `json:"reportURL,omitempty"`
+	// Summary is the results summary of a finished loadtest, it is not set when the report can not be read
+	Summary *results.Summary `json:"summary,omitempty"`
+}
+
+// SignPayload returns the signature header value of the payload, hex encoded HMAC-SHA256 with sha256= prefix
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// queueNotifications adds pending notifications of the loadtest callbacks and matching webhooks
+// when the loadtest transitions to finished or errored
+func (c *Controller) queueNotifications(loadTest *loadTestV1.LoadTest, previousPhase loadTestV1.LoadTestPhase) {
+	status := &loadTest.Status
+	if !isCompleted(status.Phase) || isCompleted(previousPhase) || status.Notifications != nil {
+		return
+	}
+
+	urls := append([]string{}, loadTest.Spec.Callbacks...)
+	for _, webhook := range c.cfg.Notifications.Webhooks {
+		if webhook.matches(loadTest.Spec.Tags) {
+			urls = append(urls, webhook.URL)
+		}
+	}
+
+	queued := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		if queued[url] {
+			continue
+		}
+		queued[url] = true
+		status.Notifications = append(status.Notifications, loadTestV1.LoadTestNotification{
+			URL:   url,
+			State: loadTestV1.LoadTestNotificationPending,
+		})
+	}
+}
+
+// scheduleNotifications queues delivery of pending notifications once the loadtest results are evaluated,
+// notifications are delivered by the notification worker so slow receivers do not hold the sync handler
+func (c *Controller) scheduleNotifications(key string, loadTest *loadTestV1.LoadTest) {
+	if c.notifyQueue == nil || !resultsEvaluated(loadTest) {
+		return
+	}
+	if delay, ok := nextNotificationDelay(loadTest.Status.Notifications, time.Now()); ok {
+		c.notifyQueue.AddAfter(key, delay)
+	}
+}
+
+// runNotificationWorker delivers notifications of the loadtests on notifyQueue
+func (c *Controller) runNotificationWorker() {
+	for c.processNextNotification() {
+	}
+}
+
+// processNextNotification delivers notifications of the next loadtest on notifyQueue
+func (c *Controller) processNextNotification() bool {
+	obj, shutdown := c.notifyQueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.notifyQueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.notifyQueue.Forget(obj)
+		utilRuntime.HandleError(fmt.Errorf("expected string in notifyQueue but got %#v", obj))
+		return true
+	}
+
+	if err := c.deliverNotifications(key); err != nil {
+		c.notifyQueue.AddRateLimited(key)
+		utilRuntime.HandleError(fmt.Errorf("error delivering notifications of '%s': %w, requeuing", key, err))
+		return true
+	}
+	c.notifyQueue.Forget(obj)
+	return true
+}
+
+// deliverNotifications posts the payload to due notifications of the loadtest, the state is stored after each attempt.
+// Delivery stops once DeliveryBudget is spent and the remaining notifications are queued again
+func (c *Controller) deliverNotifications(key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilRuntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+	deadline := time.Now().Add(c.cfg.Notifications.DeliveryBudget)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.SyncHandlerTimeout)
+	loadTest, err := c.kangalClientSet.KangalV1().LoadTests().Get(ctx, name, metaV1.GetOptions{})
+	cancel()
+	if k8sAPIErrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !resultsEvaluated(loadTest) {
+		return nil
+	}
+
+	var payload []byte
+	for _, url := range dueNotifications(loadTest.Status.Notifications, time.Now()) {
+		if time.Until(deadline) < c.cfg.Notifications.Timeout {
+			c.logger.Debug("Notification delivery budget is spent, remaining notifications are queued again", zap.String("loadtest", name))
+			break
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(c.newNotificationPayload(loadTest))
+			if err != nil {
+				return fmt.Errorf("could not build notification payload: %w", err)
+			}
+		}
+
+		deliveryErr := c.postNotification(loadTest, url, payload)
+		loadTest, err = c.updateNotification(name, url, deliveryErr)
+		if k8sAPIErrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not store notification state: %w", err)
+		}
+	}
+
+	c.scheduleNotifications(key, loadTest)
+	return nil
+}
+
+// updateNotification stores the result of a delivery attempt on the latest version of the loadtest
+func (c *Controller) updateNotification(name, url string, deliveryErr error) (*loadTestV1.LoadTest, error) {
+	var updated *loadTestV1.LoadTest
+	var notification *loadTestV1.LoadTestNotification
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.SyncHandlerTimeout)
+		defer cancel()
+
+		loadTest, err := c.kangalClientSet.KangalV1().LoadTests().Get(ctx, name, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		notification = findPendingNotification(loadTest.Status.Notifications, url)
+		if notification == nil {
+			updated = loadTest
+			return nil
+		}
+		c.recordDelivery(notification, deliveryErr)
+
+		updated, err = c.kangalClientSet.KangalV1().LoadTests().UpdateStatus(ctx, loadTest, metaV1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if notification != nil {
+		c.reportDelivery(updated, *notification, deliveryErr)
+	}
+	return updated, nil
+}
+
+// recordDelivery updates the notification state after a delivery attempt
+func (c *Controller) recordDelivery(notification *loadTestV1.LoadTestNotification, err error) {
+	now := metaV1.Now()
+	notification.Attempts++
+	notification.LastAttemptTime = &now
+	notification.NextAttemptTime = nil
+
+	switch {
+	case err == nil:
+		notification.State = loadTestV1.LoadTestNotificationDelivered
+		notification.Message = ""
+	case errors.Is(err, errNotificationNotSigned):
+		notification.State = loadTestV1.LoadTestNotificationFailed
+		notification.Message = err.Error()
+	case notification.Attempts >= c.cfg.Notifications.MaxAttempts:
+		notification.State = loadTestV1.LoadTestNotificationFailed
+		notification.Message = notificationFailedMessage
+	default:
+		notification.Message = notificationFailedMessage
+		next := metaV1.NewTime(now.Add(c.cfg.Notifications.RetryBackoff << (notification.Attempts - 1)))
+		notification.NextAttemptTime = &next
+	}
+}
+
+// reportDelivery logs the stored delivery attempt and records an event once the notification is delivered or failed
+func (c *Controller) reportDelivery(loadTest *loadTestV1.LoadTest, notification loadTestV1.LoadTestNotification, err error) {
+	logger := c.logger.With(
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("url", notification.URL),
+		zap.Int32("attempts", notification.Attempts),
+	)
+
+	switch notification.State {
+	case loadTestV1.LoadTestNotificationDelivered:
+		logger.Info("Delivered loadtest notification")
+		c.recorder.Eventf(loadTest, coreV1.EventTypeNormal, eventReasonNotificationDelivered, "Notification delivered to %s", notification.URL)
+	case loadTestV1.LoadTestNotificationFailed:
+		logger.Warn("Failed to deliver loadtest notification", zap.Error(err))
+		c.recorder.Eventf(loadTest, coreV1.EventTypeWarning, eventReasonNotificationFailed, "Notification to %s failed after %d attempts", notification.URL, notification.Attempts)
+	default:
+		logger.Info("Loadtest notification delivery failed, retrying", zap.Timep("next_attempt", &notification.NextAttemptTime.Time), zap.Error(err))
+	}
+}
+
+// postNotification posts the signed payload, responses other than 2xx are errors
+func (c *Controller) postNotification(loadTest *loadTestV1.LoadTest, url string, payload []byte) error {
+	client, secret := c.notificationTarget(loadTest.GetName(), url)
+	if secret == "" {
+		return errNotificationNotSigned
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Notifications.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("could not build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(NotificationEventHeader, notificationEvent(loadTest))
+	req.Header.Set(NotificationSignatureHeader, SignPayload(secret, payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notificationTarget returns the client and the signing key of the notification url. Admin webhooks are signed with
+// their own secret and may be internal services, user callbacks are signed with a key derived for the loadtest callback
+// and can only reach public addresses
+func (c *Controller) notificationTarget(loadTestName, url string) (*http.Client, string) {
+	for _, webhook := range c.cfg.Notifications.Webhooks {
+		if webhook.URL != url {
+			continue
+		}
+		if webhook.Secret != "" {
+			return c.webhookClient, webhook.Secret
+		}
+		return c.webhookClient, c.cfg.Notifications.Secret
+	}
+
+	if c.cfg.Notifications.Secret == "" {
+		return c.callbackClient, ""
+	}
+	return c.callbackClient, backends.CallbackSecret(c.cfg.Notifications.Secret, loadTestName, url)
+}
+
+// newNotificationPayload returns the payload of a completed loadtest, the summary is read from the report of finished loadtest
+func (c *Controller) newNotificationPayload(loadTest *loadTestV1.LoadTest) NotificationPayload {
+	payload := NotificationPayload{
+		Event:          notificationEvent(loadTest),
+		Name:           loadTest.GetName(),
+		Type:           loadTest.Spec.Type,
+		Phase:          loadTest.Status.Phase,
+		Tags:           loadTest.Spec.Tags,
+		Reason:         loadTest.Status.Reason,
+		Message:        loadTest.Status.Message,
+		StartTime:      loadTest.Status.StartTime,
+		CompletionTime: loadTest.Status.CompletionTime,
+		Conditions:     loadTest.Status.Conditions,
+		ReportURL:      c.reportURL(loadTest.GetName()),
+	}
+
+	if c.reportReader == nil || loadTest.Status.Phase != loadTestV1.LoadTestFinished {
+		return payload
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.SyncHandlerTimeout)
+	defer cancel()
+
+	summary, err := c.reportReader.Summary(ctx, loadTest.Spec.Type, loadTest.GetName())
+	if err != nil {
+		c.logger.Debug("Notification is sent without summary", zap.String("loadtest", loadTest.GetName()), zap.Error(err))
+		return payload
+	}
+	payload.Summary = &summary
+	return payload
+}
+
+func notificationEvent(loadTest *loadTestV1.LoadTest) string {
+	return "loadtest." + loadTest.Status.Phase.String()
+}
+
+func isCompleted(phase loadTestV1.LoadTestPhase) bool {
+	return phase == loadTestV1.LoadTestFinished || phase == loadTestV1.LoadTestErrored
+}
+
+// nextNotificationDelay returns the delay until the next pending notification is due, false when none is pending
+func nextNotificationDelay(notifications []loadTestV1.LoadTestNotification, now time.Time) (time.Duration, bool) {
+	var delay time.Duration
+	pending := false
+	for _, notification := range notifications {
+		if notification.State != loadTestV1.LoadTestNotificationPending {
+			continue
+		}
+
+		var due time.Duration
+		if notification.NextAttemptTime != nil && notification.NextAttemptTime.Time.After(now) {
+			due = notification.NextAttemptTime.Time.Sub(now)
+		}
+		if !pending || due < delay {
+			delay = due
+		}
+		pending = true
+	}
+	return delay, pending
+}
+
+// dueNotifications returns the urls of pending notifications to attempt now
+func dueNotifications(notifications []loadTestV1.LoadTestNotification, now time.Time) []string {
+	var urls []string
+	for _, notification := range notifications {
+		if notification.State == loadTestV1.LoadTestNotificationPending &&
+			(notification.NextAttemptTime == nil || !now.Before(notification.NextAttemptTime.Time)) {
+			urls = append(urls, notification.URL)
+		}
+	}
+	return urls
+}
+
+func findPendingNotification(notifications []loadTestV1.LoadTestNotification, url string) *loadTestV1.LoadTestNotification {
+	for i := range notifications {
+		if notifications[i].URL == url && notifications[i].State == loadTestV1.LoadTestNotificationPending {
+			return &notifications[i]
+		}
+	}
+	return nil
+}
+
+// newCallbackClient returns the client posting to user callbacks, it does not follow redirects, ignores proxy
+// settings and refuses to connect to loopback, private, link-local and multicast addresses, so callbacks can not
+// reach cloud metadata, the kube API or cluster services
+func newCallbackClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   denyInternalAddress,
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: noRedirect,
+	}
+}
+
+// newWebhookClient returns the client posting to admin webhooks, it does not follow redirects
+func newWebhookClient() *http.Client {
+	return &http.Client{CheckRedirect: noRedirect}
+}
+
+func noRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// denyInternalAddress is the dialer control refusing connections to addresses callbacks are not allowed to reach,
+// it checks the resolved address so DNS names pointing to internal addresses are refused too
+func denyInternalAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsMulticast() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, addr)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+	"github.com/hellofresh/kangal/pkg/report"
+)
+
+func TestLoadWebhooks(t *testing.T) {
+	webhooks, err := LoadWebhooks(NotificationsConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, webhooks)
+
+	webhooks, err = LoadWebhooks(NotificationsConfig{Secret: "webhook-secret", WebhooksFile: writeTemplate(t, `
+- url: https://ci.example.com/hooks/kangal
+- url: https://chat.example.com/checkout
+  tags:
+    team: checkout
+`)})
+	require.NoError(t, err)
+	assert.Equal(t, []Webhook{
+		{URL: "https://ci.example.com/hooks/kangal"},
+		{URL: "https://chat.example.com/checkout", Tags: loadTestV1.LoadTestTags{"team": "checkout"}},
+	}, webhooks)
+
+	_, err = LoadWebhooks(NotificationsConfig{Secret: "webhook-secret", WebhooksFile: writeTemplate(t, `- url: /hooks/kangal`)})
+	assert.Error(t, err)
+
+	webhooks, err = LoadWebhooks(NotificationsConfig{WebhooksFile: writeTemplate(t, `
+- url: https://ci.example.com/hooks/kangal
+  secret: ci-secret
+`)})
+	require.NoError(t, err)
+	assert.Equal(t, []Webhook{{URL: "https://ci.example.com/hooks/kangal", Secret: "ci-secret"}}, webhooks)
+
+	// webhooks are always signed
+	_, err = LoadWebhooks(NotificationsConfig{WebhooksFile: writeTemplate(t, `- url: https://ci.example.com/hooks/kangal`)})
+	assert.Error(t, err)
+}
+
+func TestQueueNotifications(t *testing.T) {
+	c := &Controller{cfg: Config{Notifications: NotificationsConfig{Webhooks: []Webhook{
+		{URL: "https://ci.example.com/hooks/kangal"},
+		{URL: "https://chat.example.com/checkout", Tags: loadTestV1.LoadTestTags{"team": "checkout"}},
+		{URL: "https://chat.example.com/payments", Tags: loadTestV1.LoadTestTags{"team": "payments"}},
+	}}}}
+
+	newLoadTest := func(phase loadTestV1.LoadTestPhase) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			Spec: loadTestV1.LoadTestSpec{
+				Tags:      loadTestV1.LoadTestTags{"team": "checkout"},
+				Callbacks: []string{"https://ci.example.com/hooks/kangal", "https://ci.example.com/jobs/42"},
+			},
+			Status: loadTestV1.LoadTestStatus{Phase: phase},
+		}
+	}
+
+	t.Run("transition to finished", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished)
+		c.queueNotifications(loadTest, loadTestV1.LoadTestRunning)
+
+		var urls []string
+		for _, notification := range loadTest.Status.Notifications {
+			assert.Equal(t, loadTestV1.LoadTestNotificationPending, notification.State)
+			urls = append(urls, notification.URL)
+		}
+		assert.Equal(t, []string{
+			"https://ci.example.com/hooks/kangal",
+			"https://ci.example.com/jobs/42",
+			"https://chat.example.com/checkout",
+		}, urls)
+	})
+
+	t.Run("already completed", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestErrored)
+		c.queueNotifications(loadTest, loadTestV1.LoadTestErrored)
+		assert.Empty(t, loadTest.Status.Notifications)
+	})
+
+	t.Run("running", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestRunning)
+		c.queueNotifications(loadTest, loadTestV1.LoadTestStarting)
+		assert.Empty(t, loadTest.Status.Notifications)
+	})
+}
+
+func TestDeliverNotifications(t *testing.T) {
+	ctx := context.Background()
+	k6Summary := `{"metrics":{"http_req_duration":{"values":{"p(95)":420}},"http_req_failed":{"values":{"rate":0.002}},"http_reqs":{"values":{"count":1200,"rate":120}}}}`
+
+	type request struct {
+		event     string
+		signature string
+		body      []byte
+	}
+
+	newServer := func(t *testing.T, statusCode int) (*httptest.Server, *[]request) {
+		var requests []request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			requests = append(requests, request{
+				event:     r.Header.Get(NotificationEventHeader),
+				signature: r.Header.Get(NotificationSignatureHeader),
+				body:      body,
+			})
+			w.WriteHeader(statusCode)
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+
+	newLoadTest := func(url string) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+			Spec: loadTestV1.LoadTestSpec{
+				Type:      loadTestV1.LoadTestTypeK6,
+				Tags:      loadTestV1.LoadTestTags{"team": "checkout"},
+				Callbacks: []string{url},
+			},
+			Status: loadTestV1.LoadTestStatus{
+				Phase:         loadTestV1.LoadTestFinished,
+				Notifications: []loadTestV1.LoadTestNotification{{URL: url, State: loadTestV1.LoadTestNotificationPending}},
+			},
+		}
+	}
+
+	newController := func(t *testing.T, server *httptest.Server, loadTest *loadTestV1.LoadTest) *Controller {
+		return &Controller{
+			cfg: Config{
+				KangalProxyURL:     "http://kangal-proxy.local",
+				SyncHandlerTimeout: time.Minute,
+				Notifications: NotificationsConfig{
+					Secret:         "webhook-secret",
+					Timeout:        5 * time.Second,
+					DeliveryBudget: time.Minute,
+					MaxAttempts:    2,
+					RetryBackoff:   time.Minute,
+				},
+			},
+			kangalClientSet: fake.NewSimpleClientset(loadTest),
+			notifyQueue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+			recorder:        record.NewFakeRecorder(10),
+			reportReader:    &fakeReportReader{report: k6Summary},
+			callbackClient:  server.Client(),
+			webhookClient:   server.Client(),
+			logger:          zaptest.NewLogger(t),
+		}
+	}
+
+	getNotification := func(t *testing.T, c *Controller) loadTestV1.LoadTestNotification {
+		loadTest, err := c.kangalClientSet.KangalV1().LoadTests().Get(ctx, "loadtest-name", metaV1.GetOptions{})
+		require.NoError(t, err)
+		require.Len(t, loadTest.Status.Notifications, 1)
+		return loadTest.Status.Notifications[0]
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusNoContent)
+		c := newController(t, server, newLoadTest(server.URL))
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+
+		require.Len(t, *requests, 1)
+		req := (*requests)[0]
+		assert.Equal(t, "loadtest.finished", req.event)
+		assert.Equal(t, SignPayload(backends.CallbackSecret("webhook-secret", "loadtest-name", server.URL), req.body), req.signature)
+
+		var payload NotificationPayload
+		require.NoError(t, json.Unmarshal(req.body, &payload))
+		assert.Equal(t, "loadtest-name", payload.Name)
+		assert.Equal(t, loadTestV1.LoadTestTags{"team": "checkout"}, payload.Tags)
+		assert.Equal(t, "http://kangal-proxy.local/load-test/loadtest-name/report", payload.ReportURL)
+		require.NotNil(t, payload.Summary)
+		assert.Equal(t, int64(1200), payload.Summary.Requests)
+
+		notification := getNotification(t, c)
+		assert.Equal(t, loadTestV1.LoadTestNotificationDelivered, notification.State)
+		assert.Equal(t, int32(1), notification.Attempts)
+		assert.NotNil(t, notification.LastAttemptTime)
+		assert.Zero(t, c.notifyQueue.Len())
+	})
+
+	t.Run("admin webhook", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusOK)
+		loadTest := newLoadTest(server.URL)
+		loadTest.Spec.Callbacks = nil
+		c := newController(t, server, loadTest)
+		c.cfg.Notifications.Webhooks = []Webhook{{URL: server.URL, Secret: "admin-secret"}}
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		require.Len(t, *requests, 1)
+		assert.Equal(t, SignPayload("admin-secret", (*requests)[0].body), (*requests)[0].signature)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusBadGateway)
+		c := newController(t, server, newLoadTest(server.URL))
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		notification := getNotification(t, c)
+		assert.Equal(t, loadTestV1.LoadTestNotificationPending, notification.State)
+		assert.Equal(t, notificationFailedMessage, notification.Message)
+		require.NotNil(t, notification.NextAttemptTime)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), notification.NextAttemptTime.Time, 5*time.Second)
+
+		// next attempt is not due yet
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		assert.Len(t, *requests, 1)
+
+		loadTest, err := c.kangalClientSet.KangalV1().LoadTests().Get(ctx, "loadtest-name", metaV1.GetOptions{})
+		require.NoError(t, err)
+		past := metaV1.NewTime(time.Now().Add(-time.Second))
+		loadTest.Status.Notifications[0].NextAttemptTime = &past
+		_, err = c.kangalClientSet.KangalV1().LoadTests().UpdateStatus(ctx, loadTest, metaV1.UpdateOptions{})
+		require.NoError(t, err)
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		assert.Len(t, *requests, 2)
+		notification = getNotification(t, c)
+		assert.Equal(t, loadTestV1.LoadTestNotificationFailed, notification.State)
+		assert.Equal(t, int32(2), notification.Attempts)
+		assert.Nil(t, notification.NextAttemptTime)
+	})
+
+	t.Run("waits for thresholds", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusOK)
+		loadTest := newLoadTest(server.URL)
+		loadTest.Spec.Thresholds = &loadTestV1.LoadTestThresholds{P95Latency: "500ms"}
+		c := newController(t, server, loadTest)
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		assert.Empty(t, *requests)
+
+		setPassedCondition(&loadTest.Status, metaV1.ConditionTrue, loadTestV1.LoadTestReasonThresholdsPassed, "ok")
+		_, err := c.kangalClientSet.KangalV1().LoadTests().UpdateStatus(ctx, loadTest, metaV1.UpdateOptions{})
+		require.NoError(t, err)
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		assert.Len(t, *requests, 1)
+	})
+
+	t.Run("report is not available", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusOK)
+		c := newController(t, server, newLoadTest(server.URL))
+		c.reportReader = &fakeReportReader{err: report.ErrReportNotFound}
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		require.Len(t, *requests, 1)
+
+		var payload NotificationPayload
+		require.NoError(t, json.Unmarshal((*requests)[0].body, &payload))
+		assert.Nil(t, payload.Summary)
+	})
+
+	t.Run("secret is not configured", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusOK)
+		c := newController(t, server, newLoadTest(server.URL))
+		c.cfg.Notifications.Secret = ""
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		assert.Empty(t, *requests)
+
+		notification := getNotification(t, c)
+		assert.Equal(t, loadTestV1.LoadTestNotificationFailed, notification.State)
+		assert.Equal(t, errNotificationNotSigned.Error(), notification.Message)
+	})
+
+	t.Run("budget is spent", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusOK)
+		c := newController(t, server, newLoadTest(server.URL))
+		c.cfg.Notifications.DeliveryBudget = time.Second
+
+		require.NoError(t, c.deliverNotifications("loadtest-name"))
+		assert.Empty(t, *requests)
+		assert.Equal(t, loadTestV1.LoadTestNotificationPending, getNotification(t, c).State)
+		assert.Equal(t, 1, c.notifyQueue.Len())
+	})
+
+	t.Run("loadtest is deleted", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusOK)
+		c := newController(t, server, newLoadTest(server.URL))
+
+		require.NoError(t, c.deliverNotifications("other-loadtest"))
+		assert.Empty(t, *requests)
+	})
+}
+
+func TestNotificationClients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := newCallbackClient().Post(server.URL, "application/json", nil)
+	assert.ErrorIs(t, err, errAddressNotAllowed)
+
+	resp, err := newWebhookClient().Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+}
+
+func TestDenyInternalAddress(t *testing.T) {
+	for address, allowed := range map[string]bool{
+		"93.184.216.34:443":           true,
+		"[2606:2800:220:1::]:443":     true,
+		"127.0.0.1:80":                false,
+		"10.96.0.1:443":               false,
+		"172.16.0.10:80":              false,
+		"192.168.1.1:80":              false,
+		"169.254.169.254:80":          false,
+		"100.64.0.1:80":               false,
+		"0.0.0.0:80":                  false,
+		"[::1]:80":                    false,
+		"[fd00::1]:80":                false,
+		"[fe80::1]:80":                false,
+		"[::ffff:169.254.169.254]:80": false,
+	} {
+		err := denyInternalAddress("tcp", address, nil)
+		if allowed {
+			assert.NoError(t, err, address)
+		} else {
+			assert.ErrorIs(t, err, errAddressNotAllowed, address)
+		}
+	}
+}
+
+func TestSignPayload(t *testing.T) {
+	// echo -n '{"name":"loadtest-name"}' | openssl dgst -sha256 -hmac webhook-secret
+	assert.Equal(t,
+		"sha256=ed61efd426feae1371dc2f23d40d1781a494448c81ca9dfa62d49cf7981284b6",
+		SignPayload("webhook-secret", []byte(`{"name":"loadtest-name"}`)),
+	)
+}
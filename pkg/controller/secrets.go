@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// syncSecretEnvVars copies values of loadtest secret env vars from Secrets in the secrets namespace
+// to a Secret in the loadtest namespace, so loadtest pods can reference it. Loadtest is marked as errored
+// when the values can not be copied because of the loadtest spec, e.g. referenced Secret does not exist
+func (c *Controller) syncSecretEnvVars(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if len(loadTest.Spec.SecretEnvVars) == 0 || loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		return nil
+	}
+
+	namespace := loadTest.Status.Namespace
+	name := backends.SecretEnvVarsName(*loadTest)
+
+	_, err := c.kubeClientSet.CoreV1().Secrets(namespace).Get(ctx, name, metaV1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !k8sAPIErrors.IsNotFound(err) {
+		return err
+	}
+
+	if c.cfg.SecretEnvVarsNamespace == "" {
+		c.setSecretEnvVarsFailed(loadTest, "secret env vars are not enabled, SECRET_ENV_VARS_NAMESPACE is not set")
+		return nil
+	}
+
+	data := make(map[string][]byte, len(loadTest.Spec.SecretEnvVars))
+	sources := map[string]*coreV1.Secret{}
+	for _, envVar := range loadTest.Spec.SecretEnvVars {
+		source, ok := sources[envVar.SecretName]
+		if !ok {
+			source, err = c.kubeClientSet.CoreV1().Secrets(c.cfg.SecretEnvVarsNamespace).Get(ctx, envVar.SecretName, metaV1.GetOptions{})
+			if k8sAPIErrors.IsNotFound(err) {
+				c.setSecretEnvVarsFailed(loadTest, fmt.Sprintf("secret %s referenced by env var %s does not exist", envVar.SecretName, envVar.Name))
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			sources[envVar.SecretName] = source
+		}
+
+		value, ok := source.Data[envVar.Key]
+		if !ok {
+			c.setSecretEnvVarsFailed(loadTest, fmt.Sprintf("secret %s referenced by env var %s does not have key %s", envVar.SecretName, envVar.Name, envVar.Key))
+			return nil
+		}
+		data[envVar.Name] = value
+	}
+
+	secret := &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          backends.LoadTestLabels(*loadTest, nil),
+			OwnerReferences: backends.LoadTestOwnerReferences(*loadTest),
+		},
+		Type: coreV1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	_, err = c.kubeClientSet.CoreV1().Secrets(namespace).Create(ctx, secret, metaV1.CreateOptions{})
+	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	c.logger.Debug("Copied secret env vars",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("namespace", namespace),
+		zap.Int("count", len(data)),
+	)
+
+	return nil
+}
+
+func (c *Controller) setSecretEnvVarsFailed(loadTest *loadTestV1.LoadTest, message string) {
+	backends.SetLoadTestErrored(&loadTest.Status, loadTestV1.LoadTestReasonSecretEnvVarsFailed, message)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, loadTestV1.LoadTestReasonSecretEnvVarsFailed, message)
+}
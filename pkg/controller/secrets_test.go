@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSyncSecretEnvVars(t *testing.T) {
+	ctx := context.Background()
+
+	newLoadTest := func(secretEnvVars ...loadTestV1.LoadTestSecretEnvVar) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+			Spec:       loadTestV1.LoadTestSpec{SecretEnvVars: secretEnvVars},
+			Status:     loadTestV1.LoadTestStatus{Namespace: "loadtest-name"},
+		}
+	}
+
+	newController := func(kubeClientSet *k8sfake.Clientset, secretsNamespace string) *Controller {
+		return &Controller{
+			cfg:           Config{SecretEnvVarsNamespace: secretsNamespace},
+			kubeClientSet: kubeClientSet,
+			recorder:      record.NewFakeRecorder(10),
+			logger:        zaptest.NewLogger(t),
+		}
+	}
+
+	source := &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "api-credentials", Namespace: "loadtest-secrets"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t"), "unused": []byte("other")},
+	}
+
+	t.Run("copies referenced keys", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestSecretEnvVar{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"})
+		kubeClientSet := k8sfake.NewSimpleClientset(source)
+
+		require.NoError(t, newController(kubeClientSet, "loadtest-secrets").syncSecretEnvVars(ctx, loadTest))
+
+		secret, err := kubeClientSet.CoreV1().Secrets("loadtest-name").Get(ctx, "loadtest-secret-env-vars", metaV1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"API_TOKEN": []byte("s3cr3t")}, secret.Data)
+		assert.Equal(t, "loadtest-name", secret.Labels[loadTestV1.LoadTestNameLabel])
+		assert.Empty(t, loadTest.Status.Phase)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestSecretEnvVar{Name: "API_TOKEN", SecretName: "api-credentials", Key: "password"})
+		kubeClientSet := k8sfake.NewSimpleClientset(source)
+
+		require.NoError(t, newController(kubeClientSet, "loadtest-secrets").syncSecretEnvVars(ctx, loadTest))
+
+		assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+		assert.Equal(t, loadTestV1.LoadTestReasonSecretEnvVarsFailed, loadTest.Status.Reason)
+		assert.Equal(t, "secret api-credentials referenced by env var API_TOKEN does not have key password", loadTest.Status.Message)
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestSecretEnvVar{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"})
+
+		require.NoError(t, newController(k8sfake.NewSimpleClientset(), "loadtest-secrets").syncSecretEnvVars(ctx, loadTest))
+
+		assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+		assert.Equal(t, "secret api-credentials referenced by env var API_TOKEN does not exist", loadTest.Status.Message)
+	})
+
+	t.Run("secret from other namespace is not used", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestSecretEnvVar{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"})
+
+		require.NoError(t, newController(k8sfake.NewSimpleClientset(source), "other-secrets").syncSecretEnvVars(ctx, loadTest))
+
+		assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestSecretEnvVar{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"})
+
+		require.NoError(t, newController(k8sfake.NewSimpleClientset(source), "").syncSecretEnvVars(ctx, loadTest))
+
+		assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+		assert.Equal(t, loadTestV1.LoadTestReasonSecretEnvVarsFailed, loadTest.Status.Reason)
+	})
+}
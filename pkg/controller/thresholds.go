@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+// reportUploadTimeout is how long the controller waits for the report after the loadtest has finished
+const reportUploadTimeout = 5 * time.Minute
+
+// ReportReader reads results of reports uploaded by loadtests
+type ReportReader interface {
+	// Summary returns the results of the loadtest report, report.ErrReportNotFound when it was not uploaded
+	Summary(ctx context.Context, loadTestType loadTestV1.LoadTestType, loadTestName string) (results.Summary, error)
+}
+
+// evaluateThresholds evaluates thresholds of a finished loadtest against its report once
+// and records the result in the Passed condition
+func (c *Controller) evaluateThresholds(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	status := &loadTest.Status
+
+	if loadTest.Spec.Thresholds == nil || status.Phase != loadTestV1.LoadTestFinished ||
+		meta.FindStatusCondition(status.Conditions, loadTestV1.LoadTestConditionPassed.String()) != nil {
+		return nil
+	}
+
+	thresholds, err := results.ParseThresholds(loadTest.Spec.Thresholds)
+	if err != nil {
+		c.setReportUnavailable(loadTest, fmt.Sprintf("invalid thresholds: %s", err))
+		return nil
+	}
+
+	if c.reportReader == nil {
+		c.setReportUnavailable(loadTest, "report storage is not configured")
+		return nil
+	}
+
+	summary, err := c.reportReader.Summary(ctx, loadTest.Spec.Type, loadTest.GetName())
+	switch {
+	case errors.Is(err, report.ErrReportNotFound):
+		// report may still be uploading, it is read again on next sync
+		if status.CompletionTime == nil || time.Since(status.CompletionTime.Time) < reportUploadTimeout {
+			return nil
+		}
+		c.setReportUnavailable(loadTest, fmt.Sprintf("report was not uploaded within %s after the loadtest finished", reportUploadTimeout))
+		return nil
+	case errors.Is(err, report.ErrResultsUnreadable):
+		c.setReportUnavailable(loadTest, err.Error())
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read report: %w", err)
+	}
+
+	passed, message := results.Evaluate(thresholds, summary)
+
+	c.logger.Info("Evaluated loadtest thresholds",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.Bool("passed", passed),
+		zap.String("results", message),
+	)
+
+	if passed {
+		setPassedCondition(status, metaV1.ConditionTrue, loadTestV1.LoadTestReasonThresholdsPassed, message)
+		c.recorder.Event(loadTest, coreV1.EventTypeNormal, loadTestV1.LoadTestReasonThresholdsPassed, message)
+		return nil
+	}
+
+	setPassedCondition(status, metaV1.ConditionFalse, loadTestV1.LoadTestReasonThresholdsFailed, message)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, loadTestV1.LoadTestReasonThresholdsFailed, message)
+	return nil
+}
+
+// setReportUnavailable records that thresholds could not be evaluated, so they are not evaluated again
+func (c *Controller) setReportUnavailable(loadTest *loadTestV1.LoadTest, message string) {
+	setPassedCondition(&loadTest.Status, metaV1.ConditionUnknown, loadTestV1.LoadTestReasonReportUnavailable, message)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, loadTestV1.LoadTestReasonReportUnavailable, message)
+}
+
+func setPassedCondition(status *loadTestV1.LoadTestStatus, conditionStatus metaV1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metaV1.Condition{
+		Type:    loadTestV1.LoadTestConditionPassed.String(),
+		Status:  conditionStatus,
+		Reason:  reason,
+		Message: message,
+	})
+}
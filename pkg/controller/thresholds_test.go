@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+// fakeReportReader parses the report as the results file of the loadtest type
+type fakeReportReader struct {
+	report string
+	err    error
+}
+
+func (r *fakeReportReader) Summary(_ context.Context, loadTestType loadTestV1.LoadTestType, _ string) (results.Summary, error) {
+	if r.err != nil {
+		return results.Summary{}, r.err
+	}
+	summary, err := results.Parse(loadTestType, strings.NewReader(r.report))
+	if err != nil {
+		return results.Summary{}, fmt.Errorf("%w: %w", report.ErrResultsUnreadable, err)
+	}
+	return summary, nil
+}
+
+func TestEvaluateThresholds(t *testing.T) {
+	ctx := context.Background()
+	k6Summary := `{"metrics":{"http_req_duration":{"values":{"p(95)":420}},"http_req_failed":{"values":{"rate":0.002}},"http_reqs":{"values":{"count":1200,"rate":120}}}}`
+
+	newLoadTest := func(phase loadTestV1.LoadTestPhase, finishedAgo time.Duration) *loadTestV1.LoadTest {
+		completionTime := metaV1.NewTime(time.Now().Add(-finishedAgo))
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+			Spec: loadTestV1.LoadTestSpec{
+				Type:       loadTestV1.LoadTestTypeK6,
+				Thresholds: &loadTestV1.LoadTestThresholds{P95Latency: "500ms", ErrorRate: "0.01", MinThroughput: "100"},
+			},
+			Status: loadTestV1.LoadTestStatus{
+				Phase:          phase,
+				CompletionTime: &completionTime,
+			},
+		}
+	}
+
+	newController := func(reportReader ReportReader) *Controller {
+		return &Controller{
+			recorder:     record.NewFakeRecorder(10),
+			reportReader: reportReader,
+			logger:       zaptest.NewLogger(t),
+		}
+	}
+
+	passedCondition := func(loadTest *loadTestV1.LoadTest) *metaV1.Condition {
+		return meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionPassed.String())
+	}
+
+	t.Run("passed", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, newController(&fakeReportReader{report: k6Summary}).evaluateThresholds(ctx, loadTest))
+
+		condition := passedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionTrue, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonThresholdsPassed, condition.Reason)
+		assert.Equal(t, "p95Latency 420ms (max 500ms) ok, errorRate 0.002 (max 0.01) ok, throughput 120/s (min 100/s) ok", condition.Message)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+		loadTest.Spec.Thresholds.P95Latency = "300ms"
+
+		require.NoError(t, newController(&fakeReportReader{report: k6Summary}).evaluateThresholds(ctx, loadTest))
+
+		condition := passedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionFalse, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonThresholdsFailed, condition.Reason)
+		assert.Contains(t, condition.Message, "p95Latency 420ms (max 300ms) failed")
+	})
+
+	t.Run("evaluated once", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+		setPassedCondition(&loadTest.Status, metaV1.ConditionTrue, loadTestV1.LoadTestReasonThresholdsPassed, "evaluated")
+
+		require.NoError(t, newController(&fakeReportReader{err: errors.New("should not be read")}).evaluateThresholds(ctx, loadTest))
+		assert.Equal(t, "evaluated", passedCondition(loadTest).Message)
+	})
+
+	t.Run("not finished", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestRunning, 0)
+
+		require.NoError(t, newController(&fakeReportReader{report: k6Summary}).evaluateThresholds(ctx, loadTest))
+		assert.Nil(t, passedCondition(loadTest))
+	})
+
+	t.Run("report not uploaded yet", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, newController(&fakeReportReader{err: report.ErrReportNotFound}).evaluateThresholds(ctx, loadTest))
+		assert.Nil(t, passedCondition(loadTest))
+	})
+
+	t.Run("report not uploaded", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Hour)
+
+		require.NoError(t, newController(&fakeReportReader{err: report.ErrReportNotFound}).evaluateThresholds(ctx, loadTest))
+
+		condition := passedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionUnknown, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestReasonReportUnavailable, condition.Reason)
+	})
+
+	t.Run("invalid report", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		require.NoError(t, newController(&fakeReportReader{report: "<html/>"}).evaluateThresholds(ctx, loadTest))
+
+		condition := passedCondition(loadTest)
+		require.NotNil(t, condition)
+		assert.Equal(t, metaV1.ConditionUnknown, condition.Status)
+	})
+
+	t.Run("storage error is retried", func(t *testing.T) {
+		loadTest := newLoadTest(loadTestV1.LoadTestFinished, time.Minute)
+
+		assert.Error(t, newController(&fakeReportReader{err: errors.New("bucket is not reachable")}).evaluateThresholds(ctx, loadTest))
+		assert.Nil(t, passedCondition(loadTest))
+	})
+}
@@ -8,11 +8,17 @@ import (
 	"strings"
 
 	"github.com/technosophos/moniker"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	maxTagLength = 63 // K8s limit.
+
+	// TransformedAnnotation marks LoadTest which spec was already transformed by its backend
+	TransformedAnnotation = "kangal.hellofresh.com/transformed"
+	// LoadTestNameLabel is set on every resource created for LoadTest, its value is LoadTest name
+	LoadTestNameLabel = "kangal.hellofresh.com/loadtest"
 )
 
 // Possible load test errors
@@ -26,20 +32,11 @@ func BuildLoadTestObject(spec LoadTestSpec) (*LoadTest, error) {
 
 	name := "loadtest-" + generatedName
 
-	labels := map[string]string{
-		"test-file-hash": getHashFromBytes(spec.TestFile),
-	}
-
-	for tagName, tagValue := range spec.Tags {
-		tagName = fmt.Sprintf("test-tag-%s", tagName)
-		labels[tagName] = tagValue
-	}
-
 	return &LoadTest{
 		TypeMeta: metaV1.TypeMeta{},
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:   name,
-			Labels: labels,
+			Labels: BuildLoadTestLabels(spec),
 		},
 		Spec: spec,
 		Status: LoadTestStatus{
@@ -48,6 +45,33 @@ func BuildLoadTestObject(spec LoadTestSpec) (*LoadTest, error) {
 	}, nil
 }
 
+// BuildLoadTestLabels returns labels identifying LoadTest test file and tags
+func BuildLoadTestLabels(spec LoadTestSpec) map[string]string {
+	labels := map[string]string{
+		"test-file-hash": getHashFromBytes(spec.TestFile),
+	}
+
+	for tagName, tagValue := range spec.Tags {
+		tagName = fmt.Sprintf("test-tag-%s", tagName)
+		labels[tagName] = tagValue
+	}
+
+	return labels
+}
+
+// IsTransformed returns true when LoadTest spec was already transformed by its backend
+func IsTransformed(loadTest *LoadTest) bool {
+	return loadTest.GetAnnotations()[TransformedAnnotation] == "true"
+}
+
+// SetTransformed marks LoadTest spec as transformed by its backend
+func SetTransformed(loadTest *LoadTest) {
+	if loadTest.Annotations == nil {
+		loadTest.Annotations = map[string]string{}
+	}
+	loadTest.Annotations[TransformedAnnotation] = "true"
+}
+
 // LoadTestTagsFromString builds tags from string.
 func LoadTestTagsFromString(tagsStr string) (LoadTestTags, error) {
 	if tagsStr == "" {
@@ -90,6 +114,124 @@ func LoadTestTagsFromString(tagsStr string) (LoadTestTags, error) {
 	return tags, nil
 }
 
+// LoadTestResourcesFromString builds resources from string, e.g. "cpuRequests:500m,memoryLimits:1Gi".
+// Empty string is a valid value and returns nil resources, so backend defaults are used.
+func LoadTestResourcesFromString(resourcesStr string) (*LoadTestResources, error) {
+	resourcesStr = strings.TrimSpace(resourcesStr)
+	if resourcesStr == "" {
+		return nil, nil
+	}
+
+	resources := &LoadTestResources{}
+	for _, pair := range strings.Split(resourcesStr, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) < 1 {
+			continue
+		}
+
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[1])) == 0 {
+			return nil, fmt.Errorf("%w for %q", ErrResourceMissingValue, parts[0])
+		}
+
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return nil, fmt.Errorf("%w %q for %q", ErrResourceInvalidQuantity, value, name)
+		}
+
+		switch name {
+		case "cpuLimits":
+			resources.CPULimits = value
+		case "cpuRequests":
+			resources.CPURequests = value
+		case "memoryLimits":
+			resources.MemoryLimits = value
+		case "memoryRequests":
+			resources.MemoryRequests = value
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrResourceUnknownName, name)
+		}
+	}
+
+	return resources, nil
+}
+
+// LoadTestThresholdsFromString builds thresholds from string, e.g. "p95Latency:500ms,errorRate:0.01,minThroughput:100".
+// Empty string is a valid value and returns nil thresholds, so the loadtest is not evaluated.
+// Threshold values are validated by backends together with the rest of the spec
+func LoadTestThresholdsFromString(thresholdsStr string) (*LoadTestThresholds, error) {
+	thresholdsStr = strings.TrimSpace(thresholdsStr)
+	if thresholdsStr == "" {
+		return nil, nil
+	}
+
+	thresholds := &LoadTestThresholds{}
+	for _, pair := range strings.Split(thresholdsStr, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) < 1 {
+			continue
+		}
+
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[1])) == 0 {
+			return nil, fmt.Errorf("%w for %q", ErrThresholdMissingValue, parts[0])
+		}
+
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+
+		switch name {
+		case "p95Latency":
+			thresholds.P95Latency = value
+		case "errorRate":
+			thresholds.ErrorRate = value
+		case "minThroughput":
+			thresholds.MinThroughput = value
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrThresholdUnknownName, name)
+		}
+	}
+
+	return thresholds, nil
+}
+
+// LoadTestSecretEnvVarsFromString builds secret env vars from string, e.g. "API_TOKEN:api-credentials/token,DB_PASSWORD:db/password".
+// Names and references are validated by backends together with the rest of the spec
+func LoadTestSecretEnvVarsFromString(secretEnvVarsStr string) ([]LoadTestSecretEnvVar, error) {
+	secretEnvVarsStr = strings.TrimSpace(secretEnvVarsStr)
+	if secretEnvVarsStr == "" {
+		return nil, nil
+	}
+
+	var secretEnvVars []LoadTestSecretEnvVar
+	for _, pair := range strings.Split(secretEnvVarsStr, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) < 1 {
+			continue
+		}
+
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w, got %q", ErrSecretEnvVarInvalidFormat, pair)
+		}
+
+		ref := strings.SplitN(strings.TrimSpace(parts[1]), "/", 2)
+		if len(ref) != 2 {
+			return nil, fmt.Errorf("%w, got %q", ErrSecretEnvVarInvalidFormat, pair)
+		}
+
+		secretEnvVars = append(secretEnvVars, LoadTestSecretEnvVar{
+			Name:       strings.TrimSpace(parts[0]),
+			SecretName: strings.TrimSpace(ref[0]),
+			Key:        strings.TrimSpace(ref[1]),
+		})
+	}
+
+	return secretEnvVars, nil
+}
+
 // LoadTestPhaseFromString tries to get LoadTestPhase from string value.
 // Empty phase is a valid value and does not cause error, so caller should take care of checking if the phase is set
 // to one of the pre-defined values or empty.
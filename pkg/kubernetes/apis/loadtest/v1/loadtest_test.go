@@ -108,6 +108,140 @@ func TestLoadTestTagsFromString(t *testing.T) {
 	}
 }
 
+func TestLoadTestResourcesFromString(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		in            string
+		out           *LoadTestResources
+		expectedError string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "all resources",
+			in:   "cpuLimits:2, cpuRequests:500m,memoryLimits:2Gi,memoryRequests:512Mi,",
+			out: &LoadTestResources{
+				CPULimits:      "2",
+				CPURequests:    "500m",
+				MemoryLimits:   "2Gi",
+				MemoryRequests: "512Mi",
+			},
+		},
+		{
+			name:          "missing value",
+			in:            "cpuLimits:",
+			expectedError: `missing resource value for "cpuLimits"`,
+		},
+		{
+			name:          "unknown name",
+			in:            "gpuLimits:1",
+			expectedError: `unknown resource name, should be one of cpuLimits, cpuRequests, memoryLimits, memoryRequests: "gpuLimits"`,
+		},
+		{
+			name:          "invalid quantity",
+			in:            "memoryLimits:lots",
+			expectedError: `invalid resource quantity "lots" for "memoryLimits"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := LoadTestResourcesFromString(tt.in)
+
+			assert.Equal(t, tt.out, out)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestLoadTestThresholdsFromString(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		in            string
+		out           *LoadTestThresholds
+		expectedError string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "all thresholds",
+			in:   "p95Latency:500ms, errorRate:0.01,minThroughput:100,",
+			out: &LoadTestThresholds{
+				P95Latency:    "500ms",
+				ErrorRate:     "0.01",
+				MinThroughput: "100",
+			},
+		},
+		{
+			name:          "missing value",
+			in:            "errorRate:",
+			expectedError: `missing threshold value for "errorRate"`,
+		},
+		{
+			name:          "unknown name",
+			in:            "p99Latency:1s",
+			expectedError: `unknown threshold name, should be one of p95Latency, errorRate, minThroughput: "p99Latency"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := LoadTestThresholdsFromString(tt.in)
+
+			assert.Equal(t, tt.out, out)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestLoadTestSecretEnvVarsFromString(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		in            string
+		out           []LoadTestSecretEnvVar
+		expectedError string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "secret env vars",
+			in:   "API_TOKEN:api-credentials/token, DB_PASSWORD:db/password,",
+			out: []LoadTestSecretEnvVar{
+				{Name: "API_TOKEN", SecretName: "api-credentials", Key: "token"},
+				{Name: "DB_PASSWORD", SecretName: "db", Key: "password"},
+			},
+		},
+		{
+			name:          "missing secret",
+			in:            "API_TOKEN",
+			expectedError: `secret env var should be in NAME:secret-name/key format, got "API_TOKEN"`,
+		},
+		{
+			name:          "missing key",
+			in:            "API_TOKEN:api-credentials",
+			expectedError: `secret env var should be in NAME:secret-name/key format, got "API_TOKEN:api-credentials"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := LoadTestSecretEnvVarsFromString(tt.in)
+
+			assert.Equal(t, tt.out, out)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
 func TestLoadTestPhaseFromString(t *testing.T) {
 	for _, tt := range []struct {
 		name string
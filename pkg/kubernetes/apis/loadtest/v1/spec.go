@@ -12,6 +12,18 @@ var (
 	ErrTagMissingValue = errors.New("missing tag value")
 	// ErrTagValueMaxLengthExceeded indicates that tag value is too long.
 	ErrTagValueMaxLengthExceeded = errors.New("tag value is too long")
+	// ErrResourceMissingValue indicates that resource value is missing.
+	ErrResourceMissingValue = errors.New("missing resource value")
+	// ErrResourceUnknownName indicates that resource name is not one of cpuLimits, cpuRequests, memoryLimits, memoryRequests.
+	ErrResourceUnknownName = errors.New("unknown resource name, should be one of cpuLimits, cpuRequests, memoryLimits, memoryRequests")
+	// ErrResourceInvalidQuantity indicates that resource value is not a valid quantity.
+	ErrResourceInvalidQuantity = errors.New("invalid resource quantity")
+	// ErrThresholdMissingValue indicates that threshold value is missing.
+	ErrThresholdMissingValue = errors.New("missing threshold value")
+	// ErrThresholdUnknownName indicates that threshold name is not one of p95Latency, errorRate, minThroughput.
+	ErrThresholdUnknownName = errors.New("unknown threshold name, should be one of p95Latency, errorRate, minThroughput")
+	// ErrSecretEnvVarInvalidFormat indicates that secret env var is not in NAME:secret-name/key format.
+	ErrSecretEnvVarInvalidFormat = errors.New("secret env var should be in NAME:secret-name/key format")
 )
 
 //NewSpec initialize spec for LoadTest custom resource
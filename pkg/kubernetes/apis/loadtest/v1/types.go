@@ -20,6 +20,7 @@ import (
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -55,6 +56,68 @@ type LoadTestSpec struct {
 	EnvVars         map[string]string `json:"envVars,omitempty"`
 	TargetURL       string            `json:"targetURL,omitempty"`
 	Duration        time.Duration     `json:"duration,omitempty"`
+	// TTLSecondsAfterFinished limits the lifetime of a finished or errored LoadTest,
+	// when not set the controller cleanup threshold is used
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// MasterResources overrides backend default resources of the master pod
+	MasterResources *LoadTestResources `json:"masterResources,omitempty"`
+	// WorkerResources overrides backend default resources of the worker pods
+	WorkerResources *LoadTestResources `json:"workerResources,omitempty"`
+	// Scheduling constraints of LoadTest pods, merged with controller global defaults
+	Scheduling *LoadTestScheduling `json:"scheduling,omitempty"`
+	// Thresholds are pass/fail criteria evaluated against the LoadTest report once it has finished
+	Thresholds *LoadTestThresholds `json:"thresholds,omitempty"`
+	// CompareBaseline compares the LoadTest results against the most recent passing LoadTest with the same tags
+	// once it has finished, the result is recorded in the Regressed condition
+	CompareBaseline bool `json:"compareBaseline,omitempty"`
+	// SecretEnvVars are environment variables which values are read from Secrets in the controller secrets namespace,
+	// values are copied to the LoadTest namespace and never stored in LoadTest
+	SecretEnvVars []LoadTestSecretEnvVar `json:"secretEnvVars,omitempty"`
+	// Callbacks are URLs the controller notifies with a signed POST request once the LoadTest is finished or errored
+	Callbacks []string `json:"callbacks,omitempty"`
+}
+
+// LoadTestSecretEnvVar is an environment variable which value is read from a Secret key
+type LoadTestSecretEnvVar struct {
+	// Name is the environment variable name
+	Name string `json:"name"`
+	// SecretName is the name of the Secret in the controller secrets namespace
+	SecretName string `json:"secretName"`
+	// Key is the Secret key holding the value
+	Key string `json:"key"`
+}
+
+// LoadTestThresholds is the pass/fail criteria of a LoadTest, thresholds not set are not evaluated
+type LoadTestThresholds struct {
+	// P95Latency is the maximum 95th percentile response time, e.g. 500ms
+	P95Latency string `json:"p95Latency,omitempty"`
+	// ErrorRate is the maximum ratio of failed requests between 0 and 1, e.g. 0.01
+	ErrorRate string `json:"errorRate,omitempty"`
+	// MinThroughput is the minimum number of requests per second, e.g. 100
+	MinThroughput string `json:"minThroughput,omitempty"`
+}
+
+// LoadTestScheduling is the scheduling constraints of LoadTest pods, allowed values are restricted by admin
+type LoadTestScheduling struct {
+	// NodeSelector is merged with controller node selectors, LoadTest values win on conflicting keys
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are appended to controller tolerations
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity replaces backend default node affinity, pod affinity or pod anti-affinity when set
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// TopologySpreadConstraints describes how LoadTest pods are spread across topology domains, e.g. zones
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName is the priority class of LoadTest pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// LoadTestResources is the resources requests and limits of LoadTest pods,
+// values not set fall back to backend defaults
+type LoadTestResources struct {
+	CPULimits      string `json:"cpuLimits,omitempty"`
+	CPURequests    string `json:"cpuRequests,omitempty"`
+	MemoryLimits   string `json:"memoryLimits,omitempty"`
+	MemoryRequests string `json:"memoryRequests,omitempty"`
 }
 
 // LoadTestTags is a list of tags of a LoadTest resource.
@@ -78,12 +141,57 @@ type ImageDetails struct {
 
 // LoadTestStatus is the status for a LoadTest resource
 type LoadTestStatus struct {
-	Phase     LoadTestPhase      `json:"phase"`
-	Namespace string             `json:"namespace"`
-	JobStatus batchv1.JobStatus  `json:"jobStatus"`
-	Pods      LoadTestPodsStatus `json:"pods"`
+	Phase     LoadTestPhase `json:"phase"`
+	Namespace string        `json:"namespace"`
+	// SharedNamespace is true when the loadtest runs in the namespace shared by all loadtests in single namespace mode,
+	// its resources are named and labeled after the loadtest
+	SharedNamespace bool               `json:"sharedNamespace,omitempty"`
+	JobStatus       batchv1.JobStatus  `json:"jobStatus"`
+	Pods            LoadTestPodsStatus `json:"pods"`
+	Conditions      []metav1.Condition `json:"conditions,omitempty"`
+	StartTime       *metav1.Time       `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time       `json:"completionTime,omitempty"`
+	// Reason is a brief CamelCase string that describes why the loadtest is in its current phase
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable description of the Reason
+	Message string `json:"message,omitempty"`
+	// Notifications are delivery states of completion webhooks, set once the loadtest is finished or errored
+	Notifications []LoadTestNotification `json:"notifications,omitempty"`
+}
+
+// LoadTestNotification is the delivery state of a completion webhook
+type LoadTestNotification struct {
+	// URL is the webhook URL, a LoadTest callback or an admin configured webhook
+	URL string `json:"url"`
+	// State is Pending until the webhook is delivered or all delivery attempts failed
+	State LoadTestNotificationState `json:"state"`
+	// Attempts is the number of delivery attempts
+	Attempts int32 `json:"attempts,omitempty"`
+	// LastAttemptTime is the time of the last delivery attempt
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// NextAttemptTime is the earliest time a failed delivery is attempted again
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+	// Message is the error of the last failed delivery attempt
+	Message string `json:"message,omitempty"`
+}
+
+// LoadTestNotificationState is the delivery state of a completion webhook
+type LoadTestNotificationState string
+
+// String returns string representation of LoadTestNotificationState
+func (s LoadTestNotificationState) String() string {
+	return string(s)
 }
 
+const (
+	// LoadTestNotificationPending is used until the webhook is delivered or all delivery attempts failed
+	LoadTestNotificationPending LoadTestNotificationState = "Pending"
+	// LoadTestNotificationDelivered is used once the webhook responded with 2xx status code
+	LoadTestNotificationDelivered LoadTestNotificationState = "Delivered"
+	// LoadTestNotificationFailed is used when all delivery attempts failed
+	LoadTestNotificationFailed LoadTestNotificationState = "Failed"
+)
+
 // LoadTestPhase defines the phases that a loadtest can be in
 type LoadTestPhase string
 
@@ -111,6 +219,77 @@ const (
 	LoadTestErrored LoadTestPhase = "errored"
 )
 
+// LoadTestConditionType is a valid value for Condition.Type of a LoadTest
+type LoadTestConditionType string
+
+// String returns string representation of LoadTestConditionType
+func (c LoadTestConditionType) String() string {
+	return string(c)
+}
+
+const (
+	// LoadTestConditionReady is true when all loadtest pods are running
+	LoadTestConditionReady LoadTestConditionType = "Ready"
+	// LoadTestConditionComplete is true when the loadtest has finished running
+	LoadTestConditionComplete LoadTestConditionType = "Complete"
+	// LoadTestConditionFailed is true when the loadtest has errored, the condition
+	// reason and message explain why
+	LoadTestConditionFailed LoadTestConditionType = "Failed"
+	// LoadTestConditionPassed is set once the report of a finished loadtest with thresholds was evaluated,
+	// the condition message has the measured values
+	LoadTestConditionPassed LoadTestConditionType = "Passed"
+	// LoadTestConditionRecorded is true once the metadata of the completed loadtest is stored in the history catalog
+	LoadTestConditionRecorded LoadTestConditionType = "Recorded"
+	// LoadTestConditionRegressed is set once the results of a finished loadtest comparing to baseline were compared
+	// to the baseline loadtest results, the condition message has the regressed values
+	LoadTestConditionRegressed LoadTestConditionType = "Regressed"
+)
+
+// Reasons used in LoadTest conditions and in LoadTestStatus.Reason
+const (
+	// LoadTestReasonCreating is used while loadtest resources are being created
+	LoadTestReasonCreating = "Creating"
+	// LoadTestReasonPodsPending is used while some of loadtest pods are not running yet
+	LoadTestReasonPodsPending = "PodsPending"
+	// LoadTestReasonRunning is used when all loadtest pods are running
+	LoadTestReasonRunning = "Running"
+	// LoadTestReasonCompleted is used when the loadtest has finished successfully
+	LoadTestReasonCompleted = "Completed"
+	// LoadTestReasonImagePullFailure is used when the loadtest image can not be pulled
+	LoadTestReasonImagePullFailure = "ImagePullFailure"
+	// LoadTestReasonContainerConfigError is used when a container can not be configured, e.g. missing secret
+	LoadTestReasonContainerConfigError = "ContainerConfigError"
+	// LoadTestReasonCrashLoop is used when a container keeps crashing
+	LoadTestReasonCrashLoop = "CrashLoopBackOff"
+	// LoadTestReasonOOMKilled is used when a container was killed for exceeding its memory limit
+	LoadTestReasonOOMKilled = "OOMKilled"
+	// LoadTestReasonContainerFailed is used when a container terminated with non-zero exit code
+	LoadTestReasonContainerFailed = "ContainerFailed"
+	// LoadTestReasonJobFailed is used when a loadtest job failed without a more specific container reason
+	LoadTestReasonJobFailed = "JobFailed"
+	// LoadTestReasonDeadlineExceeded is used when the loadtest ran longer than its duration plus grace period or the global maximum
+	LoadTestReasonDeadlineExceeded = "DeadlineExceeded"
+	// LoadTestReasonTimeout is used when loadtest pods did not start in time
+	LoadTestReasonTimeout = "Timeout"
+	// LoadTestReasonThresholdsPassed is used when all loadtest thresholds are met
+	LoadTestReasonThresholdsPassed = "ThresholdsPassed"
+	// LoadTestReasonThresholdsFailed is used when at least one of loadtest thresholds is not met
+	LoadTestReasonThresholdsFailed = "ThresholdsFailed"
+	// LoadTestReasonReportUnavailable is used when thresholds can not be evaluated because the report is missing or can not be parsed
+	LoadTestReasonReportUnavailable = "ReportUnavailable"
+	// LoadTestReasonSecretEnvVarsFailed is used when secret env vars can not be copied to the loadtest namespace, e.g. missing Secret key
+	LoadTestReasonSecretEnvVarsFailed = "SecretEnvVarsFailed"
+	// LoadTestReasonHistoryRecorded is used when the loadtest metadata is stored in the history catalog
+	LoadTestReasonHistoryRecorded = "HistoryRecorded"
+	// LoadTestReasonBaselineRegressed is used when the loadtest results are worse than the baseline beyond the tolerances
+	LoadTestReasonBaselineRegressed = "BaselineRegressed"
+	// LoadTestReasonBaselineMatched is used when the loadtest results are within the tolerances of the baseline
+	LoadTestReasonBaselineMatched = "BaselineMatched"
+	// LoadTestReasonBaselineUnavailable is used when the loadtest can not be compared to a baseline,
+	// e.g. there is no previous passing loadtest with the same tags
+	LoadTestReasonBaselineUnavailable = "BaselineUnavailable"
+)
+
 // LoadTestType needs to be specified to know what tool to use when running a loadtest
 type LoadTestType string
 
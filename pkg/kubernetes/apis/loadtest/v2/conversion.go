@@ -0,0 +1,249 @@
+package v2
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// Env vars backends read from v1 LoadTest spec, v2 backend specific configuration is stored in them
+const (
+	EnvJMeterThreads                      = "JMETER_THREADS"
+	EnvJMeterRemoteCustomDataEnabled      = "JMETER_WORKER_REMOTE_CUSTOM_DATA_ENABLED"
+	EnvJMeterRemoteCustomDataBucket       = "JMETER_WORKER_REMOTE_CUSTOM_DATA_BUCKET"
+	EnvJMeterRemoteCustomDataVolumeSize   = "JMETER_WORKER_REMOTE_CUSTOM_DATA_VOLUME_SIZE"
+	EnvJMeterRemoteCustomDataStorageClass = "JMETER_WORKER_REMOTE_CUSTOM_DATA_STORAGECLASS"
+
+	EnvLocustUsers     = "LOCUST_USERS"
+	EnvLocustSpawnRate = "LOCUST_SPAWN_RATE"
+
+	EnvK6VUs        = "K6_VUS"
+	EnvK6Iterations = "K6_ITERATIONS"
+
+	EnvGhzConcurrency   = "GHZ_CONCURRENCY"
+	EnvGhzTotalRequests = "GHZ_TOTAL_REQUESTS"
+	EnvGhzRPS           = "GHZ_RPS"
+)
+
+// RemoteCustomDataEnabled returns true when JMeter remote custom data is enabled, JMeter backend enables it
+// whenever JMETER_WORKER_REMOTE_CUSTOM_DATA_ENABLED is set, whatever its value
+func RemoteCustomDataEnabled(envVars map[string]string) bool {
+	_, ok := envVars[EnvJMeterRemoteCustomDataEnabled]
+	return ok
+}
+
+// ValidateBackendSpec returns errors for backend specific configurations set for another LoadTest type,
+// they would be converted to env vars the LoadTest backend does not read
+func ValidateBackendSpec(spec LoadTestSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, backend := range []struct {
+		name     string
+		loadType loadTestV1.LoadTestType
+		set      bool
+	}{
+		{"jmeter", loadTestV1.LoadTestTypeJMeter, spec.JMeter != nil},
+		{"locust", loadTestV1.LoadTestTypeLocust, spec.Locust != nil},
+		{"k6", loadTestV1.LoadTestTypeK6, spec.K6 != nil},
+		{"ghz", loadTestV1.LoadTestTypeGhz, spec.Ghz != nil},
+	} {
+		if backend.set && spec.Type != backend.loadType {
+			errs = append(errs, field.Forbidden(path.Child(backend.name), fmt.Sprintf("allowed only for %s load tests", backend.loadType)))
+		}
+	}
+	return errs
+}
+
+// ConvertToV1 converts LoadTest to v1, the storage version, backend specific configuration is converted to env vars
+func ConvertToV1(in *LoadTest) *loadTestV1.LoadTest {
+	out := &loadTestV1.LoadTest{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Status:     *in.Status.DeepCopy(),
+	}
+	out.APIVersion = loadTestV1.SchemeGroupVersion.String()
+
+	spec := in.Spec.DeepCopy()
+	out.Spec = loadTestV1.LoadTestSpec{
+		Type:                    spec.Type,
+		Overwrite:               spec.Overwrite,
+		MasterConfig:            spec.MasterConfig,
+		WorkerConfig:            spec.WorkerConfig,
+		DistributedPods:         spec.DistributedPods,
+		Tags:                    spec.Tags,
+		TestFile:                spec.TestFile,
+		TestData:                spec.TestData,
+		EnvVars:                 spec.EnvVars,
+		TargetURL:               spec.TargetURL,
+		Duration:                spec.Duration,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+		MasterResources:         spec.MasterResources,
+		WorkerResources:         spec.WorkerResources,
+		Scheduling:              spec.Scheduling,
+		Thresholds:              spec.Thresholds,
+		CompareBaseline:         spec.CompareBaseline,
+		SecretEnvVars:           spec.SecretEnvVars,
+		Callbacks:               spec.Callbacks,
+	}
+
+	envVars := map[string]string{}
+	if spec.JMeter != nil {
+		setInt32(envVars, EnvJMeterThreads, spec.JMeter.Threads)
+		if spec.JMeter.RemoteCustomData != nil {
+			// the value set as env var is kept, so converting from v1 and back is lossless
+			if !RemoteCustomDataEnabled(spec.EnvVars) {
+				envVars[EnvJMeterRemoteCustomDataEnabled] = "true"
+			}
+			setString(envVars, EnvJMeterRemoteCustomDataBucket, spec.JMeter.RemoteCustomData.Bucket)
+			setString(envVars, EnvJMeterRemoteCustomDataVolumeSize, spec.JMeter.RemoteCustomData.VolumeSize)
+			setString(envVars, EnvJMeterRemoteCustomDataStorageClass, spec.JMeter.RemoteCustomData.StorageClass)
+		}
+	}
+	if spec.Locust != nil {
+		setInt32(envVars, EnvLocustUsers, spec.Locust.Users)
+		setInt32(envVars, EnvLocustSpawnRate, spec.Locust.SpawnRate)
+	}
+	if spec.K6 != nil {
+		setInt32(envVars, EnvK6VUs, spec.K6.VUs)
+		setInt32(envVars, EnvK6Iterations, spec.K6.Iterations)
+	}
+	if spec.Ghz != nil {
+		setInt32(envVars, EnvGhzConcurrency, spec.Ghz.Concurrency)
+		setInt32(envVars, EnvGhzTotalRequests, spec.Ghz.TotalRequests)
+		setInt32(envVars, EnvGhzRPS, spec.Ghz.RPS)
+	}
+
+	if len(envVars) > 0 {
+		if out.Spec.EnvVars == nil {
+			out.Spec.EnvVars = make(map[string]string, len(envVars))
+		}
+		for name, value := range envVars {
+			out.Spec.EnvVars[name] = value
+		}
+	}
+
+	return out
+}
+
+// ConvertFromV1 converts v1 LoadTest to v2, env vars known for the LoadTest type are converted to backend specific configuration
+func ConvertFromV1(in *loadTestV1.LoadTest) *LoadTest {
+	out := &LoadTest{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Status:     *in.Status.DeepCopy(),
+	}
+	out.APIVersion = SchemeGroupVersion.String()
+
+	spec := in.Spec.DeepCopy()
+	out.Spec = LoadTestSpec{
+		Type:                    spec.Type,
+		Overwrite:               spec.Overwrite,
+		MasterConfig:            spec.MasterConfig,
+		WorkerConfig:            spec.WorkerConfig,
+		DistributedPods:         spec.DistributedPods,
+		Tags:                    spec.Tags,
+		TestFile:                spec.TestFile,
+		TestData:                spec.TestData,
+		EnvVars:                 spec.EnvVars,
+		TargetURL:               spec.TargetURL,
+		Duration:                spec.Duration,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+		MasterResources:         spec.MasterResources,
+		WorkerResources:         spec.WorkerResources,
+		Scheduling:              spec.Scheduling,
+		Thresholds:              spec.Thresholds,
+		CompareBaseline:         spec.CompareBaseline,
+		SecretEnvVars:           spec.SecretEnvVars,
+		Callbacks:               spec.Callbacks,
+	}
+
+	envVars := out.Spec.EnvVars
+	switch spec.Type {
+	case loadTestV1.LoadTestTypeJMeter:
+		jmeter := &JMeterSpec{
+			Threads: popInt32(envVars, EnvJMeterThreads),
+		}
+		if RemoteCustomDataEnabled(envVars) {
+			jmeter.RemoteCustomData = &JMeterRemoteCustomData{
+				Bucket:       popString(envVars, EnvJMeterRemoteCustomDataBucket),
+				VolumeSize:   popString(envVars, EnvJMeterRemoteCustomDataVolumeSize),
+				StorageClass: popString(envVars, EnvJMeterRemoteCustomDataStorageClass),
+			}
+			// other values enable it as well and are kept as env var, so converting back is lossless
+			if envVars[EnvJMeterRemoteCustomDataEnabled] == "true" {
+				delete(envVars, EnvJMeterRemoteCustomDataEnabled)
+			}
+		}
+		if *jmeter != (JMeterSpec{}) {
+			out.Spec.JMeter = jmeter
+		}
+	case loadTestV1.LoadTestTypeLocust:
+		locust := &LocustSpec{
+			Users:     popInt32(envVars, EnvLocustUsers),
+			SpawnRate: popInt32(envVars, EnvLocustSpawnRate),
+		}
+		if *locust != (LocustSpec{}) {
+			out.Spec.Locust = locust
+		}
+	case loadTestV1.LoadTestTypeK6:
+		k6 := &K6Spec{
+			VUs:        popInt32(envVars, EnvK6VUs),
+			Iterations: popInt32(envVars, EnvK6Iterations),
+		}
+		if *k6 != (K6Spec{}) {
+			out.Spec.K6 = k6
+		}
+	case loadTestV1.LoadTestTypeGhz:
+		ghz := &GhzSpec{
+			Concurrency:   popInt32(envVars, EnvGhzConcurrency),
+			TotalRequests: popInt32(envVars, EnvGhzTotalRequests),
+			RPS:           popInt32(envVars, EnvGhzRPS),
+		}
+		if *ghz != (GhzSpec{}) {
+			out.Spec.Ghz = ghz
+		}
+	}
+
+	if envVars != nil && len(envVars) == 0 {
+		out.Spec.EnvVars = nil
+	}
+
+	return out
+}
+
+func setString(envVars map[string]string, name, value string) {
+	if value != "" {
+		envVars[name] = value
+	}
+}
+
+func setInt32(envVars map[string]string, name string, value *int32) {
+	if value != nil {
+		envVars[name] = strconv.FormatInt(int64(*value), 10)
+	}
+}
+
+func popString(envVars map[string]string, name string) string {
+	value := envVars[name]
+	delete(envVars, name)
+	return value
+}
+
+// popInt32 removes env var only if it is a valid int32, invalid values are kept as env vars
+func popInt32(envVars map[string]string, name string) *int32 {
+	value, ok := envVars[name]
+	if !ok {
+		return nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil
+	}
+	delete(envVars, name)
+
+	result := int32(parsed)
+	return &result
+}
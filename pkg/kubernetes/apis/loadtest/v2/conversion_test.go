@@ -0,0 +1,224 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestConvertToV1(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		spec            LoadTestSpec
+		expectedEnvVars map[string]string
+	}{
+		{
+			name: "no backend specific configuration",
+			spec: LoadTestSpec{
+				Type:    loadTestV1.LoadTestTypeK6,
+				EnvVars: map[string]string{"TOKEN": "secret"},
+			},
+			expectedEnvVars: map[string]string{"TOKEN": "secret"},
+		},
+		{
+			name: "jmeter remote custom data",
+			spec: LoadTestSpec{
+				Type: loadTestV1.LoadTestTypeJMeter,
+				JMeter: &JMeterSpec{
+					RemoteCustomData: &JMeterRemoteCustomData{Bucket: "my-bucket", VolumeSize: "1Gi"},
+				},
+			},
+			expectedEnvVars: map[string]string{
+				EnvJMeterRemoteCustomDataEnabled:    "true",
+				EnvJMeterRemoteCustomDataBucket:     "my-bucket",
+				EnvJMeterRemoteCustomDataVolumeSize: "1Gi",
+			},
+		},
+		{
+			name: "jmeter threads",
+			spec: LoadTestSpec{
+				Type:   loadTestV1.LoadTestTypeJMeter,
+				JMeter: &JMeterSpec{Threads: int32Ptr(20)},
+			},
+			expectedEnvVars: map[string]string{EnvJMeterThreads: "20"},
+		},
+		{
+			name: "locust users and spawn rate",
+			spec: LoadTestSpec{
+				Type:    loadTestV1.LoadTestTypeLocust,
+				EnvVars: map[string]string{"TOKEN": "secret"},
+				Locust:  &LocustSpec{Users: int32Ptr(100), SpawnRate: int32Ptr(10)},
+			},
+			expectedEnvVars: map[string]string{
+				"TOKEN":            "secret",
+				EnvLocustUsers:     "100",
+				EnvLocustSpawnRate: "10",
+			},
+		},
+		{
+			name: "k6 vus",
+			spec: LoadTestSpec{
+				Type: loadTestV1.LoadTestTypeK6,
+				K6:   &K6Spec{VUs: int32Ptr(50)},
+			},
+			expectedEnvVars: map[string]string{EnvK6VUs: "50"},
+		},
+		{
+			name: "ghz options",
+			spec: LoadTestSpec{
+				Type: loadTestV1.LoadTestTypeGhz,
+				Ghz:  &GhzSpec{Concurrency: int32Ptr(5), TotalRequests: int32Ptr(1000), RPS: int32Ptr(200)},
+			},
+			expectedEnvVars: map[string]string{
+				EnvGhzConcurrency:   "5",
+				EnvGhzTotalRequests: "1000",
+				EnvGhzRPS:           "200",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &LoadTest{
+				TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "LoadTest"},
+				ObjectMeta: metav1.ObjectMeta{Name: "loadtest-converted"},
+				Spec:       tt.spec,
+				Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning},
+			}
+
+			out := ConvertToV1(in)
+
+			assert.Equal(t, loadTestV1.SchemeGroupVersion.String(), out.APIVersion)
+			assert.Equal(t, "LoadTest", out.Kind)
+			assert.Equal(t, "loadtest-converted", out.Name)
+			assert.Equal(t, tt.spec.Type, out.Spec.Type)
+			assert.Equal(t, loadTestV1.LoadTestRunning, out.Status.Phase)
+			assert.Equal(t, tt.expectedEnvVars, out.Spec.EnvVars)
+
+			// converting back must be lossless
+			assert.Equal(t, in, ConvertFromV1(out))
+		})
+	}
+}
+
+func TestConvertFromV1(t *testing.T) {
+	t.Run("env vars of other backends are kept", func(t *testing.T) {
+		in := &loadTestV1.LoadTest{
+			Spec: loadTestV1.LoadTestSpec{
+				Type:    loadTestV1.LoadTestTypeJMeter,
+				EnvVars: map[string]string{EnvK6VUs: "10"},
+			},
+		}
+
+		out := ConvertFromV1(in)
+
+		assert.Nil(t, out.Spec.JMeter)
+		assert.Nil(t, out.Spec.K6)
+		assert.Equal(t, map[string]string{EnvK6VUs: "10"}, out.Spec.EnvVars)
+	})
+
+	t.Run("remote custom data is enabled by any value", func(t *testing.T) {
+		for _, value := range []string{"", "false", "yes", "enabled"} {
+			in := &loadTestV1.LoadTest{
+				Spec: loadTestV1.LoadTestSpec{
+					Type: loadTestV1.LoadTestTypeJMeter,
+					EnvVars: map[string]string{
+						EnvJMeterRemoteCustomDataEnabled: value,
+						EnvJMeterRemoteCustomDataBucket:  "my-bucket",
+					},
+				},
+			}
+
+			out := ConvertFromV1(in)
+
+			assert.Equal(t, &JMeterSpec{RemoteCustomData: &JMeterRemoteCustomData{Bucket: "my-bucket"}}, out.Spec.JMeter, value)
+			assert.Equal(t, map[string]string{EnvJMeterRemoteCustomDataEnabled: value}, out.Spec.EnvVars, value)
+			assert.True(t, RemoteCustomDataEnabled(ConvertToV1(out).Spec.EnvVars), value)
+		}
+	})
+
+	t.Run("invalid values are kept as env vars", func(t *testing.T) {
+		in := &loadTestV1.LoadTest{
+			Spec: loadTestV1.LoadTestSpec{
+				Type:    loadTestV1.LoadTestTypeLocust,
+				EnvVars: map[string]string{EnvLocustUsers: "many", EnvLocustSpawnRate: "5"},
+			},
+		}
+
+		out := ConvertFromV1(in)
+
+		assert.Equal(t, &LocustSpec{SpawnRate: int32Ptr(5)}, out.Spec.Locust)
+		assert.Equal(t, map[string]string{EnvLocustUsers: "many"}, out.Spec.EnvVars)
+	})
+
+	t.Run("input is not modified", func(t *testing.T) {
+		in := &loadTestV1.LoadTest{
+			Spec: loadTestV1.LoadTestSpec{
+				Type:    loadTestV1.LoadTestTypeK6,
+				EnvVars: map[string]string{EnvK6Iterations: "100"},
+			},
+		}
+
+		out := ConvertFromV1(in)
+
+		assert.Equal(t, &K6Spec{Iterations: int32Ptr(100)}, out.Spec.K6)
+		assert.Nil(t, out.Spec.EnvVars)
+		assert.Equal(t, map[string]string{EnvK6Iterations: "100"}, in.Spec.EnvVars)
+	})
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	for name, envVars := range map[string]map[string]string{
+		"jmeter custom data enabled": {
+			EnvJMeterThreads:                 "20",
+			EnvJMeterRemoteCustomDataEnabled: "true",
+			EnvJMeterRemoteCustomDataBucket:  "my-bucket",
+		},
+		"jmeter custom data without bucket": {EnvJMeterRemoteCustomDataEnabled: "true"},
+		"jmeter custom data disabled": {
+			EnvJMeterRemoteCustomDataEnabled: "false",
+			EnvJMeterRemoteCustomDataBucket:  "my-bucket",
+		},
+		"jmeter custom data enabled with other spelling": {EnvJMeterRemoteCustomDataEnabled: "1"},
+		"jmeter custom data enabled with empty value":    {EnvJMeterRemoteCustomDataEnabled: ""},
+		"jmeter invalid threads":                         {EnvJMeterThreads: "many", "TOKEN": "secret"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			in := &loadTestV1.LoadTest{
+				TypeMeta:   metav1.TypeMeta{APIVersion: loadTestV1.SchemeGroupVersion.String(), Kind: "LoadTest"},
+				ObjectMeta: metav1.ObjectMeta{Name: "loadtest-converted"},
+				Spec: loadTestV1.LoadTestSpec{
+					Type:    loadTestV1.LoadTestTypeJMeter,
+					EnvVars: envVars,
+				},
+			}
+
+			assert.Equal(t, in, ConvertToV1(ConvertFromV1(in)))
+		})
+	}
+}
+
+func TestValidateBackendSpec(t *testing.T) {
+	path := field.NewPath("spec")
+
+	assert.Empty(t, ValidateBackendSpec(LoadTestSpec{Type: loadTestV1.LoadTestTypeK6, K6: &K6Spec{VUs: int32Ptr(10)}}, path))
+	assert.Empty(t, ValidateBackendSpec(LoadTestSpec{Type: loadTestV1.LoadTestTypeK6}, path))
+
+	errs := ValidateBackendSpec(LoadTestSpec{
+		Type:   loadTestV1.LoadTestTypeK6,
+		JMeter: &JMeterSpec{Threads: int32Ptr(10)},
+		Ghz:    &GhzSpec{},
+	}, path)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "spec.jmeter", errs[0].Field)
+	assert.Equal(t, field.ErrorTypeForbidden, errs[0].Type)
+	assert.Equal(t, "allowed only for JMeter load tests", errs[0].Detail)
+	assert.Equal(t, "spec.ghz", errs[1].Field)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LoadTest is a specification for a LoadTest resource
+type LoadTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LoadTestSpec              `json:"spec"`
+	Status loadTestV1.LoadTestStatus `json:"status"`
+}
+
+// LoadTestSpec is the spec for a LoadTest resource
+type LoadTestSpec struct {
+	Type            loadTestV1.LoadTestType `json:"type"`
+	Overwrite       bool                    `json:"overwrite"`
+	MasterConfig    loadTestV1.ImageDetails `json:"masterConfig"`
+	WorkerConfig    loadTestV1.ImageDetails `json:"workerConfig"`
+	DistributedPods *int32                  `json:"distributedPods"`
+	Tags            loadTestV1.LoadTestTags `json:"tags"`
+	TestFile        []byte                  `json:"testFile"`
+	TestData        []byte                  `json:"testData,omitempty"`
+	EnvVars         map[string]string       `json:"envVars,omitempty"`
+	TargetURL       string                  `json:"targetURL,omitempty"`
+	Duration        time.Duration           `json:"duration,omitempty"`
+	// TTLSecondsAfterFinished limits the lifetime of a finished or errored LoadTest,
+	// when not set the controller cleanup threshold is used
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// MasterResources overrides backend default resources of the master pod
+	MasterResources *loadTestV1.LoadTestResources `json:"masterResources,omitempty"`
+	// WorkerResources overrides backend default resources of the worker pods
+	WorkerResources *loadTestV1.LoadTestResources `json:"workerResources,omitempty"`
+	// Scheduling constraints of LoadTest pods, merged with controller global defaults
+	Scheduling *loadTestV1.LoadTestScheduling `json:"scheduling,omitempty"`
+	// Thresholds are pass/fail criteria evaluated against the LoadTest report once it has finished
+	Thresholds *loadTestV1.LoadTestThresholds `json:"thresholds,omitempty"`
+	// CompareBaseline compares the LoadTest results against the most recent passing LoadTest with the same tags
+	// once it has finished, the result is recorded in the Regressed condition
+	CompareBaseline bool `json:"compareBaseline,omitempty"`
+	// SecretEnvVars are environment variables which values are read from Secrets in the controller secrets namespace,
+	// values are copied to the LoadTest namespace and never stored in LoadTest
+	SecretEnvVars []loadTestV1.LoadTestSecretEnvVar `json:"secretEnvVars,omitempty"`
+	// Callbacks are URLs the controller notifies with a signed POST request once the LoadTest is finished or errored
+	Callbacks []string `json:"callbacks,omitempty"`
+
+	// JMeter is the JMeter specific configuration, allowed only for JMeter type
+	JMeter *JMeterSpec `json:"jmeter,omitempty"`
+	// Locust is the Locust specific configuration, allowed only for Locust type
+	Locust *LocustSpec `json:"locust,omitempty"`
+	// K6 is the k6 specific configuration, allowed only for K6 type
+	K6 *K6Spec `json:"k6,omitempty"`
+	// Ghz is the ghz specific configuration, allowed only for Ghz type
+	Ghz *GhzSpec `json:"ghz,omitempty"`
+}
+
+// JMeterSpec is the JMeter specific configuration of a LoadTest
+type JMeterSpec struct {
+	// Threads is the number of threads of each worker, it is exported to workers as JMETER_THREADS env var
+	// read by the test plan with ${__env(JMETER_THREADS)}
+	Threads *int32 `json:"threads,omitempty"`
+	// RemoteCustomData syncs custom data from a remote bucket to each worker before the test starts
+	RemoteCustomData *JMeterRemoteCustomData `json:"remoteCustomData,omitempty"`
+}
+
+// JMeterRemoteCustomData is the remote bucket custom data is synced from
+type JMeterRemoteCustomData struct {
+	// Bucket is the name of the bucket to sync custom data from
+	Bucket string `json:"bucket"`
+	// VolumeSize is the size of the volume custom data is synced to, e.g. 1Gi
+	VolumeSize string `json:"volumeSize,omitempty"`
+	// StorageClass is the storage class of the volume custom data is synced to
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// LocustSpec is the Locust specific configuration of a LoadTest
+type LocustSpec struct {
+	// Users is the peak number of concurrent users
+	Users *int32 `json:"users,omitempty"`
+	// SpawnRate is the number of users started per second
+	SpawnRate *int32 `json:"spawnRate,omitempty"`
+}
+
+// K6Spec is the k6 specific configuration of a LoadTest
+type K6Spec struct {
+	// VUs is the number of virtual users
+	VUs *int32 `json:"vus,omitempty"`
+	// Iterations is the total number of script iterations shared by all virtual users
+	Iterations *int32 `json:"iterations,omitempty"`
+}
+
+// GhzSpec is the ghz specific configuration of a LoadTest
+type GhzSpec struct {
+	// Concurrency is the number of request workers to run concurrently
+	Concurrency *int32 `json:"concurrency,omitempty"`
+	// TotalRequests is the number of requests to run
+	TotalRequests *int32 `json:"totalRequests,omitempty"`
+	// RPS is the requests per second rate limit
+	RPS *int32 `json:"rps,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LoadTestList is a list of LoadTest resources
+type LoadTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []LoadTest `json:"items"`
+}
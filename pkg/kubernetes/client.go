@@ -16,6 +16,7 @@ import (
 	restClient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v1"
 )
@@ -160,9 +161,10 @@ func (c *Client) filterLoadTestsByPhase(list *apisLoadTestV1.LoadTestList, phase
 // GetMasterPodRequest is making an assumptions that we only care about the logs
 // from the most recently created pod. It gets the pods associated with
 // the master job and returns the request that is used for getting the logs
-func (c *Client) GetMasterPodRequest(ctx context.Context, namespace string) (*restClient.Request, error) {
+func (c *Client) GetMasterPodRequest(ctx context.Context, loadTest apisLoadTestV1.LoadTest) (*restClient.Request, error) {
+	namespace := loadTest.Status.Namespace
 	pods, err := c.kubeClient.CoreV1().Pods(namespace).List(ctx, metaV1.ListOptions{
-		LabelSelector: loadTestMasterLabelSelector,
+		LabelSelector: backends.LoadTestSelector(loadTest, loadTestMasterLabelSelector),
 	})
 	if err != nil {
 		c.logger.Error(err.Error())
@@ -175,9 +177,10 @@ func (c *Client) GetMasterPodRequest(ctx context.Context, namespace string) (*re
 }
 
 // GetWorkerPodRequest is used for getting the logs from worker pod
-func (c *Client) GetWorkerPodRequest(ctx context.Context, namespace, workerPodNr string) (*restClient.Request, error) {
+func (c *Client) GetWorkerPodRequest(ctx context.Context, loadTest apisLoadTestV1.LoadTest, workerPodNr string) (*restClient.Request, error) {
+	namespace := loadTest.Status.Namespace
 	pods, err := c.kubeClient.CoreV1().Pods(namespace).List(ctx, metaV1.ListOptions{
-		LabelSelector: loadTestWorkerLabelSelector,
+		LabelSelector: backends.LoadTestSelector(loadTest, loadTestWorkerLabelSelector),
 	})
 	if err != nil {
 		c.logger.Error(err.Error())
@@ -231,6 +234,21 @@ func BuildClientConfig(masterURL string, kubeConfigPath string, timeout time.Dur
 	return kubeCfg, nil
 }
 
+// BuildClientConfigForContext is used in cmd package to connect to the cluster of a kubeconfig context,
+// current context is used when kubeContext is empty
+func BuildClientConfigForContext(kubeConfigPath string, kubeContext string, timeout time.Duration) (*restClient.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfigPath
+
+	kubeCfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	kubeCfg.Timeout = timeout
+	return kubeCfg, nil
+}
+
 // CountExistingLoadtests used in metrics to report running loadtests
 func (c *Client) CountExistingLoadtests() (map[apisLoadTestV1.LoadTestPhase]int64, map[apisLoadTestV1.LoadTestType]int64, error) {
 	tt, err := c.ltClient.List(context.Background(), metaV1.ListOptions{})
@@ -513,6 +513,10 @@ func TestGetMasterPodLogs(t *testing.T) {
 	ctx := context.Background()
 
 	var logger = zap.NewNop()
+	loadTest := apisLoadTestV1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace"},
+		Status:     apisLoadTestV1.LoadTestStatus{Namespace: "namespace"},
+	}
 	loadtestClientset := fakeClientset.NewSimpleClientset()
 	client := &fake.Clientset{}
 	client.Fake.PrependReactor("list", "pods", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
@@ -520,7 +524,7 @@ func TestGetMasterPodLogs(t *testing.T) {
 	})
 
 	c := NewClient(loadtestClientset.KangalV1().LoadTests(), client, logger)
-	_, err := c.GetMasterPodRequest(ctx, "namespace")
+	_, err := c.GetMasterPodRequest(ctx, loadTest)
 	assert.Error(t, err)
 
 	client = &fake.Clientset{}
@@ -533,7 +537,21 @@ func TestGetMasterPodLogs(t *testing.T) {
 	// to easily mock this funciton like there is for "ListPods". To do this We would
 	// need to wright our own `FakePod` package, and that doesn't seem worth it.
 	c = NewClient(loadtestClientset.KangalV1().LoadTests(), client, logger)
-	_, err = c.GetMasterPodRequest(ctx, "namespace")
+	_, err = c.GetMasterPodRequest(ctx, loadTest)
+	assert.Nil(t, err)
+
+	// in shared namespace pods are selected by loadtest label
+	loadTest.Name = "loadtest-name"
+	loadTest.Status.SharedNamespace = true
+	client = &fake.Clientset{}
+	client.Fake.PrependReactor("list", "pods", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+		selector := action.(k8stesting.ListActionImpl).GetListRestrictions().Labels.String()
+		assert.Equal(t, "app=loadtest-master,kangal.hellofresh.com/loadtest=loadtest-name", selector)
+		return true, &corev1.PodList{}, nil
+	})
+
+	c = NewClient(loadtestClientset.KangalV1().LoadTests(), client, logger)
+	_, err = c.GetMasterPodRequest(ctx, loadTest)
 	assert.Nil(t, err)
 }
 
@@ -700,7 +718,11 @@ func TestGetWorkerPodsRequest(t *testing.T) {
 			})
 			c := NewClient(loadtestClientset.KangalV1().LoadTests(), client, logger)
 
-			_, err := c.GetWorkerPodRequest(ctx, "foo", test.workerID)
+			loadTest := apisLoadTestV1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Status:     apisLoadTestV1.LoadTestStatus{Namespace: "foo"},
+			}
+			_, err := c.GetWorkerPodRequest(ctx, loadTest, test.workerID)
 			if !test.expectedError {
 				assert.NoError(t, err)
 			} else {
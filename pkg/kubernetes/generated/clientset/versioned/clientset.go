@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	kangalv1 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v1"
+	kangalv2 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v2"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -31,12 +32,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	KangalV1() kangalv1.KangalV1Interface
+	KangalV2() kangalv2.KangalV2Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	kangalV1 *kangalv1.KangalV1Client
+	kangalV2 *kangalv2.KangalV2Client
 }
 
 // KangalV1 retrieves the KangalV1Client
@@ -44,6 +47,11 @@ func (c *Clientset) KangalV1() kangalv1.KangalV1Interface {
 	return c.kangalV1
 }
 
+// KangalV2 retrieves the KangalV2Client
+func (c *Clientset) KangalV2() kangalv2.KangalV2Interface {
+	return c.kangalV2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -92,6 +100,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.kangalV2, err = kangalv2.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -114,6 +126,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.kangalV1 = kangalv1.New(c)
+	cs.kangalV2 = kangalv2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -22,6 +22,8 @@ import (
 	clientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned"
 	kangalv1 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v1"
 	fakekangalv1 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v1/fake"
+	kangalv2 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v2"
+	fakekangalv2 "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/typed/loadtest/v2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -83,3 +85,8 @@ var (
 func (c *Clientset) KangalV1() kangalv1.KangalV1Interface {
 	return &fakekangalv1.FakeKangalV1{Fake: &c.Fake}
 }
+
+// KangalV2 retrieves the KangalV2Client
+func (c *Clientset) KangalV2() kangalv2.KangalV2Interface {
+	return &fakekangalv2.FakeKangalV2{Fake: &c.Fake}
+}
@@ -20,6 +20,7 @@ package scheme
 
 import (
 	kangalv1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	kangalv2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,6 +33,7 @@ var Codecs = serializer.NewCodecFactory(Scheme)
 var ParameterCodec = runtime.NewParameterCodec(Scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	kangalv1.AddToScheme,
+	kangalv2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
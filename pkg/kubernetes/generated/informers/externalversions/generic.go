@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	v2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -56,6 +57,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1.SchemeGroupVersion.WithResource("loadtests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kangal().V1().LoadTests().Informer()}, nil
 
+		// Group=kangal.hellofresh.com, Version=v2
+	case v2.SchemeGroupVersion.WithResource("loadtests"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kangal().V2().LoadTests().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -0,0 +1,188 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
+
+	kube "github.com/hellofresh/kangal/pkg/kubernetes"
+	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var (
+	// ErrUnknownCluster is the error returned when a request targets a cluster that is not configured
+	ErrUnknownCluster = errors.New("unknown cluster")
+	// ErrNoCapacity is the error returned when the target clusters run their max number of active loadtests
+	ErrNoCapacity = errors.New("number of active load tests reached limit")
+	// ErrClusterNameMissing is the error returned when a configured cluster has no name
+	ErrClusterNameMissing = errors.New("cluster name is empty")
+	// ErrClusterNameDuplicate is the error returned when a cluster name is configured more than once
+	ErrClusterNameDuplicate = errors.New("cluster name is duplicated")
+)
+
+// ClusterConfig is a cluster entry of the CLUSTERS_CONFIG file
+type ClusterConfig struct {
+	// Name identifies the cluster in API requests and responses, e.g. the cluster region
+	Name string `json:"name"`
+	// Context is the kubeconfig context used to connect to the cluster, current context when empty
+	Context string `json:"context,omitempty"`
+	// KubeConfig is the path to the kubeconfig file, --kubeconfig value when empty
+	KubeConfig string `json:"kubeconfig,omitempty"`
+	// Capacity is the max number of active loadtests in the cluster, --max-load-tests value when empty
+	Capacity int `json:"capacity,omitempty"`
+}
+
+// ReadClustersConfig reads the list of clusters from a YAML file
+func ReadClustersConfig(path string, defaultCapacity int) ([]ClusterConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read clusters config: %w", err)
+	}
+
+	var clusters []ClusterConfig
+	if err := yaml.UnmarshalStrict(content, &clusters); err != nil {
+		return nil, fmt.Errorf("could not parse clusters config %s: %w", path, err)
+	}
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("clusters config %s has no clusters", path)
+	}
+
+	names := make(map[string]bool, len(clusters))
+	for i, cluster := range clusters {
+		if cluster.Name == "" {
+			return nil, fmt.Errorf("%w: cluster #%d", ErrClusterNameMissing, i+1)
+		}
+		if names[cluster.Name] {
+			return nil, fmt.Errorf("%w: %s", ErrClusterNameDuplicate, cluster.Name)
+		}
+		names[cluster.Name] = true
+
+		if cluster.Capacity <= 0 {
+			clusters[i].Capacity = defaultCapacity
+		}
+	}
+
+	return clusters, nil
+}
+
+// Cluster is a cluster the proxy dispatches loadtests to
+type Cluster struct {
+	// Name is empty when the proxy manages a single cluster
+	Name       string
+	Capacity   int
+	KubeClient *kube.Client
+}
+
+// freeCapacity returns the number of loadtests that can still be started in the cluster
+func (c *Cluster) freeCapacity() (int, error) {
+	testsByPhase, _, err := c.KubeClient.CountExistingLoadtests()
+	if err != nil {
+		return 0, err
+	}
+
+	active := testsByPhase[apisLoadTestV1.LoadTestRunning] + testsByPhase[apisLoadTestV1.LoadTestCreating]
+	return c.Capacity - int(active), nil
+}
+
+// Clusters is the list of clusters managed by the proxy
+type Clusters []*Cluster
+
+// SingleCluster returns clusters of a proxy managing only the cluster of the given client
+func SingleCluster(kubeClient *kube.Client, capacity int) Clusters {
+	return Clusters{{Capacity: capacity, KubeClient: kubeClient}}
+}
+
+// Get returns the cluster with the given name
+func (cs Clusters) Get(name string) (*Cluster, error) {
+	for _, cluster := range cs {
+		if cluster.Name == name {
+			return cluster, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownCluster, name)
+}
+
+// Pick returns the cluster a new loadtest is created in. When name is empty the cluster
+// with the most free capacity is picked, clusters the active loadtests can not be counted in are logged
+// and skipped, the error is returned only when no cluster is reachable. ErrNoCapacity is returned
+// when no reachable cluster has free capacity
+func (cs Clusters) Pick(name string, logger *zap.Logger) (*Cluster, error) {
+	candidates := cs
+	if name != "" {
+		cluster, err := cs.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		candidates = Clusters{cluster}
+	}
+
+	var (
+		picked    *Cluster
+		mostFree  int
+		reachable bool
+		lastErr   error
+	)
+	for _, cluster := range candidates {
+		free, err := cluster.freeCapacity()
+		if err != nil {
+			logger.Warn("Could not count active load tests, skipping cluster", zap.String("cluster", cluster.Name), zap.Error(err))
+			lastErr = fmt.Errorf("could not count active load tests in cluster %q: %w", cluster.Name, err)
+			continue
+		}
+		reachable = true
+		if free > mostFree {
+			picked, mostFree = cluster, free
+		}
+	}
+
+	if !reachable && lastErr != nil {
+		return nil, lastErr
+	}
+	if picked == nil {
+		return nil, ErrNoCapacity
+	}
+	return picked, nil
+}
+
+// FindLoadTest looks up the loadtest in all clusters and returns it with the cluster it runs in.
+// The not found error is returned only when the loadtest was not found and all clusters were reachable
+func (cs Clusters) FindLoadTest(ctx context.Context, name string) (*Cluster, *apisLoadTestV1.LoadTest, error) {
+	var notFoundErr, lastErr error
+	for _, cluster := range cs {
+		loadTest, err := cluster.KubeClient.GetLoadTest(ctx, name)
+		switch {
+		case err == nil:
+			return cluster, loadTest, nil
+		case k8sAPIErrors.IsNotFound(err):
+			notFoundErr = err
+		default:
+			lastErr = err
+		}
+	}
+
+	if lastErr != nil {
+		return nil, nil, lastErr
+	}
+	return nil, nil, notFoundErr
+}
+
+// Locate returns the cluster the loadtest runs in, loadtest is not looked up when there is a single cluster
+func (cs Clusters) Locate(ctx context.Context, name string) (*Cluster, error) {
+	if len(cs) == 1 {
+		return cs[0], nil
+	}
+
+	cluster, _, err := cs.FindLoadTest(ctx, name)
+	return cluster, err
+}
+
+// GetLoadTest returns the loadtest from the cluster it runs in
+func (cs Clusters) GetLoadTest(ctx context.Context, name string) (*apisLoadTestV1.LoadTest, error) {
+	_, loadTest, err := cs.FindLoadTest(ctx, name)
+	return loadTest, err
+}
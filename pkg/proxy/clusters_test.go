@@ -0,0 +1,195 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+
+	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
+	kube "github.com/hellofresh/kangal/pkg/kubernetes"
+	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+)
+
+func newTestCluster(t *testing.T, name string, capacity int, phases ...apisLoadTestV1.LoadTestPhase) *Cluster {
+	loadtestClientSet := fakeClientset.NewSimpleClientset()
+	for i, phase := range phases {
+		_, err := loadtestClientSet.KangalV1().LoadTests().Create(context.Background(), &apisLoadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: name + "-loadtest-" + string(rune('a'+i))},
+			Spec:       apisLoadTestV1.LoadTestSpec{Type: apisLoadTestV1.LoadTestTypeJMeter, DistributedPods: new(int32)},
+			Status:     apisLoadTestV1.LoadTestStatus{Phase: phase},
+		}, metaV1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	return &Cluster{
+		Name:       name,
+		Capacity:   capacity,
+		KubeClient: kube.NewClient(loadtestClientSet.KangalV1().LoadTests(), fake.NewSimpleClientset(), zaptest.NewLogger(t)),
+	}
+}
+
+func TestReadClustersConfig(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "clusters.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		clusters, err := ReadClustersConfig(write(t, `
+- name: eu-west-1
+  context: loadtest-eu
+  capacity: 5
+- name: us-east-1
+  context: loadtest-us
+  kubeconfig: /etc/kangal/us.kubeconfig
+`), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []ClusterConfig{
+			{Name: "eu-west-1", Context: "loadtest-eu", Capacity: 5},
+			{Name: "us-east-1", Context: "loadtest-us", KubeConfig: "/etc/kangal/us.kubeconfig", Capacity: 10},
+		}, clusters)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		_, err := ReadClustersConfig(write(t, "- context: loadtest-eu\n"), 10)
+		assert.ErrorIs(t, err, ErrClusterNameMissing)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, err := ReadClustersConfig(write(t, "- name: eu-west-1\n- name: eu-west-1\n"), 10)
+		assert.ErrorIs(t, err, ErrClusterNameDuplicate)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := ReadClustersConfig(write(t, "- name: eu-west-1\n  region: eu\n"), 10)
+		assert.Error(t, err)
+	})
+}
+
+func TestClustersPick(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	clusters := Clusters{
+		newTestCluster(t, "eu-west-1", 2, apisLoadTestV1.LoadTestRunning),
+		newTestCluster(t, "us-east-1", 3, apisLoadTestV1.LoadTestRunning, apisLoadTestV1.LoadTestFinished),
+		newTestCluster(t, "ap-south-1", 1, apisLoadTestV1.LoadTestCreating),
+	}
+
+	picked, err := clusters.Pick("", logger)
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", picked.Name)
+
+	picked, err = clusters.Pick("eu-west-1", logger)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", picked.Name)
+
+	_, err = clusters.Pick("ap-south-1", logger)
+	assert.ErrorIs(t, err, ErrNoCapacity)
+
+	_, err = clusters.Pick("sa-east-1", logger)
+	assert.ErrorIs(t, err, ErrUnknownCluster)
+
+	_, err = Clusters{clusters[2]}.Pick("", logger)
+	assert.ErrorIs(t, err, ErrNoCapacity)
+}
+
+func TestClustersPickSkipsUnreachable(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	loadtestClientSet := fakeClientset.NewSimpleClientset()
+	loadtestClientSet.Fake.PrependReactor("list", "loadtests", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errUnreachable
+	})
+	unreachable := &Cluster{
+		Name:       "sa-east-1",
+		Capacity:   5,
+		KubeClient: kube.NewClient(loadtestClientSet.KangalV1().LoadTests(), fake.NewSimpleClientset(), logger),
+	}
+	full := newTestCluster(t, "ap-south-1", 1, apisLoadTestV1.LoadTestCreating)
+
+	picked, err := Clusters{unreachable, newTestCluster(t, "eu-west-1", 2, apisLoadTestV1.LoadTestRunning)}.Pick("", logger)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", picked.Name)
+
+	_, err = Clusters{unreachable, full}.Pick("", logger)
+	assert.ErrorIs(t, err, ErrNoCapacity)
+
+	_, err = Clusters{unreachable}.Pick("", logger)
+	assert.ErrorIs(t, err, errUnreachable)
+
+	_, err = Clusters{unreachable, full}.Pick("sa-east-1", logger)
+	assert.ErrorIs(t, err, errUnreachable)
+}
+
+var errUnreachable = errors.New("cluster unreachable")
+
+func TestClustersFindLoadTest(t *testing.T) {
+	clusters := Clusters{
+		newTestCluster(t, "eu-west-1", 2, apisLoadTestV1.LoadTestRunning),
+		newTestCluster(t, "us-east-1", 2, apisLoadTestV1.LoadTestRunning),
+	}
+
+	cluster, loadTest, err := clusters.FindLoadTest(context.Background(), "us-east-1-loadtest-a")
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", cluster.Name)
+	assert.Equal(t, "us-east-1-loadtest-a", loadTest.Name)
+
+	_, _, err = clusters.FindLoadTest(context.Background(), "missing")
+	assert.True(t, k8sAPIErrors.IsNotFound(err))
+}
+
+func TestProxyListClusters(t *testing.T) {
+	clusters := Clusters{
+		newTestCluster(t, "eu-west-1", 2, apisLoadTestV1.LoadTestRunning),
+		newTestCluster(t, "us-east-1", 2, apisLoadTestV1.LoadTestFinished, apisLoadTestV1.LoadTestRunning),
+	}
+	testProxyHandler := NewClustersProxy(clusters, nil, 50, false, time.Hour, "")
+
+	list := func(query string) (int, LoadTestStatusPage) {
+		req := httptest.NewRequest(http.MethodGet, "/load-test"+query, nil)
+		req = req.WithContext(mPkg.SetLogger(req.Context(), zaptest.NewLogger(t)))
+		w := httptest.NewRecorder()
+		testProxyHandler.List(w, req)
+
+		var page LoadTestStatusPage
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		}
+		return w.Code, page
+	}
+
+	code, page := list("")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, page.Items, 3)
+	assert.Equal(t, "eu-west-1", page.Items[0].Cluster)
+	assert.Equal(t, "us-east-1", page.Items[1].Cluster)
+	assert.Empty(t, page.Continue)
+
+	code, page = list("?limit=1")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "us-east-1:", page.Continue)
+
+	code, page = list("?continue=us-east-1:")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "us-east-1", page.Items[0].Cluster)
+
+	code, _ = list("?continue=sa-east-1:")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -3,8 +3,10 @@ package proxy
 import (
 	"time"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	"github.com/hellofresh/kangal/pkg/core/observability"
 	"github.com/hellofresh/kangal/pkg/report"
+	"github.com/hellofresh/kangal/pkg/results"
 )
 
 // Config is the possible Proxy configurations
@@ -13,11 +15,26 @@ type Config struct {
 	Logger              observability.LoggerConfig
 	OpenAPI             OpenAPIConfig
 	Report              report.Config
+	Compare             results.Tolerances
 	MaxLoadTestsRun     int
 	MaxListLimit        int64 `envconfig:"MAX_LIST_LIMIT" required:"true" default:"50"`
 	MasterURL           string
 	AllowedCustomImages bool `envconfig:"ALLOWED_CUSTOM_IMAGES" default:"false"`
 
+	// Scheduling is the allowlist of scheduling constraints a loadtest can set
+	Scheduling backends.SchedulingPolicy
+
+	// MaxTTLAfterFinished is the max value allowed for loadtest ttlSecondsAfterFinished
+	MaxTTLAfterFinished time.Duration `envconfig:"MAX_TTL_AFTER_FINISHED" default:"168h"`
+
+	// ClustersConfig is the path to the file with the clusters loadtests are dispatched to,
+	// loadtests run in the cluster of --kubeconfig and --master-url when empty
+	ClustersConfig string `envconfig:"CLUSTERS_CONFIG"`
+
+	// CallbackSecret is the key the signing keys of loadtest callbacks are derived from, it is shared with
+	// the controller. Loadtests with callbacks are refused when it is empty
+	CallbackSecret string `envconfig:"NOTIFY_WEBHOOK_SECRET"`
+
 	// KubeClientTimeout specifies timeout for each operation done by kube client
 	KubeClientTimeout time.Duration `envconfig:"KUBE_CLIENT_TIMEOUT" default:"5s"`
 }
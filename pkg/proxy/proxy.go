@@ -3,9 +3,12 @@ package proxy
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -14,6 +17,7 @@ import (
 	"github.com/go-chi/render"
 	"go.uber.org/zap"
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	restClient "k8s.io/client-go/rest"
 
 	"github.com/hellofresh/kangal/pkg/backends"
@@ -25,15 +29,19 @@ import (
 
 const (
 	mimeJSON = "application/json; charset=utf-8"
+
+	// continueSeparator separates the cluster name and the kubernetes continue token when listing multiple clusters
+	continueSeparator = ":"
 )
 
 // Proxy handler
 type Proxy struct {
-	maxLoadTestsRun     int
 	maxListLimit        int64
 	registry            backends.Registry
-	kubeClient          *kube.Client
+	clusters            Clusters
 	allowedCustomImages bool
+	maxTTLAfterFinished time.Duration
+	callbackSecret      string
 }
 
 // MetricsReporter used to interface with the metrics configurations
@@ -42,21 +50,29 @@ type MetricsReporter struct {
 }
 
 // NewMetricsReporter contains loadtest metrics definition
-func NewMetricsReporter(meter metric.Meter, kubeClient *kube.Client) (*MetricsReporter, error) {
+func NewMetricsReporter(meter metric.Meter, clusters Clusters) (*MetricsReporter, error) {
 	countRunningLoadtests, err := meter.Int64ObservableUpDownCounter(
 		"kangal_loadtests_count",
 		metric.WithDescription("Current number of loadtests in cluster, grouped by type and phase"),
 		metric.WithInt64Callback(func(ctx context.Context, io metric.Int64Observer) error {
-			states, types, err := kubeClient.CountExistingLoadtests()
-			if err != nil {
-				return fmt.Errorf("could not get metric data for CountExistingLoadtests: %w", err)
-			}
-			for k, v := range states {
-				io.Observe(v, metric.WithAttributes(attribute.String("phase", k.String())))
-			}
-
-			for k, v := range types {
-				io.Observe(v, metric.WithAttributes(attribute.String("type", k.String())))
+			for _, cluster := range clusters {
+				states, types, err := cluster.KubeClient.CountExistingLoadtests()
+				if err != nil {
+					return fmt.Errorf("could not get metric data for CountExistingLoadtests: %w", err)
+				}
+
+				var clusterAttrs []attribute.KeyValue
+				if cluster.Name != "" {
+					clusterAttrs = append(clusterAttrs, attribute.String("cluster", cluster.Name))
+				}
+
+				for k, v := range states {
+					io.Observe(v, metric.WithAttributes(append(clusterAttrs, attribute.String("phase", k.String()))...))
+				}
+
+				for k, v := range types {
+					io.Observe(v, metric.WithAttributes(append(clusterAttrs, attribute.String("type", k.String()))...))
+				}
 			}
 			return nil
 		}),
@@ -71,14 +87,20 @@ func NewMetricsReporter(meter metric.Meter, kubeClient *kube.Client) (*MetricsRe
 		nil
 }
 
-// NewProxy returns new Proxy handlers
-func NewProxy(maxLoadTestsRun int, registry backends.Registry, kubeClient *kube.Client, maxListLimit int64, allowedCustomImages bool) *Proxy {
+// NewProxy returns new Proxy handlers managing loadtests in a single cluster
+func NewProxy(maxLoadTestsRun int, registry backends.Registry, kubeClient *kube.Client, maxListLimit int64, allowedCustomImages bool, maxTTLAfterFinished time.Duration, callbackSecret string) *Proxy {
+	return NewClustersProxy(SingleCluster(kubeClient, maxLoadTestsRun), registry, maxListLimit, allowedCustomImages, maxTTLAfterFinished, callbackSecret)
+}
+
+// NewClustersProxy returns new Proxy handlers dispatching loadtests to the given clusters
+func NewClustersProxy(clusters Clusters, registry backends.Registry, maxListLimit int64, allowedCustomImages bool, maxTTLAfterFinished time.Duration, callbackSecret string) *Proxy {
 	return &Proxy{
-		maxLoadTestsRun:     maxLoadTestsRun,
 		registry:            registry,
-		kubeClient:          kubeClient,
+		clusters:            clusters,
 		maxListLimit:        maxListLimit,
 		allowedCustomImages: allowedCustomImages,
+		maxTTLAfterFinished: maxTTLAfterFinished,
+		callbackSecret:      callbackSecret,
 	}
 }
 
@@ -92,13 +114,30 @@ type LoadTestStatusPage struct {
 
 // LoadTestStatus defines response structure for status request
 type LoadTestStatus struct {
-	Type            string                      `json:"type"`
-	DistributedPods int32                       `json:"distributedPods"`        // number of distributed pods requested
-	Namespace       string                      `json:"loadtestName,omitempty"` // namespace created equals the loadtest name
-	Phase           string                      `json:"phase,omitempty"`        // jmeter loadtest status
-	Tags            apisLoadTestV1.LoadTestTags `json:"tags"`
-	HasEnvVars      bool                        `json:"hasEnvVars"`
-	HasTestData     bool                        `json:"hasTestData"`
+	Cluster                 string                                `json:"cluster,omitempty"` // cluster the loadtest runs in when the proxy manages multiple clusters
+	Type                    string                                `json:"type"`
+	DistributedPods         int32                                 `json:"distributedPods"` // number of distributed pods requested
+	Name                    string                                `json:"loadtestName,omitempty"`
+	Namespace               string                                `json:"namespace,omitempty"` // namespace the loadtest runs in
+	Phase                   string                                `json:"phase,omitempty"`     // jmeter loadtest status
+	Tags                    apisLoadTestV1.LoadTestTags           `json:"tags"`
+	HasEnvVars              bool                                  `json:"hasEnvVars"`
+	HasTestData             bool                                  `json:"hasTestData"`
+	Reason                  string                                `json:"reason,omitempty"`  // why the loadtest is in its current phase
+	Message                 string                                `json:"message,omitempty"` // human readable details for the reason
+	StartTime               *metaV1.Time                          `json:"startTime,omitempty"`
+	CompletionTime          *metaV1.Time                          `json:"completionTime,omitempty"`
+	Conditions              []metaV1.Condition                    `json:"conditions,omitempty"`
+	TTLSecondsAfterFinished *int32                                `json:"ttlSecondsAfterFinished,omitempty"` // seconds the loadtest is kept after it finished
+	MasterResources         *apisLoadTestV1.LoadTestResources     `json:"masterResources,omitempty"`
+	WorkerResources         *apisLoadTestV1.LoadTestResources     `json:"workerResources,omitempty"`
+	Scheduling              *apisLoadTestV1.LoadTestScheduling    `json:"scheduling,omitempty"`
+	Thresholds              *apisLoadTestV1.LoadTestThresholds    `json:"thresholds,omitempty"`
+	CompareBaseline         bool                                  `json:"compareBaseline,omitempty"`
+	SecretEnvVars           []apisLoadTestV1.LoadTestSecretEnvVar `json:"secretEnvVars,omitempty"` // references only, values are never returned
+	Callbacks               []string                              `json:"callbacks,omitempty"`
+	Notifications           []apisLoadTestV1.LoadTestNotification `json:"notifications,omitempty"`   // delivery states of completion webhooks
+	CallbackSecrets         map[string]string                     `json:"callbackSecrets,omitempty"` // signing keys of the callbacks, only returned on creation
 }
 
 // List lists all the load tests.
@@ -116,7 +155,12 @@ func (p *Proxy) List(w http.ResponseWriter, r *http.Request) {
 
 	logger.Debug("Retrieving info for load tests")
 
-	loadTests, err := p.kubeClient.ListLoadTest(ctx, *opt)
+	if len(p.clusters) > 1 {
+		p.listClusters(w, r, *opt)
+		return
+	}
+
+	loadTests, err := p.clusters[0].KubeClient.ListLoadTest(ctx, *opt)
 	if err != nil {
 		logger.Error("could not list load tests", zap.Error(err))
 		render.Render(w, r, cHttp.ErrResponse(http.StatusInternalServerError, err.Error()))
@@ -124,25 +168,91 @@ func (p *Proxy) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items := make([]LoadTestStatus, len(loadTests.Items))
-	for i, lt := range loadTests.Items {
+	render.JSON(w, r, &LoadTestStatusPage{
+		Limit:    opt.Limit,
+		Continue: loadTests.Continue,
+		Remain:   loadTests.RemainingItemCount,
+		Items:    listItems(p.clusters[0], loadTests.Items),
+	})
+}
+
+// listClusters lists load tests of all clusters one cluster after another. The continue token
+// is prefixed with the name of the cluster the next page is listed from
+func (p *Proxy) listClusters(w http.ResponseWriter, r *http.Request, opt kube.ListOptions) {
+	ctx := r.Context()
+	logger := mPkg.GetLogger(ctx)
+
+	start := 0
+	if opt.Continue != "" {
+		clusterName, token, _ := strings.Cut(opt.Continue, continueSeparator)
+		found := false
+		for i, cluster := range p.clusters {
+			if cluster.Name == clusterName {
+				start, found = i, true
+				break
+			}
+		}
+		if !found {
+			render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, fmt.Sprintf("invalid continue token: %s", ErrUnknownCluster)))
+			return
+		}
+		opt.Continue = token
+	}
+
+	limit := opt.Limit
+	items := []LoadTestStatus{}
+	next := ""
+	for i := start; i < len(p.clusters); i++ {
+		if int64(len(items)) >= limit {
+			next = p.clusters[i].Name + continueSeparator
+			break
+		}
+
+		cluster := p.clusters[i]
+		opt.Limit = limit - int64(len(items))
+		loadTests, err := cluster.KubeClient.ListLoadTest(ctx, opt)
+		if err != nil {
+			logger.Error("could not list load tests", zap.String("cluster", cluster.Name), zap.Error(err))
+			render.Render(w, r, cHttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+		items = append(items, listItems(cluster, loadTests.Items)...)
+
+		if loadTests.Continue != "" {
+			next = cluster.Name + continueSeparator + loadTests.Continue
+			break
+		}
+		opt.Continue = ""
+	}
+
+	render.JSON(w, r, &LoadTestStatusPage{
+		Limit:    limit,
+		Continue: next,
+		Items:    items,
+	})
+}
+
+func listItems(cluster *Cluster, loadTests []apisLoadTestV1.LoadTest) []LoadTestStatus {
+	items := make([]LoadTestStatus, len(loadTests))
+	for i, lt := range loadTests {
 		items[i] = LoadTestStatus{
+			Cluster:         cluster.Name,
 			Type:            lt.Spec.Type.String(),
 			DistributedPods: *lt.Spec.DistributedPods,
+			Name:            lt.Name,
 			Namespace:       lt.Status.Namespace,
 			Phase:           lt.Status.Phase.String(),
 			Tags:            lt.Spec.Tags,
 			HasEnvVars:      len(lt.Spec.EnvVars) != 0,
 			HasTestData:     len(lt.Spec.TestData) != 0,
+			Reason:          lt.Status.Reason,
+			Message:         lt.Status.Message,
+			StartTime:       lt.Status.StartTime,
+			CompletionTime:  lt.Status.CompletionTime,
+			Conditions:      lt.Status.Conditions,
 		}
 	}
-
-	render.JSON(w, r, &LoadTestStatusPage{
-		Limit:    opt.Limit,
-		Continue: loadTests.Continue,
-		Remain:   loadTests.RemainingItemCount,
-		Items:    items,
-	})
+	return items
 }
 
 // Create creates loadtest CR on POST request
@@ -157,6 +267,17 @@ func (p *Proxy) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(ltSpec.Callbacks) > 0 && p.callbackSecret == "" {
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, "callbacks are not enabled, NOTIFY_WEBHOOK_SECRET is not configured"))
+		return
+	}
+
+	if ltSpec.TTLSecondsAfterFinished != nil && time.Duration(*ltSpec.TTLSecondsAfterFinished)*time.Second > p.maxTTLAfterFinished {
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest,
+			fmt.Sprintf("ttlSecondsAfterFinished value is too big, max possible value is %d", int64(p.maxTTLAfterFinished/time.Second))))
+		return
+	}
+
 	backend, err := p.registry.GetBackend(ltSpec.Type)
 	if err != nil {
 		logger.Error("could not get backend", zap.Error(err))
@@ -177,51 +298,56 @@ func (p *Proxy) Create(w http.ResponseWriter, r *http.Request) {
 		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, err.Error()))
 		return
 	}
+	apisLoadTestV1.SetTransformed(loadTest)
 
-	// Find the old load test with the same data
-	labeledLoadTests, err := p.kubeClient.GetLoadTestsByLabel(ctx, loadTest)
-	if err != nil {
-		logger.Error("Could not count active load tests with given hash", zap.Error(err))
-		render.Render(w, r, cHttp.ErrResponse(http.StatusInternalServerError, "Could not count active load tests with given hash"))
-		return
-	}
-
-	if len(labeledLoadTests.Items) > 0 {
-		if !loadTest.Spec.Overwrite {
-			render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest,
-				"Load test with given testfile already exists, aborting. Please delete existing load test and try again."))
+	// Find the old load test with the same data in all clusters
+	for _, cluster := range p.clusters {
+		labeledLoadTests, err := cluster.KubeClient.GetLoadTestsByLabel(ctx, loadTest)
+		if err != nil {
+			logger.Error("Could not count active load tests with given hash", zap.String("cluster", cluster.Name), zap.Error(err))
+			render.Render(w, r, cHttp.ErrResponse(http.StatusInternalServerError, "Could not count active load tests with given hash"))
 			return
 		}
 
-		// If users wants to overwrite
-		for _, item := range labeledLoadTests.Items {
-			// Remove the old tests
-			err := p.kubeClient.DeleteLoadTest(ctx, item.Name)
-			if err != nil {
-				logger.Error("Could not delete load test with error", zap.Error(err))
-				render.Render(w, r, cHttp.ErrResponse(http.StatusConflict, err.Error()))
+		if len(labeledLoadTests.Items) > 0 {
+			if !loadTest.Spec.Overwrite {
+				render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest,
+					"Load test with given testfile already exists, aborting. Please delete existing load test and try again."))
 				return
 			}
+
+			// If users wants to overwrite
+			for _, item := range labeledLoadTests.Items {
+				// Remove the old tests
+				err := cluster.KubeClient.DeleteLoadTest(ctx, item.Name)
+				if err != nil {
+					logger.Error("Could not delete load test with error", zap.Error(err))
+					render.Render(w, r, cHttp.ErrResponse(http.StatusConflict, err.Error()))
+					return
+				}
+			}
 		}
 	}
 
-	// check the number of active loadtests currently running on the cluster
-	testsByPhase, _, err := p.kubeClient.CountExistingLoadtests()
-	if err != nil {
-		logger.Error("Could not count active load tests", zap.Error(err))
-		render.Render(w, r, cHttp.ErrResponse(http.StatusInternalServerError, "Could not count active load tests"))
+	// pick the cluster by the number of active loadtests currently running on the clusters
+	clusterName := r.FormValue(targetCluster)
+	target, err := p.clusters.Pick(clusterName, logger)
+	switch {
+	case errors.Is(err, ErrUnknownCluster):
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, err.Error()))
 		return
-	}
-	activeLoadTests := testsByPhase["running"] + testsByPhase["creating"]
-
-	if int(activeLoadTests) >= p.maxLoadTestsRun {
-		logger.Warn("number of active load tests reached limit", zap.Int("current", int(activeLoadTests)), zap.Int("limit", p.maxLoadTestsRun))
+	case errors.Is(err, ErrNoCapacity):
+		logger.Warn("number of active load tests reached limit", zap.String("cluster", clusterName))
 		render.Render(w, r, cHttp.ErrResponse(http.StatusTooManyRequests, "Number of active load tests reached limit"))
 		return
+	case err != nil:
+		logger.Error("Could not count active load tests", zap.Error(err))
+		render.Render(w, r, cHttp.ErrResponse(http.StatusInternalServerError, "Could not count active load tests"))
+		return
 	}
 
 	// Pushing LoadTest to Kubernetes
-	loadTestName, err := p.kubeClient.CreateLoadTest(ctx, loadTest)
+	loadTestName, err := target.KubeClient.CreateLoadTest(ctx, loadTest)
 	if err != nil {
 		logger.Error("Could not create load test", zap.Error(err))
 		render.Render(w, r, cHttp.ErrResponse(http.StatusConflict, err.Error()))
@@ -230,16 +356,32 @@ func (p *Proxy) Create(w http.ResponseWriter, r *http.Request) {
 
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, &LoadTestStatus{
+		Cluster:         target.Name,
 		Type:            loadTest.Spec.Type.String(),
 		DistributedPods: *loadTest.Spec.DistributedPods,
-		Namespace:       loadTestName,
+		Name:            loadTestName,
 		Phase:           string(apisLoadTestV1.LoadTestCreating),
 		Tags:            loadTest.Spec.Tags,
 		HasEnvVars:      len(loadTest.Spec.EnvVars) != 0,
 		HasTestData:     len(loadTest.Spec.TestData) != 0,
+		Callbacks:       loadTest.Spec.Callbacks,
+		CallbackSecrets: p.callbackSecrets(loadTestName, loadTest.Spec.Callbacks),
 	})
 }
 
+// callbackSecrets returns the keys signing the notifications of the loadtest callbacks, keyed by callback url
+func (p *Proxy) callbackSecrets(loadTestName string, callbacks []string) map[string]string {
+	if len(callbacks) == 0 {
+		return nil
+	}
+
+	secrets := make(map[string]string, len(callbacks))
+	for _, callback := range callbacks {
+		secrets[callback] = backends.CallbackSecret(p.callbackSecret, loadTestName, callback)
+	}
+	return secrets
+}
+
 // Delete deletes load test CR
 func (p *Proxy) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -248,7 +390,10 @@ func (p *Proxy) Delete(w http.ResponseWriter, r *http.Request) {
 	ltID := chi.URLParam(r, loadTestID)
 	logger.Debug("Deleting loadtest", zap.String("ltID", ltID))
 
-	err := p.kubeClient.DeleteLoadTest(ctx, ltID)
+	ltCluster, err := p.clusters.Locate(ctx, ltID)
+	if err == nil {
+		err = ltCluster.KubeClient.DeleteLoadTest(ctx, ltID)
+	}
 	if err != nil {
 		logger.Error("Could not delete load test with error", zap.Error(err))
 		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, err.Error()))
@@ -266,7 +411,7 @@ func (p *Proxy) Get(w http.ResponseWriter, r *http.Request) {
 	ltID := chi.URLParam(r, loadTestID)
 	logger.Debug("Retrieving info for loadtest", zap.String("ltID", ltID))
 
-	result, err := p.kubeClient.GetLoadTest(ctx, ltID)
+	ltCluster, result, err := p.clusters.FindLoadTest(ctx, ltID)
 	if err != nil {
 		logger.Error("Could not get load test info with error", zap.Error(err))
 
@@ -280,13 +425,29 @@ func (p *Proxy) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	render.JSON(w, r, &LoadTestStatus{
-		Type:            result.Spec.Type.String(),
-		DistributedPods: *result.Spec.DistributedPods,
-		Namespace:       result.Status.Namespace,
-		Phase:           result.Status.Phase.String(),
-		Tags:            result.Spec.Tags,
-		HasEnvVars:      len(result.Spec.EnvVars) != 0,
-		HasTestData:     len(result.Spec.TestData) != 0,
+		Cluster:                 ltCluster.Name,
+		Type:                    result.Spec.Type.String(),
+		DistributedPods:         *result.Spec.DistributedPods,
+		Name:                    result.Name,
+		Namespace:               result.Status.Namespace,
+		Phase:                   result.Status.Phase.String(),
+		Tags:                    result.Spec.Tags,
+		HasEnvVars:              len(result.Spec.EnvVars) != 0,
+		HasTestData:             len(result.Spec.TestData) != 0,
+		Reason:                  result.Status.Reason,
+		Message:                 result.Status.Message,
+		StartTime:               result.Status.StartTime,
+		CompletionTime:          result.Status.CompletionTime,
+		Conditions:              result.Status.Conditions,
+		TTLSecondsAfterFinished: result.Spec.TTLSecondsAfterFinished,
+		MasterResources:         result.Spec.MasterResources,
+		WorkerResources:         result.Spec.WorkerResources,
+		Scheduling:              result.Spec.Scheduling,
+		Thresholds:              result.Spec.Thresholds,
+		CompareBaseline:         result.Spec.CompareBaseline,
+		SecretEnvVars:           result.Spec.SecretEnvVars,
+		Callbacks:               result.Spec.Callbacks,
+		Notifications:           result.Status.Notifications,
 	})
 }
 
@@ -299,7 +460,7 @@ func (p *Proxy) GetLogs(w http.ResponseWriter, r *http.Request) {
 	var logsRequest *restClient.Request
 	logger.Info("Retrieving logs for loadtest", zap.String("ltID", ltID))
 
-	loadTest, err := p.kubeClient.GetLoadTest(ctx, ltID)
+	ltCluster, loadTest, err := p.clusters.FindLoadTest(ctx, ltID)
 	if err != nil {
 		logger.Error("Could not get load test info with error", zap.Error(err))
 		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, err.Error()))
@@ -315,7 +476,7 @@ func (p *Proxy) GetLogs(w http.ResponseWriter, r *http.Request) {
 
 	if workerID == "" {
 		logger.Info("Returning master pod logs")
-		logsRequest, err = p.kubeClient.GetMasterPodRequest(ctx, namespace)
+		logsRequest, err = ltCluster.KubeClient.GetMasterPodRequest(ctx, *loadTest)
 		if err != nil {
 			logger.Error("Could not get load test logs request:", zap.Error(err))
 			render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, err.Error()))
@@ -323,7 +484,7 @@ func (p *Proxy) GetLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		logger.Info("Returning worker pod logs")
-		logsRequest, err = p.kubeClient.GetWorkerPodRequest(ctx, namespace, workerID)
+		logsRequest, err = ltCluster.KubeClient.GetWorkerPodRequest(ctx, *loadTest, workerID)
 		if err != nil {
 			logger.Error("Could not get load test logs request:", zap.Error(err))
 			render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, err.Error()))
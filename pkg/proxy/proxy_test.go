@@ -135,6 +135,7 @@ func TestProxy_List(t *testing.T) {
 				},
 				Items: []apisLoadTestV1.LoadTest{
 					{
+						ObjectMeta: metaV1.ObjectMeta{Name: "random"},
 						Spec: apisLoadTestV1.LoadTestSpec{
 							Type:            apisLoadTestV1.LoadTestTypeJMeter,
 							DistributedPods: &distributedPods,
@@ -151,7 +152,7 @@ func TestProxy_List(t *testing.T) {
 			},
 			expectedCode:        200,
 			expectedContentType: "application/json",
-			expectedResponse:    `{"limit":50,"continue":"continue","remain":42,"items":[{"type":"JMeter","distributedPods":2,"loadtestName":"random","phase":"running","tags":{},"hasEnvVars":false,"hasTestData":true}]}`,
+			expectedResponse:    `{"limit":50,"continue":"continue","remain":42,"items":[{"type":"JMeter","distributedPods":2,"loadtestName":"random","namespace":"random","phase":"running","tags":{},"hasEnvVars":false,"hasTestData":true}]}`,
 		},
 		{
 			scenario:  "success",
@@ -164,6 +165,7 @@ func TestProxy_List(t *testing.T) {
 				Items: []apisLoadTestV1.LoadTest{
 					{
 						ObjectMeta: metaV1.ObjectMeta{
+							Name: "random",
 							Labels: map[string]string{
 								"test-tag-department": "platform",
 								"test-tag-team":       "kangal",
@@ -188,7 +190,7 @@ func TestProxy_List(t *testing.T) {
 			},
 			expectedCode:        200,
 			expectedContentType: "application/json",
-			expectedResponse:    `{"limit":10,"continue":"continue","remain":42,"items":[{"type":"JMeter","distributedPods":2,"loadtestName":"random","phase":"running","tags":{"department":"platform","team":"kangal"},"hasEnvVars":false,"hasTestData":true}]}`,
+			expectedResponse:    `{"limit":10,"continue":"continue","remain":42,"items":[{"type":"JMeter","distributedPods":2,"loadtestName":"random","namespace":"random","phase":"running","tags":{"department":"platform","team":"kangal"},"hasEnvVars":false,"hasTestData":true}]}`,
 		},
 	}
 
@@ -208,7 +210,7 @@ func TestProxy_List(t *testing.T) {
 			})
 			c := kube.NewClient(loadTestClientSet.KangalV1().LoadTests(), kubeClientSet, logger)
 
-			testProxyHandler := NewProxy(1, nil, c, 50, false)
+			testProxyHandler := NewProxy(1, nil, c, 50, false, time.Hour, "")
 
 			req := httptest.NewRequest("POST", "http://example.com/foo?"+tc.urlParams, nil)
 			req = req.WithContext(ctx)
@@ -312,7 +314,7 @@ func TestProxyCreate(t *testing.T) {
 				backends.WithKangalClientSet(loadtestClientSet),
 			)
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, time.Hour, "")
 			handler := testProxyHandler.Create
 
 			requestWrap := createRequestWrapper(t, tt.requestFiles, strconv.Itoa(tt.distributedPods), string(tt.loadTestType), tt.tagsString, false, "", "")
@@ -335,6 +337,130 @@ func TestProxyCreate(t *testing.T) {
 	}
 }
 
+func TestProxyCreateTTLAfterFinished(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		ttl              string
+		expectedCode     int
+		expectedResponse string
+	}{
+		{
+			"TTL within max value",
+			"3600",
+			http.StatusCreated,
+			`{"type":"Fake","distributedPods":1,"phase":"creating","tags":{},"hasEnvVars":false,"hasTestData":false}` + "\n",
+		},
+		{
+			"TTL exceeds max value",
+			"3601",
+			http.StatusBadRequest,
+			`{"error":"ttlSecondsAfterFinished value is too big, max possible value is 3600"}` + "\n",
+		},
+		{
+			"Negative TTL",
+			"-1",
+			http.StatusBadRequest,
+			`{"error":"error getting ttlSecondsAfterFinished from request: ttl can not be negative"}` + "\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				kubeClientSet     = fake.NewSimpleClientset()
+				loadtestClientSet = fakeClientset.NewSimpleClientset()
+				logger            = zaptest.NewLogger(t)
+			)
+			ctx := mPkg.SetLogger(context.Background(), logger)
+			loadtestClientSet.Fake.PrependReactor("create", "loadtests", func(action k8sTesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, &apisLoadTestV1.LoadTest{}, nil
+			})
+			c := kube.NewClient(loadtestClientSet.KangalV1().LoadTests(), kubeClientSet, logger)
+
+			b := backends.New(
+				backends.WithLogger(logger),
+				backends.WithKubeClientSet(kubeClientSet),
+				backends.WithKangalClientSet(loadtestClientSet),
+			)
+
+			testProxyHandler := NewProxy(1, b, c, 50, false, time.Hour, "")
+
+			requestWrap := createRequestWrapper(t, map[string]string{"testFile": "testdata/valid/loadtest.jmx"}, "1", string(apisLoadTestV1.LoadTestTypeFake), "", false, "", "")
+
+			req := httptest.NewRequest("POST", "http://example.com/foo?ttlSecondsAfterFinished="+tt.ttl, requestWrap.body)
+			req.Header.Set("Content-Type", requestWrap.contentType)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			testProxyHandler.Create(w, req)
+
+			resp := w.Result()
+			respBody, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+			assert.Equal(t, tt.expectedResponse, string(respBody))
+		})
+	}
+}
+
+func TestProxyCreateCallbacks(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		callbackSecret   string
+		expectedCode     int
+		expectedResponse string
+	}{
+		{
+			"Callback secrets are returned",
+			"webhook-secret",
+			http.StatusCreated,
+			`{"type":"Fake","distributedPods":1,"loadtestName":"loadtest-name","phase":"creating","tags":{},"hasEnvVars":false,"hasTestData":false,` +
+				`"callbacks":["https://ci.example.com/hooks/kangal"],"callbackSecrets":{"https://ci.example.com/hooks/kangal":"` +
+				backends.CallbackSecret("webhook-secret", "loadtest-name", "https://ci.example.com/hooks/kangal") + `"}}` + "\n",
+		},
+		{
+			"Callbacks are not enabled",
+			"",
+			http.StatusBadRequest,
+			`{"error":"callbacks are not enabled, NOTIFY_WEBHOOK_SECRET is not configured"}` + "\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				kubeClientSet     = fake.NewSimpleClientset()
+				loadtestClientSet = fakeClientset.NewSimpleClientset()
+				logger            = zaptest.NewLogger(t)
+			)
+			ctx := mPkg.SetLogger(context.Background(), logger)
+			loadtestClientSet.Fake.PrependReactor("create", "loadtests", func(action k8sTesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, &apisLoadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}, nil
+			})
+			c := kube.NewClient(loadtestClientSet.KangalV1().LoadTests(), kubeClientSet, logger)
+
+			b := backends.New(
+				backends.WithLogger(logger),
+				backends.WithKubeClientSet(kubeClientSet),
+				backends.WithKangalClientSet(loadtestClientSet),
+			)
+
+			testProxyHandler := NewProxy(1, b, c, 50, false, time.Hour, tt.callbackSecret)
+
+			requestWrap := createRequestWrapper(t, map[string]string{"testFile": "testdata/valid/loadtest.jmx"}, "1", string(apisLoadTestV1.LoadTestTypeFake), "", false, "", "")
+
+			req := httptest.NewRequest("POST", "http://example.com/foo?callbacks=https://ci.example.com/hooks/kangal", requestWrap.body)
+			req.Header.Set("Content-Type", requestWrap.contentType)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			testProxyHandler.Create(w, req)
+
+			resp := w.Result()
+			respBody, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+			assert.Equal(t, tt.expectedResponse, string(respBody))
+		})
+	}
+}
+
 func TestNewProxyRecreate(t *testing.T) {
 	for _, tt := range []struct {
 		name             string
@@ -416,7 +542,7 @@ func TestNewProxyRecreate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, time.Hour, "")
 			testProxyHandler.Create(w, req)
 
 			resp := w.Result()
@@ -516,7 +642,7 @@ func TestProxyCreateWithErrors(t *testing.T) {
 			req.Header.Set("Content-Type", requestWrap.contentType)
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, time.Hour, "")
 			testProxyHandler.Create(w, req)
 
 			resp := w.Result()
@@ -530,6 +656,7 @@ func TestProxyCreateWithErrors(t *testing.T) {
 
 func TestProxyGet(t *testing.T) {
 	var pods = int32(1)
+	var ttlSeconds = int32(86400)
 	for _, tt := range []struct {
 		name             string
 		loadTest         apisLoadTestV1.LoadTest
@@ -540,6 +667,7 @@ func TestProxyGet(t *testing.T) {
 		{
 			"Valid request",
 			apisLoadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "aaa"},
 				Spec: apisLoadTestV1.LoadTestSpec{
 					Type:            apisLoadTestV1.LoadTestTypeJMeter,
 					DistributedPods: &pods,
@@ -552,7 +680,58 @@ func TestProxyGet(t *testing.T) {
 					Namespace: "aaa",
 				}},
 			http.StatusOK,
-			`{"type":"JMeter","distributedPods":1,"loadtestName":"aaa","phase":"running","tags":{"team":"kangal"},"hasEnvVars":false,"hasTestData":false}` + "\n",
+			`{"type":"JMeter","distributedPods":1,"loadtestName":"aaa","namespace":"aaa","phase":"running","tags":{"team":"kangal"},"hasEnvVars":false,"hasTestData":false}` + "\n",
+			nil,
+		},
+		{
+			"Shared namespace",
+			apisLoadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "aaa"},
+				Spec: apisLoadTestV1.LoadTestSpec{
+					Type:            apisLoadTestV1.LoadTestTypeJMeter,
+					DistributedPods: &pods,
+				},
+				Status: apisLoadTestV1.LoadTestStatus{
+					Phase:     apisLoadTestV1.LoadTestRunning,
+					Namespace: "kangal-loadtests",
+				}},
+			http.StatusOK,
+			`{"type":"JMeter","distributedPods":1,"loadtestName":"aaa","namespace":"kangal-loadtests","phase":"running","tags":null,"hasEnvVars":false,"hasTestData":false}` + "\n",
+			nil,
+		},
+		{
+			"With TTL",
+			apisLoadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "aaa"},
+				Spec: apisLoadTestV1.LoadTestSpec{
+					Type:                    apisLoadTestV1.LoadTestTypeFake,
+					DistributedPods:         &pods,
+					TTLSecondsAfterFinished: &ttlSeconds,
+				},
+				Status: apisLoadTestV1.LoadTestStatus{
+					Phase:     apisLoadTestV1.LoadTestFinished,
+					Namespace: "aaa",
+				}},
+			http.StatusOK,
+			`{"type":"Fake","distributedPods":1,"loadtestName":"aaa","namespace":"aaa","phase":"finished","tags":null,"hasEnvVars":false,"hasTestData":false,"ttlSecondsAfterFinished":86400}` + "\n",
+			nil,
+		},
+		{
+			"Errored with reason",
+			apisLoadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "aaa"},
+				Spec: apisLoadTestV1.LoadTestSpec{
+					Type:            apisLoadTestV1.LoadTestTypeK6,
+					DistributedPods: &pods,
+				},
+				Status: apisLoadTestV1.LoadTestStatus{
+					Phase:     apisLoadTestV1.LoadTestErrored,
+					Namespace: "aaa",
+					Reason:    apisLoadTestV1.LoadTestReasonImagePullFailure,
+					Message:   "container k6 in pod loadtest-job-0 is waiting: ImagePullBackOff",
+				}},
+			http.StatusOK,
+			`{"type":"K6","distributedPods":1,"loadtestName":"aaa","namespace":"aaa","phase":"errored","tags":null,"hasEnvVars":false,"hasTestData":false,"reason":"ImagePullFailure","message":"container k6 in pod loadtest-job-0 is waiting: ImagePullBackOff"}` + "\n",
 			nil,
 		},
 		{
@@ -590,7 +769,7 @@ func TestProxyGet(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, time.Hour, "")
 			testProxyHandler.Get(w, req)
 
 			resp := w.Result()
@@ -640,7 +819,7 @@ func TestProxyDelete(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, nil, c, 50, false)
+			testProxyHandler := NewProxy(1, nil, c, 50, false, time.Hour, "")
 			testProxyHandler.Delete(w, req)
 
 			resp := w.Result()
@@ -740,7 +919,7 @@ func TestProxyGetLogs(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, time.Hour, "")
 			testProxyHandler.GetLogs(w, req.WithContext(ctx))
 
 			resp := w.Result()
@@ -3,6 +3,7 @@ package proxy
 import (
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -31,6 +32,15 @@ const (
 	envVars         = "envVars"
 	targetURL       = "targetURL"
 	duration        = "duration"
+	ttl             = "ttlSecondsAfterFinished"
+	masterResources = "masterResources"
+	workerResources = "workerResources"
+	scheduling      = "scheduling"
+	thresholds      = "thresholds"
+	compareBaseline = "compareBaseline"
+	secretEnvVars   = "secretEnvVars"
+	callbacks       = "callbacks"
+	targetCluster   = "cluster"
 	loadTestID      = "id"
 	workerPodID     = "worker"
 )
@@ -46,6 +56,8 @@ var (
 	ErrWrongImageFormat = errors.New("invalid image format")
 	// ErrEmptyType is the error returned when there's no loadtest type provided
 	ErrEmptyType = errors.New("loadtest type is empty")
+	// ErrNegativeTTL is the error returned when the ttlSecondsAfterFinished is negative
+	ErrNegativeTTL = errors.New("ttl can not be negative")
 
 	testFileFormats = map[string]bool{
 		"jmx":  true,
@@ -162,6 +174,54 @@ func fromHTTPRequestToLoadTestSpec(r *http.Request, logger *zap.Logger, allowedC
 		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", duration, err)
 	}
 
+	ttlSeconds, err := getTTLSecondsAfterFinished(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", ttl), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", ttl, err)
+	}
+
+	mr, err := apisLoadTestV1.LoadTestResourcesFromString(r.FormValue(masterResources))
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", masterResources), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", masterResources, err)
+	}
+
+	wr, err := apisLoadTestV1.LoadTestResourcesFromString(r.FormValue(workerResources))
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", workerResources), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", workerResources, err)
+	}
+
+	th, err := apisLoadTestV1.LoadTestThresholdsFromString(r.FormValue(thresholds))
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", thresholds), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", thresholds, err)
+	}
+
+	cb, err := getCompareBaseline(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", compareBaseline), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("bad %s value: should be boolean", compareBaseline)
+	}
+	// baselines are load tests with the same tags, without tags every load test would be a baseline
+	if cb && len(tagList) == 0 {
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("%s requires %s selecting the baseline", compareBaseline, tags)
+	}
+
+	sev, err := apisLoadTestV1.LoadTestSecretEnvVarsFromString(r.FormValue(secretEnvVars))
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", secretEnvVars), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", secretEnvVars, err)
+	}
+
+	cbs := getCallbacks(r)
+
+	sc, err := getScheduling(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", scheduling), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", scheduling, err)
+	}
+
 	mi := apisLoadTestV1.ImageDetails{
 		Image: "",
 		Tag:   "",
@@ -195,6 +255,15 @@ func fromHTTPRequestToLoadTestSpec(r *http.Request, logger *zap.Logger, allowedC
 		EnvVars:         ev,
 		TargetURL:       turl,
 		Duration:        dur,
+
+		TTLSecondsAfterFinished: ttlSeconds,
+		MasterResources:         mr,
+		WorkerResources:         wr,
+		Scheduling:              sc,
+		Thresholds:              th,
+		CompareBaseline:         cb,
+		SecretEnvVars:           sev,
+		Callbacks:               cbs,
 	}, nil
 }
 
@@ -322,6 +391,31 @@ func getOverwrite(r *http.Request) (bool, error) {
 	return overwrite, nil
 }
 
+func getCompareBaseline(r *http.Request) (bool, error) {
+	cb := r.FormValue(compareBaseline)
+	if cb == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(cb)
+}
+
+// getCallbacks returns callback URLs, each one is sent as a separate form value,
+// URLs are validated by backends together with the rest of the spec
+func getCallbacks(r *http.Request) []string {
+	if r.Form == nil {
+		r.FormValue(callbacks)
+	}
+
+	var urls []string
+	for _, value := range r.Form[callbacks] {
+		if value = strings.TrimSpace(value); value != "" {
+			urls = append(urls, value)
+		}
+	}
+	return urls
+}
+
 func getLoadTestType(r *http.Request) (apisLoadTestV1.LoadTestType, error) {
 	ltType := r.FormValue(backendType)
 	if ltType == "" {
@@ -369,6 +463,44 @@ func getDuration(r *http.Request) (time.Duration, error) {
 	return time.ParseDuration(val)
 }
 
+func getTTLSecondsAfterFinished(r *http.Request) (*int32, error) {
+	val := r.FormValue(ttl)
+
+	if val == "" {
+		return nil, nil
+	}
+
+	seconds, err := strconv.ParseInt(val, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	if seconds < 0 {
+		return nil, ErrNegativeTTL
+	}
+
+	ttlSeconds := int32(seconds)
+	return &ttlSeconds, nil
+}
+
+func getScheduling(r *http.Request) (*apisLoadTestV1.LoadTestScheduling, error) {
+	val := r.FormValue(scheduling)
+
+	if val == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(val))
+	decoder.DisallowUnknownFields()
+
+	var sc apisLoadTestV1.LoadTestScheduling
+	if err := decoder.Decode(&sc); err != nil {
+		return nil, err
+	}
+
+	return &sc, nil
+}
+
 func getImage(r *http.Request, role string) (apisLoadTestV1.ImageDetails, error) {
 	imageStr := r.FormValue(role)
 
@@ -417,6 +417,21 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestCompareBaselineRequiresTags(t *testing.T) {
+	requestFiles := map[string]string{testFile: "testdata/valid/loadtest.jmx"}
+
+	request := buildMocFormReq(t, requestFiles, "1", string(apisLoadTestV1.LoadTestTypeJMeter), "", "", "")
+	request.URL.RawQuery = "compareBaseline=true"
+	_, err := fromHTTPRequestToLoadTestSpec(request, zaptest.NewLogger(t), false)
+	assert.EqualError(t, err, "compareBaseline requires tags selecting the baseline")
+
+	request = buildMocFormReq(t, requestFiles, "1", string(apisLoadTestV1.LoadTestTypeJMeter), "service:checkout", "", "")
+	request.URL.RawQuery = "compareBaseline=true"
+	spec, err := fromHTTPRequestToLoadTestSpec(request, zaptest.NewLogger(t), false)
+	require.NoError(t, err)
+	assert.True(t, spec.CompareBaseline)
+}
+
 func TestCheckLoadTestSpec(t *testing.T) {
 	ltType := apisLoadTestV1.LoadTestTypeJMeter
 	requestFiles := map[string]string{
@@ -482,6 +497,111 @@ func TestGetDuration(t *testing.T) {
 	}
 }
 
+func TestGetTTLSecondsAfterFinished(t *testing.T) {
+	ttlSeconds := int32(3600)
+
+	scenarios := []struct {
+		ttl         string
+		expected    *int32
+		expectError bool
+	}{
+		{
+			ttl:         "3600",
+			expected:    &ttlSeconds,
+			expectError: false,
+		},
+		{
+			ttl:         "-1",
+			expected:    nil,
+			expectError: true,
+		},
+		{
+			ttl:         "1h",
+			expected:    nil,
+			expectError: true,
+		},
+		{
+			ttl:         "",
+			expected:    nil,
+			expectError: false,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		req.Form = url.Values{"ttlSecondsAfterFinished": []string{scenario.ttl}}
+		req.ParseForm()
+
+		actual, err := getTTLSecondsAfterFinished(req)
+
+		if scenario.expectError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, scenario.expected, actual)
+	}
+}
+
+func TestGetScheduling(t *testing.T) {
+	scenarios := []struct {
+		scheduling  string
+		expected    *apisLoadTestV1.LoadTestScheduling
+		expectError bool
+	}{
+		{
+			scheduling: `{"nodeSelector":{"pool":"spot"},"priorityClassName":"loadtest"}`,
+			expected: &apisLoadTestV1.LoadTestScheduling{
+				NodeSelector:      map[string]string{"pool": "spot"},
+				PriorityClassName: "loadtest",
+			},
+		},
+		{
+			scheduling:  `{"nodeSelectors":{"pool":"spot"}}`,
+			expectError: true,
+		},
+		{
+			scheduling:  "pool:spot",
+			expectError: true,
+		},
+		{
+			scheduling: "",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		require.NoError(t, err)
+
+		req.Form = url.Values{"scheduling": []string{scenario.scheduling}}
+
+		actual, err := getScheduling(req)
+
+		if scenario.expectError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, scenario.expected, actual)
+	}
+}
+
+func TestGetCallbacks(t *testing.T) {
+	req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+	require.NoError(t, err)
+	assert.Nil(t, getCallbacks(req))
+
+	req.Form = url.Values{"callbacks": []string{"https://ci.example.com/hooks/kangal", " ", "https://chat.example.com/notify "}}
+	assert.Equal(t, []string{"https://ci.example.com/hooks/kangal", "https://chat.example.com/notify"}, getCallbacks(req))
+}
+
 func TestGetTargetURL(t *testing.T) {
 	for _, ti := range []struct {
 		tag         string
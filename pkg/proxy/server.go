@@ -16,7 +16,6 @@ import (
 	"github.com/hellofresh/kangal/pkg/backends"
 	cHttp "github.com/hellofresh/kangal/pkg/core/http"
 	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
-	kube "github.com/hellofresh/kangal/pkg/kubernetes"
 	"github.com/hellofresh/kangal/pkg/report"
 	otelPrometheus "go.opentelemetry.io/otel/exporters/prometheus"
 )
@@ -24,7 +23,8 @@ import (
 // Runner encapsulates all Kangal Proxy API server dependencies
 type Runner struct {
 	Exporter      *otelPrometheus.Exporter
-	KubeClient    *kube.Client
+	Clusters      Clusters
+	ReportStore   report.ReportStore
 	Logger        *zap.Logger
 	StatsReporter *MetricsReporter
 }
@@ -33,9 +33,10 @@ type Runner struct {
 func RunServer(cfg Config, rr Runner) error {
 	registry := backends.New(
 		backends.WithLogger(rr.Logger),
+		backends.WithSchedulingPolicy(cfg.Scheduling),
 	)
 
-	proxyHandler := NewProxy(cfg.MaxLoadTestsRun, registry, rr.KubeClient, cfg.MaxListLimit, cfg.AllowedCustomImages)
+	proxyHandler := NewClustersProxy(rr.Clusters, registry, cfg.MaxListLimit, cfg.AllowedCustomImages, cfg.MaxTTLAfterFinished, cfg.CallbackSecret)
 
 	// Start instrumented server
 	r := chi.NewRouter()
@@ -93,8 +94,21 @@ func RunServer(cfg Config, rr Runner) error {
 		url := fmt.Sprintf("%s/", r.URL.Host+r.URL.Path)
 		http.Redirect(w, r, url, http.StatusMovedPermanently)
 	})
-	r.Get("/load-test/{id}/report/*", report.ShowHandler())
-	r.Put("/load-test/{id}/report", report.PersistHandler(rr.KubeClient, rr.Logger))
+	// files and archive are registered ahead of the report catch-all, chi matches static segments first
+	reportIndexes := report.NewArchiveIndexCache()
+	r.Get("/load-test/{id}/report/files", report.FilesHandler(rr.ReportStore, reportIndexes))
+	archiveHandler := report.ArchiveHandler(rr.ReportStore)
+	r.Get("/load-test/{id}/report/archive", archiveHandler)
+	r.Head("/load-test/{id}/report/archive", archiveHandler)
+	r.Get("/load-test/{id}/report/*", report.ShowHandler(rr.ReportStore, reportIndexes))
+	r.Put("/load-test/{id}/report", report.PersistHandler(rr.ReportStore, rr.Clusters, rr.Logger))
+	r.Get("/load-test/{id}/summary", report.SummaryHandler(rr.ReportStore))
+	r.Get("/compare", report.CompareHandler(rr.ReportStore, cfg.Compare))
+
+	// ---------------------------------------------------------------------- //
+	// LoadTest history
+	// ---------------------------------------------------------------------- //
+	r.Get("/history", report.HistoryHandler(report.NewHistory(rr.ReportStore), int(cfg.MaxListLimit)))
 
 	address := fmt.Sprintf(":%d", cfg.HTTPPort)
 	rr.Logger.Info("Running HTTP server...", zap.String("address", address))
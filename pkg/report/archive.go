@@ -0,0 +1,48 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"strings"
+)
+
+// ArchivePrefix is the bucket prefix under which loadtest artifacts are archived before the loadtest is deleted
+const ArchivePrefix = "archive"
+
+// internalPrefixes are the bucket prefixes of objects stored next to the reports, they are never served as reports
+var internalPrefixes = []string{ArchivePrefix, HistoryPrefix, SummaryPrefix, BaselinePrefix}
+
+// IsReservedName returns true when the report name points outside of the loadtest reports, e.g. to archived loadtest logs
+func IsReservedName(name string) bool {
+	first, _, _ := strings.Cut(strings.TrimPrefix(path.Clean(pathSeparator+name), pathSeparator), pathSeparator)
+	if first == "" {
+		return true
+	}
+	for _, prefix := range internalPrefixes {
+		if first == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// Archiver stores loadtest artifacts in the report store
+type Archiver struct {
+	store ReportStore
+}
+
+// NewArchiver returns an archiver using the report store
+func NewArchiver(store ReportStore) *Archiver {
+	return &Archiver{store: store}
+}
+
+// Archive uploads given content as a file of the loadtest archive
+func (a *Archiver) Archive(ctx context.Context, loadTestName, fileName string, content []byte, contentType string) error {
+	return a.store.Put(ctx, ArchiveObjectName(loadTestName, fileName), bytes.NewReader(content), int64(len(content)), contentType)
+}
+
+// ArchiveObjectName returns the bucket object name of an archived loadtest file
+func ArchiveObjectName(loadTestName, fileName string) string {
+	return path.Join(ArchivePrefix, loadTestName, fileName)
+}
@@ -0,0 +1,416 @@
+package report
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// defaultArchiveIndexCacheSize is the number of report indexes kept in memory by a ShowHandler
+	defaultArchiveIndexCacheSize = 128
+	// defaultArchiveIndexCacheBytes is the total size of decompressed reports kept in memory by a ShowHandler,
+	// it is not less than maxDecompressedSize so every decompressed report can be cached
+	defaultArchiveIndexCacheBytes = 2 * maxDecompressedSize
+)
+
+// archiveEntry is a regular file of an archive
+type archiveEntry struct {
+	offset         int64 // offset of the file content in the archive
+	size           int64
+	compressedSize int64
+	method         uint16 // zip compression method, zip.Store for tar entries
+	modTime        time.Time
+}
+
+// archiveIndex maps the files of an archive to their content offsets, so they are read without extracting the archive
+type archiveIndex struct {
+	etag    string
+	files   map[string]archiveEntry
+	dirs    map[string]bool
+	modTime time.Time
+	// data is the decompressed content of gzip and zstd reports, entries are read from it instead of the stored object
+	data []byte
+	// single is true when data is a compressed file, not an archive
+	single bool
+}
+
+func newArchiveIndex(info ObjectInfo) *archiveIndex {
+	return &archiveIndex{
+		etag:    info.ETag,
+		files:   map[string]archiveEntry{},
+		dirs:    map[string]bool{"": true},
+		modTime: info.LastModified,
+	}
+}
+
+// buildArchiveIndex indexes the report archive of the given content type
+func buildArchiveIndex(contentType string, r io.ReadSeeker, size int64, info ObjectInfo) (*archiveIndex, error) {
+	switch contentType {
+	case contentTypeTar:
+		return buildTarIndex(r, info)
+	case contentTypeZip:
+		return buildZipIndex(readerAt(r), size, info)
+	}
+
+	data, err := decompress(contentType, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if sniffArchiveContentType(data[:min(len(data), sniffLen)]) != contentTypeTar {
+		index := newArchiveIndex(info)
+		index.data, index.single = data, true
+		return index, nil
+	}
+
+	index, err := buildTarIndex(bytes.NewReader(data), info)
+	if err != nil {
+		return nil, err
+	}
+	index.data = data
+	return index, nil
+}
+
+// positionReader tracks the position of the archive reader, tar.Reader seeks over the file contents
+type positionReader struct {
+	r   io.ReadSeeker
+	pos int64
+}
+
+func (p *positionReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.pos += int64(n)
+	return n, err
+}
+
+func (p *positionReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := p.r.Seek(offset, whence)
+	if err == nil {
+		p.pos = pos
+	}
+	return pos, err
+}
+
+// buildTarIndex reads the tar headers of the archive
+func buildTarIndex(r io.ReadSeeker, info ObjectInfo) (*archiveIndex, error) {
+	index := newArchiveIndex(info)
+
+	pr := &positionReader{r: r}
+	tr := tar.NewReader(pr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return index, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read report archive: %w", err)
+		}
+
+		name := archiveEntryName(header.Name)
+		if name == "" {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			index.addDir(name)
+		case tar.TypeReg:
+			index.files[name] = archiveEntry{offset: pr.pos, size: header.Size, compressedSize: header.Size, method: zip.Store, modTime: header.ModTime}
+			index.addDir(path.Dir(name))
+		}
+	}
+}
+
+// buildZipIndex reads the central directory of the zip archive
+func buildZipIndex(r io.ReaderAt, size int64, info ObjectInfo) (*archiveIndex, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("could not read report archive: %w", err)
+	}
+
+	index := newArchiveIndex(info)
+	for _, file := range zr.File {
+		name := archiveEntryName(file.Name)
+		if name == "" {
+			continue
+		}
+		if file.FileInfo().IsDir() {
+			index.addDir(name)
+			continue
+		}
+		if file.Method != zip.Store && file.Method != zip.Deflate {
+			return nil, fmt.Errorf("%s: %w", file.Name, zip.ErrAlgorithm)
+		}
+		// entries are limited as decompressed reports are, deflated ones are inflated in memory when they are served
+		if file.UncompressedSize64 > maxDecompressedSize {
+			return nil, fmt.Errorf("%s: %w", file.Name, ErrReportTooLarge)
+		}
+
+		offset, err := file.DataOffset()
+		if err != nil {
+			return nil, fmt.Errorf("could not read report archive: %w", err)
+		}
+		index.files[name] = archiveEntry{
+			offset:         offset,
+			size:           int64(file.UncompressedSize64),
+			compressedSize: int64(file.CompressedSize64),
+			method:         file.Method,
+			modTime:        file.Modified,
+		}
+		index.addDir(path.Dir(name))
+	}
+
+	return index, nil
+}
+
+// archiveEntryName returns the archive entry name relative to the archive root, e.g. ./content/js/dashboard.js is content/js/dashboard.js
+func archiveEntryName(name string) string {
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
+
+func (i *archiveIndex) addDir(name string) {
+	for name != "." && name != "" && !i.dirs[name] {
+		i.dirs[name] = true
+		name = path.Dir(name)
+	}
+}
+
+// entryETag returns the ETag of the file served for the archive path, empty when there is no such file
+func (i *archiveIndex) entryETag(name string) string {
+	name = archiveEntryName(name)
+	entry, ok := i.files[name]
+	if !ok && i.dirs[name] {
+		entry, ok = i.files[path.Join(name, "index.html")]
+	}
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf(`"%s-%x"`, strings.Trim(i.etag, `"`), entry.offset)
+}
+
+// archiveFileSystem serves the files of an archive as a http.FileSystem
+type archiveFileSystem struct {
+	index *archiveIndex
+	r     io.ReaderAt
+}
+
+func newArchiveFileSystem(index *archiveIndex, r io.ReadSeeker) *archiveFileSystem {
+	if index.data != nil {
+		return &archiveFileSystem{index: index, r: bytes.NewReader(index.data)}
+	}
+	return &archiveFileSystem{index: index, r: readerAt(r)}
+}
+
+// Open implements http.FileSystem
+func (a *archiveFileSystem) Open(name string) (http.File, error) {
+	name = archiveEntryName(name)
+
+	if entry, ok := a.index.files[name]; ok {
+		content, err := a.open(entry)
+		if err != nil {
+			return nil, err
+		}
+		return &storeFile{
+			ReadSeekCloser: nopSeekCloser{content},
+			info:           objectFileInfo{name: path.Base(name), size: entry.size, modTime: a.modTime(entry)},
+		}, nil
+	}
+
+	if !a.index.dirs[name] {
+		return nil, os.ErrNotExist
+	}
+
+	var entries []fs.FileInfo
+	for file, entry := range a.index.files {
+		if path.Dir(file) == name || (name == "" && !strings.Contains(file, "/")) {
+			entries = append(entries, objectFileInfo{name: path.Base(file), size: entry.size, modTime: a.modTime(entry)})
+		}
+	}
+	for dir := range a.index.dirs {
+		if dir != "" && (path.Dir(dir) == name || (name == "" && !strings.Contains(dir, "/"))) {
+			entries = append(entries, objectFileInfo{name: path.Base(dir), isDir: true, modTime: a.index.modTime})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return &storeFile{
+		info:    objectFileInfo{name: path.Base("/" + name), isDir: true, modTime: a.index.modTime},
+		entries: entries,
+	}, nil
+}
+
+// open returns the entry content, stored entries are read from their offsets and deflated entries are inflated in memory
+func (a *archiveFileSystem) open(entry archiveEntry) (io.ReadSeeker, error) {
+	section := io.NewSectionReader(a.r, entry.offset, entry.compressedSize)
+	if entry.method == zip.Store {
+		return section, nil
+	}
+
+	fr := flate.NewReader(section)
+	defer fr.Close()
+
+	content, err := io.ReadAll(io.LimitReader(fr, min(entry.size, maxDecompressedSize)))
+	if err != nil {
+		return nil, fmt.Errorf("could not read report archive: %w", err)
+	}
+	return bytes.NewReader(content), nil
+}
+
+// modTime returns the archive modification time for entries archived without it
+func (a *archiveFileSystem) modTime(entry archiveEntry) time.Time {
+	if entry.modTime.IsZero() {
+		return a.index.modTime
+	}
+	return entry.modTime
+}
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+// seekerReaderAt reads at offsets of readers which do not implement io.ReaderAt
+type seekerReaderAt struct {
+	mu sync.Mutex
+	r  io.ReadSeeker
+}
+
+func (s *seekerReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(s.r, p)
+}
+
+func readerAt(r io.ReadSeeker) io.ReaderAt {
+	if ra, ok := r.(io.ReaderAt); ok {
+		return ra
+	}
+	return &seekerReaderAt{r: r}
+}
+
+// ArchiveIndexCache keeps the indexes of the most recently viewed reports, bounded by the number of reports
+// and by the total size of decompressed reports. Indexes are keyed by the loadtest name, so a cache is shared
+// only by handlers of the same store
+type ArchiveIndexCache struct {
+	mu       sync.Mutex
+	size     int
+	maxBytes int
+	bytes    int
+	entries  *list.List
+	items    map[string]*list.Element
+	// builds shares an index being built between concurrent requests of the same report
+	builds singleflight.Group
+}
+
+type archiveIndexCacheItem struct {
+	key   string
+	index *archiveIndex
+}
+
+// NewArchiveIndexCache returns the cache of report archive indexes with the default bounds
+func NewArchiveIndexCache() *ArchiveIndexCache {
+	return newArchiveIndexCache(defaultArchiveIndexCacheSize, defaultArchiveIndexCacheBytes)
+}
+
+func newArchiveIndexCache(size, maxBytes int) *ArchiveIndexCache {
+	return &ArchiveIndexCache{
+		size:     size,
+		maxBytes: maxBytes,
+		entries:  list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+// get returns the cached index of the report, nil when it is not cached or the report has changed
+func (c *ArchiveIndexCache) get(key, etag string) *archiveIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	index := element.Value.(*archiveIndexCacheItem).index
+	if index.etag != etag {
+		c.remove(element)
+		return nil
+	}
+
+	c.entries.MoveToFront(element)
+	return index
+}
+
+// add caches the report index, removing the least recently used ones when the cache is full.
+// Indexes with decompressed reports larger than the cache are not cached
+func (c *ArchiveIndexCache) add(key string, index *archiveIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+	if len(index.data) > c.maxBytes {
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&archiveIndexCacheItem{key: key, index: index})
+	c.bytes += len(index.data)
+	for c.entries.Len() > c.size || c.bytes > c.maxBytes {
+		c.remove(c.entries.Back())
+	}
+}
+
+func (c *ArchiveIndexCache) remove(element *list.Element) {
+	item := c.entries.Remove(element).(*archiveIndexCacheItem)
+	delete(c.items, item.key)
+	c.bytes -= len(item.index.data)
+}
+
+// index returns the cached index of the report archive or builds it. Concurrent requests of a report not cached yet
+// wait for a single build, indexes do not depend on the reader they are built from
+func (c *ArchiveIndexCache) index(key, contentType string, r io.ReadSeeker, info ObjectInfo) (*archiveIndex, error) {
+	if index := c.get(key, info.ETag); index != nil {
+		return index, nil
+	}
+
+	index, err, _ := c.builds.Do(key+"\x00"+info.ETag, func() (interface{}, error) {
+		if index := c.get(key, info.ETag); index != nil {
+			return index, nil
+		}
+
+		index, err := buildArchiveIndex(contentType, r, info.Size, info)
+		if err != nil {
+			return nil, err
+		}
+		c.add(key, index)
+		return index, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return index.(*archiveIndex), nil
+}
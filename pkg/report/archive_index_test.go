@@ -0,0 +1,333 @@
+package report
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTarIndex(t *testing.T) {
+	longName := "content/" + strings.Repeat("long-directory-name/", 8) + "file.js"
+	archive := newTestTar(t, map[string]string{
+		"./index.html":           "<html>index</html>",
+		"./content/css/app.css":  "body{}",
+		"./statistics.json":      `{"Total":{}}`,
+		longName:                 "console.log()",
+		"../../escaped/file.txt": "escaped",
+	})
+
+	index, err := buildTarIndex(bytes.NewReader(archive), ObjectInfo{ETag: "etag", LastModified: time.Unix(1700000000, 0)})
+	require.NoError(t, err)
+
+	assert.Len(t, index.files, 5)
+	assert.Contains(t, index.files, "escaped/file.txt")
+	assert.True(t, index.dirs["content/css"])
+	assert.True(t, index.dirs["content"])
+
+	fs := newArchiveFileSystem(index, bytes.NewReader(archive))
+	for name, expected := range map[string]string{
+		"/index.html":          "<html>index</html>",
+		"/content/css/app.css": "body{}",
+		"/" + longName:         "console.log()",
+	} {
+		f, err := fs.Open(name)
+		require.NoError(t, err, name)
+
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+		require.NoError(t, f.Close())
+	}
+
+	dir, err := fs.Open("/content")
+	require.NoError(t, err)
+	entries, err := dir.Readdir(-1)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "css", entries[0].Name())
+	assert.True(t, entries[0].IsDir())
+
+	_, err = fs.Open("/missing.html")
+	assert.Error(t, err)
+
+	assert.Equal(t, index.entryETag("index.html"), index.entryETag(""))
+	assert.NotEqual(t, index.entryETag("index.html"), index.entryETag("content/css/app.css"))
+	assert.Empty(t, index.entryETag("missing.html"))
+}
+
+func TestBuildTarIndexInvalidArchive(t *testing.T) {
+	archive := newTestTar(t, map[string]string{"index.html": strings.Repeat("a", 1024)})
+
+	_, err := buildTarIndex(bytes.NewReader(archive[:700]), ObjectInfo{})
+	assert.Error(t, err)
+}
+
+func TestBuildZipIndex(t *testing.T) {
+	archive := newTestZip(t, map[string]string{
+		"index.html":        "<html>index</html>",
+		"content/js/app.js": strings.Repeat("console.log();", 100),
+	}, zip.Deflate)
+
+	index, err := buildZipIndex(bytes.NewReader(archive), int64(len(archive)), ObjectInfo{ETag: "etag"})
+	require.NoError(t, err)
+	assert.Len(t, index.files, 2)
+	assert.True(t, index.dirs["content/js"])
+	assert.Less(t, index.files["content/js/app.js"].compressedSize, index.files["content/js/app.js"].size)
+
+	f, err := newArchiveFileSystem(index, bytes.NewReader(archive)).Open("/content/js/app.js")
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("console.log();", 100), string(content))
+}
+
+func TestBuildZipIndexTooLargeEntry(t *testing.T) {
+	var compressed bytes.Buffer
+	fw, err := flate.NewWriter(&compressed, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("<html>index</html>"))
+	require.NoError(t, err)
+	require.NoError(t, fw.Close())
+
+	// entry declares more than maxDecompressedSize, its content is not inflated to check it
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	w, err := writer.CreateRaw(&zip.FileHeader{
+		Name:               "index.html",
+		Method:             zip.Deflate,
+		CompressedSize64:   uint64(compressed.Len()),
+		UncompressedSize64: maxDecompressedSize + 1,
+	})
+	require.NoError(t, err)
+	_, err = w.Write(compressed.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	_, err = buildZipIndex(bytes.NewReader(archive.Bytes()), int64(archive.Len()), ObjectInfo{})
+	assert.ErrorIs(t, err, ErrReportTooLarge)
+}
+
+func TestArchiveIndexCache(t *testing.T) {
+	cache := newArchiveIndexCache(2, 10)
+
+	cache.add("first", &archiveIndex{etag: "1"})
+	cache.add("second", &archiveIndex{etag: "2"})
+	require.NotNil(t, cache.get("first", "1"))
+
+	// second is the least recently used
+	cache.add("third", &archiveIndex{etag: "3"})
+	assert.Nil(t, cache.get("second", "2"))
+	assert.NotNil(t, cache.get("first", "1"))
+	assert.NotNil(t, cache.get("third", "3"))
+
+	// report was uploaded again
+	assert.Nil(t, cache.get("first", "changed"))
+	assert.Nil(t, cache.get("first", "1"))
+
+	// decompressed reports are bounded by size
+	cache.add("large", &archiveIndex{etag: "4", data: make([]byte, 11)})
+	assert.Nil(t, cache.get("large", "4"))
+	cache.add("fourth", &archiveIndex{etag: "5", data: make([]byte, 6)})
+	cache.add("fifth", &archiveIndex{etag: "6", data: make([]byte, 6)})
+	assert.Nil(t, cache.get("fourth", "5"))
+	assert.NotNil(t, cache.get("fifth", "6"))
+}
+
+// countingReader counts the reads of the report, it blocks the first read until released
+type countingReader struct {
+	io.ReadSeeker
+	reads   *atomic.Int32
+	release <-chan struct{}
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads.Add(1)
+	<-r.release
+	return r.ReadSeeker.Read(p)
+}
+
+func TestArchiveIndexCacheSingleBuild(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write(newTestTar(t, map[string]string{"index.html": "<html>index</html>"}))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	cache := newArchiveIndexCache(defaultArchiveIndexCacheSize, defaultArchiveIndexCacheBytes)
+	info := ObjectInfo{Key: "loadtest-name", Size: int64(compressed.Len()), ETag: "1"}
+
+	var (
+		wg      sync.WaitGroup
+		reads   = make([]atomic.Int32, 10)
+		release = make(chan struct{})
+		indexes = make([]*archiveIndex, len(reads))
+	)
+	for i := range reads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := &countingReader{ReadSeeker: bytes.NewReader(compressed.Bytes()), reads: &reads[i], release: release}
+			index, err := cache.index("loadtest-name", contentTypeGzip, r, info)
+			assert.NoError(t, err)
+			indexes[i] = index
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// report is decompressed once and every request gets the same index
+	readers := 0
+	for i := range reads {
+		if reads[i].Load() > 0 {
+			readers++
+		}
+		assert.Same(t, indexes[0], indexes[i])
+	}
+	assert.Equal(t, 1, readers)
+	assert.Contains(t, indexes[0].files, "index.html")
+}
+
+func TestShowHandlerTarEntries(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for name, content := range map[string]string{"index.html": "<html>index</html>", "content/js/app.js": "0123456789"} {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), ModTime: modTime, Typeflag: tar.TypeReg}))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, store.Put(ctx, "loadtest-name", &archive, -1, "application/x-tar"))
+
+	handler := chi.NewRouter()
+	handler.Get("/load-test/{id}/report/*", ShowHandler(store, NewArchiveIndexCache()))
+
+	serve := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve("/load-test/loadtest-name/report/content/js/app.js", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "0123456789", rr.Body.String())
+	assert.Equal(t, modTime.Format(http.TimeFormat), rr.Header().Get("Last-Modified"))
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rr = serve("/load-test/loadtest-name/report/content/js/app.js", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	rr = serve("/load-test/loadtest-name/report/content/js/app.js", map[string]string{"Range": "bytes=2-4"})
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "234", rr.Body.String())
+
+	rr = serve("/load-test/loadtest-name/report/", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "<html>index</html>", rr.Body.String())
+
+	rr = serve("/load-test/loadtest-name/report/missing.js", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func newTestZip(t *testing.T, files map[string]string, method uint16) []byte {
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, content := range files {
+		w, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	return archive.Bytes()
+}
+
+func TestShowHandlerCompressedReports(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	files := map[string]string{"./index.html": "<html>index</html>", "./content/js/app.js": "0123456789"}
+	tarball := newTestTar(t, files)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, err = gw.Write(tarball)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	zw, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zst := zw.EncodeAll(tarball, nil)
+	require.NoError(t, zw.Close())
+
+	var single bytes.Buffer
+	gw = gzip.NewWriter(&single)
+	_, err = gw.Write([]byte("<html>single</html>"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	// file system store detects the content type of the reports
+	for name, content := range map[string][]byte{
+		"tar-gz":      gz.Bytes(),
+		"tar-zst":     zst,
+		"zip-stored":  newTestZip(t, files, zip.Store),
+		"zip-deflate": newTestZip(t, files, zip.Deflate),
+		"single-gz":   single.Bytes(),
+	} {
+		require.NoError(t, store.Put(ctx, name, bytes.NewReader(content), -1, ""))
+	}
+
+	handler := chi.NewRouter()
+	handler.Get("/load-test/{id}/report/*", ShowHandler(store, NewArchiveIndexCache()))
+
+	for _, tt := range []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"/load-test/tar-gz/report/", http.StatusOK, "<html>index</html>"},
+		{"/load-test/tar-gz/report/content/js/app.js", http.StatusOK, "0123456789"},
+		{"/load-test/tar-zst/report/content/js/app.js", http.StatusOK, "0123456789"},
+		{"/load-test/zip-stored/report/", http.StatusOK, "<html>index</html>"},
+		{"/load-test/zip-deflate/report/content/js/app.js", http.StatusOK, "0123456789"},
+		{"/load-test/zip-deflate/report/missing.js", http.StatusNotFound, ""},
+		{"/load-test/single-gz/report/", http.StatusOK, "<html>single</html>"},
+		{"/load-test/single-gz/report/index.html", http.StatusNotFound, ""},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
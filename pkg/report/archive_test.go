@@ -0,0 +1,55 @@
+package report
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiver(t *testing.T) {
+	var (
+		uploadedPath        string
+		uploadedContent     string
+		uploadedContentType string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploadedPath = r.URL.Path
+		uploadedContent = string(body)
+		uploadedContentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store, err := NewMinioStore(newTestMinioConfig(srv.URL))
+	require.NoError(t, err)
+
+	err = NewArchiver(store).Archive(context.Background(), "loadtest-name", "metadata.json", []byte(`{"name":"loadtest-name"}`), "application/json")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/some-bucket/archive/loadtest-name/metadata.json", uploadedPath)
+	assert.Contains(t, uploadedContent, `{"name":"loadtest-name"}`)
+	assert.Equal(t, "application/json", uploadedContentType)
+}
+
+func TestIsReservedName(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"loadtest-name":            false,
+		"loadtest-name/index.html": false,
+		"archive":                  true,
+		"history":                  true,
+		"summary":                  true,
+		"baseline":                 true,
+		"/archive/loadtest-name":   true,
+		"../archive":               true,
+		"":                         true,
+		"/":                        true,
+	} {
+		assert.Equal(t, expected, IsReservedName(name), name)
+	}
+}
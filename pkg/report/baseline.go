@@ -0,0 +1,145 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+const (
+	// BaselinePrefix is the bucket prefix under which comparisons of loadtest results against their baselines are stored
+	BaselinePrefix = "baseline"
+	// baselineLookupPageSize is the number of history records read at once while looking up the baseline
+	baselineLookupPageSize = 20
+)
+
+var (
+	// ErrBaselineNotFound is returned when there is no previous passing loadtest with the same type, tags and results summary
+	ErrBaselineNotFound = errors.New("baseline not found, there is no previous passing load test with the same type, tags and results summary")
+	// ErrBaselineUntagged is returned when the loadtest has no tags, tags select the baseline
+	ErrBaselineUntagged = errors.New("load tests without tags are not compared to baseline, tags select the baseline")
+)
+
+// BaselineObjectName returns the bucket object name of the loadtest comparison against its baseline
+func BaselineObjectName(loadTestName string) string {
+	return path.Join(BaselinePrefix, loadTestName+".json")
+}
+
+// Baselines compares loadtest results against the results of the most recent passing loadtest with the same type and tags
+type Baselines struct {
+	store      ReportStore
+	history    *History
+	tolerances results.Tolerances
+}
+
+// NewBaselines returns baselines using the history catalog of the report store
+func NewBaselines(store ReportStore, tolerances results.Tolerances) *Baselines {
+	return &Baselines{store: store, history: NewHistory(store), tolerances: tolerances}
+}
+
+// Compare compares the loadtest results against its baseline and stores the comparison, so it is returned
+// with the results summary. ErrSummaryNotFound is returned when the loadtest results were not summarized yet
+// and ErrBaselineUntagged when the loadtest has no tags
+func (b *Baselines) Compare(ctx context.Context, loadTestName string, loadTestType loadTestV1.LoadTestType, tags loadTestV1.LoadTestTags) (CompareResult, error) {
+	if len(tags) == 0 {
+		return CompareResult{}, ErrBaselineUntagged
+	}
+
+	candidate, err := getSummary(ctx, b.store, loadTestName)
+	if err != nil {
+		return CompareResult{}, err
+	}
+
+	// most recent records are read first, the lookup stops at the first baseline
+	opts := HistoryListOptions{Limit: baselineLookupPageSize}
+	for {
+		page, err := b.history.List(ctx, HistoryFilter{Type: loadTestType, Tags: tags}, opts)
+		if err != nil {
+			return CompareResult{}, fmt.Errorf("could not list history: %w", err)
+		}
+
+		for _, record := range page.Items {
+			if record.Name == loadTestName || !isBaseline(record, tags) {
+				continue
+			}
+
+			base, err := getSummary(ctx, b.store, record.Name)
+			if errors.Is(err, ErrSummaryNotFound) {
+				continue
+			}
+			if err != nil {
+				return CompareResult{}, err
+			}
+
+			return b.storeComparison(ctx, CompareResult{
+				Base:       record.Name,
+				Candidate:  loadTestName,
+				Tolerances: b.tolerances,
+				Comparison: results.Compare(base, candidate, b.tolerances),
+			})
+		}
+
+		if page.Continue == "" {
+			return CompareResult{}, ErrBaselineNotFound
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// storeComparison stores the comparison, so it is returned with the results summary
+func (b *Baselines) storeComparison(ctx context.Context, result CompareResult) (CompareResult, error) {
+	content, err := json.Marshal(result)
+	if err != nil {
+		return CompareResult{}, err
+	}
+	err = b.store.Put(ctx, BaselineObjectName(result.Candidate), bytes.NewReader(content), int64(len(content)), "application/json")
+	if err != nil {
+		return CompareResult{}, fmt.Errorf("could not store comparison: %w", err)
+	}
+	return result, nil
+}
+
+// isBaseline returns true when the recorded loadtest of the same type has the same tag set, has finished and neither failed
+// its thresholds nor regressed, so a regression does not become the baseline of the next loadtests
+func isBaseline(record HistoryRecord, tags loadTestV1.LoadTestTags) bool {
+	// history filter matched all the tags, the same number of tags means the same tag set
+	if len(record.Tags) != len(tags) || record.Status.Phase != loadTestV1.LoadTestFinished {
+		return false
+	}
+
+	conditions := record.Status.Conditions
+	return !meta.IsStatusConditionFalse(conditions, loadTestV1.LoadTestConditionPassed.String()) &&
+		!meta.IsStatusConditionTrue(conditions, loadTestV1.LoadTestConditionRegressed.String())
+}
+
+// getBaseline returns the stored comparison of the loadtest against its baseline, nil when it was not compared
+func getBaseline(ctx context.Context, store ReportStore, loadTestName string) (*CompareResult, error) {
+	result, _, err := getBaselineObject(ctx, store, loadTestName)
+	return result, err
+}
+
+// getBaselineObject returns the stored comparison with its object info, nil when it was not compared
+func getBaselineObject(ctx context.Context, store ReportStore, loadTestName string) (*CompareResult, ObjectInfo, error) {
+	obj, info, err := store.Get(ctx, BaselineObjectName(loadTestName))
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil, ObjectInfo{}, nil
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	defer obj.Close()
+
+	var result CompareResult
+	if err := json.NewDecoder(obj).Decode(&result); err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("could not read baseline comparison: %w", err)
+	}
+	return &result, info, nil
+}
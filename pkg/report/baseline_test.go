@@ -0,0 +1,103 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+func TestBaselinesCompare(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	tags := loadTestV1.LoadTestTags{"service": "checkout", "scenario": "soak"}
+	now := time.Now()
+	history := NewHistory(store)
+	recordType := func(name string, loadTestType loadTestV1.LoadTestType, tags loadTestV1.LoadTestTags, created time.Duration, phase loadTestV1.LoadTestPhase, conditions ...metaV1.Condition) {
+		require.NoError(t, history.Record(ctx, HistoryRecord{
+			Name:              name,
+			Type:              loadTestType,
+			Tags:              tags,
+			CreationTimestamp: now.Add(-created),
+			Status:            loadTestV1.LoadTestStatus{Phase: phase, Conditions: conditions},
+		}))
+	}
+	record := func(name string, tags loadTestV1.LoadTestTags, created time.Duration, phase loadTestV1.LoadTestPhase, conditions ...metaV1.Condition) {
+		recordType(name, loadTestV1.LoadTestTypeK6, tags, created, phase, conditions...)
+	}
+	summary := func(name string, p95 time.Duration) {
+		content, err := json.Marshal(results.Summary{Stats: results.Stats{Throughput: 100, Latency: results.Latency{P95: p95}}})
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, SummaryObjectName(name), strings.NewReader(string(content)), -1, "application/json"))
+	}
+
+	baselines := NewBaselines(store, results.Tolerances{Latency: 0.1, Throughput: 0.1, ErrorRate: 0.01})
+
+	summary("candidate", 300*time.Millisecond)
+	_, err = baselines.Compare(ctx, "candidate", loadTestV1.LoadTestTypeK6, tags)
+	assert.ErrorIs(t, err, ErrBaselineNotFound)
+
+	record("candidate", tags, 0, loadTestV1.LoadTestFinished)
+	record("passing", tags, 4*time.Hour, loadTestV1.LoadTestFinished,
+		metaV1.Condition{Type: loadTestV1.LoadTestConditionPassed.String(), Status: metaV1.ConditionTrue})
+	summary("passing", 200*time.Millisecond)
+	record("without-summary", tags, 3*time.Hour, loadTestV1.LoadTestFinished)
+	record("failed-thresholds", tags, 2*time.Hour, loadTestV1.LoadTestFinished,
+		metaV1.Condition{Type: loadTestV1.LoadTestConditionPassed.String(), Status: metaV1.ConditionFalse})
+	summary("failed-thresholds", 300*time.Millisecond)
+	record("regressed", tags, 2*time.Hour, loadTestV1.LoadTestFinished,
+		metaV1.Condition{Type: loadTestV1.LoadTestConditionRegressed.String(), Status: metaV1.ConditionTrue})
+	summary("regressed", 300*time.Millisecond)
+	record("errored", tags, time.Hour, loadTestV1.LoadTestErrored)
+	summary("errored", 300*time.Millisecond)
+	record("more-tags", loadTestV1.LoadTestTags{"service": "checkout", "scenario": "soak", "env": "dev"}, time.Hour, loadTestV1.LoadTestFinished)
+	summary("more-tags", 300*time.Millisecond)
+	recordType("other-type", loadTestV1.LoadTestTypeJMeter, tags, time.Hour, loadTestV1.LoadTestFinished)
+	summary("other-type", 300*time.Millisecond)
+
+	result, err := baselines.Compare(ctx, "candidate", loadTestV1.LoadTestTypeK6, tags)
+	require.NoError(t, err)
+	assert.Equal(t, "passing", result.Base)
+	assert.Equal(t, "candidate", result.Candidate)
+	assert.True(t, result.Regressed)
+	assert.True(t, result.Total.P95.Regressed)
+
+	stored, err := getBaseline(ctx, store, "candidate")
+	require.NoError(t, err)
+	assert.Equal(t, &result, stored)
+
+	_, err = baselines.Compare(ctx, "missing", loadTestV1.LoadTestTypeK6, tags)
+	assert.ErrorIs(t, err, ErrSummaryNotFound)
+
+	_, err = baselines.Compare(ctx, "candidate", loadTestV1.LoadTestTypeK6, nil)
+	assert.ErrorIs(t, err, ErrBaselineUntagged)
+
+	t.Run("summary includes comparison", func(t *testing.T) {
+		handler := chi.NewRouter()
+		handler.Get("/load-test/{id}/summary", SummaryHandler(store))
+
+		for name, hasBaseline := range map[string]bool{"candidate": true, "passing": false} {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/load-test/"+name+"/summary", nil))
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			var summary SummaryResult
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summary))
+			assert.Equal(t, 100.0, summary.Throughput)
+			assert.Equal(t, hasBaseline, summary.Baseline != nil, name)
+		}
+	})
+}
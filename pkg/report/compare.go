@@ -0,0 +1,205 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+
+	khttp "github.com/hellofresh/kangal/pkg/core/http"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+// CompareResult is the comparison of the candidate loadtest results against the base loadtest results
+type CompareResult struct {
+	Base       string             `json:"base"`
+	Candidate  string             `json:"candidate"`
+	Tolerances results.Tolerances `json:"tolerances"`
+	results.Comparison
+}
+
+var compareTemplate = template.Must(template.New("compare").Funcs(template.FuncMap{
+	"latency": func(ns float64) string {
+		return time.Duration(ns).Round(10 * time.Microsecond).String()
+	},
+	"percent": func(ratio float64) string {
+		return strconv.FormatFloat(ratio*100, 'f', 2, 64) + "%"
+	},
+	"float": func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 2, 64)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Candidate}} compared to {{.Base}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.regressed { background: #fdd; }
+</style>
+</head>
+<body>
+<h1>{{.Candidate}} compared to {{.Base}}</h1>
+<p>{{if .Regressed}}<strong>Regressed</strong>{{else}}No regressions{{end}},
+tolerances: latency {{percent .Tolerances.Latency}}, throughput {{percent .Tolerances.Throughput}}, error rate {{float .Tolerances.ErrorRate}}</p>
+<table>
+<tr><th>Endpoint</th><th>Throughput, req/s</th><th>Error rate</th><th>p50</th><th>p95</th><th>p99</th></tr>
+{{define "row"}}<td{{if .Throughput.Regressed}} class="regressed"{{end}}>{{float .Throughput.Base}} &rarr; {{float .Throughput.Candidate}} ({{percent .Throughput.Relative}})</td>
+<td{{if .ErrorRate.Regressed}} class="regressed"{{end}}>{{float .ErrorRate.Base}} &rarr; {{float .ErrorRate.Candidate}}</td>
+{{range .Percentiles}}<td{{if .Regressed}} class="regressed"{{end}}>{{latency .Base}} &rarr; {{latency .Candidate}} ({{percent .Relative}})</td>
+{{end}}{{end}}<tr><td><strong>Total</strong></td>{{template "row" .Total}}</tr>
+{{range .Endpoints}}<tr><td>{{.Name}}</td>{{template "row" .}}</tr>
+{{end}}</table>
+{{with .MissingEndpoints}}<p>Missing in {{$.Candidate}}: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}</p>
+{{end}}</body>
+</html>
+`))
+
+// CompareHandler method compares the results summaries of base and candidate loadtests, it renders HTML page
+// when requested with format=html or by a browser and JSON otherwise
+func CompareHandler(store ReportStore, tolerances results.Tolerances) func(w http.ResponseWriter, r *http.Request) {
+	if store == nil {
+		panic("report store was not initialized")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		base, candidate := params.Get("base"), params.Get("candidate")
+		if base == "" || candidate == "" {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, "base and candidate load tests are required"))
+			return
+		}
+
+		requestTolerances, err := tolerancesFromRequest(r, tolerances)
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		summaries := make([]results.Summary, 2)
+		for i, name := range []string{base, candidate} {
+			summaries[i], err = getSummary(r.Context(), store, name)
+			if errors.Is(err, ErrSummaryNotFound) {
+				render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, fmt.Sprintf("%s: %s", name, err)))
+				return
+			}
+			if err != nil {
+				render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+				return
+			}
+		}
+
+		result := CompareResult{
+			Base:       base,
+			Candidate:  candidate,
+			Tolerances: requestTolerances,
+			Comparison: results.Compare(summaries[0], summaries[1], requestTolerances),
+		}
+
+		if !wantsHTML(r) {
+			render.JSON(w, r, result)
+			return
+		}
+
+		var page bytes.Buffer
+		if err := compareTemplate.Execute(&page, compareView(result)); err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		page.WriteTo(w)
+	}
+}
+
+// compareView adds the latency percentiles as a list, so the template renders them in one loop
+func compareView(result CompareResult) interface{} {
+	type statsView struct {
+		results.StatsComparison
+		Name        string
+		Percentiles []results.Delta
+	}
+	newStatsView := func(name string, stats results.StatsComparison) statsView {
+		return statsView{StatsComparison: stats, Name: name, Percentiles: []results.Delta{stats.P50, stats.P95, stats.P99}}
+	}
+
+	endpoints := make([]statsView, 0, len(result.Endpoints))
+	for _, endpoint := range result.Endpoints {
+		endpoints = append(endpoints, newStatsView(endpoint.Name, endpoint.StatsComparison))
+	}
+
+	return struct {
+		CompareResult
+		Total     statsView
+		Endpoints []statsView
+	}{
+		CompareResult: result,
+		Total:         newStatsView("", result.Total),
+		Endpoints:     endpoints,
+	}
+}
+
+func wantsHTML(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "html"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// tolerancesFromRequest overrides the default tolerances with latencyTolerance, throughputTolerance
+// and errorRateTolerance query parameters
+func tolerancesFromRequest(r *http.Request, tolerances results.Tolerances) (results.Tolerances, error) {
+	params := r.URL.Query()
+	for name, into := range map[string]*float64{
+		"latencyTolerance":    &tolerances.Latency,
+		"throughputTolerance": &tolerances.Throughput,
+		"errorRateTolerance":  &tolerances.ErrorRate,
+	} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+
+		tolerance, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return results.Tolerances{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		*into = tolerance
+	}
+
+	return tolerances, tolerances.Validate()
+}
+
+// getSummary returns the stored results summary of the loadtest
+func getSummary(ctx context.Context, store ReportStore, loadTestName string) (results.Summary, error) {
+	summary, _, err := getSummaryObject(ctx, store, loadTestName)
+	return summary, err
+}
+
+// getSummaryObject returns the stored results summary with its object info, ErrSummaryNotFound when it was not stored
+func getSummaryObject(ctx context.Context, store ReportStore, loadTestName string) (results.Summary, ObjectInfo, error) {
+	obj, info, err := store.Get(ctx, SummaryObjectName(loadTestName))
+	if errors.Is(err, ErrObjectNotFound) {
+		return results.Summary{}, ObjectInfo{}, ErrSummaryNotFound
+	}
+	if err != nil {
+		return results.Summary{}, ObjectInfo{}, err
+	}
+	defer obj.Close()
+
+	var summary results.Summary
+	if err := json.NewDecoder(obj).Decode(&summary); err != nil {
+		return results.Summary{}, ObjectInfo{}, fmt.Errorf("could not read summary: %w", err)
+	}
+	return summary, info, nil
+}
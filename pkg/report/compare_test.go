@@ -0,0 +1,86 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+func TestCompareHandler(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	for name, content := range map[string]string{
+		"base":      `{"requests": 100, "errorRate": 0.01, "throughput": 10, "latency": {"p95": 200000000}, "endpoints": [{"name": "GET /", "throughput": 10, "latency": {"p95": 200000000}}, {"name": "POST /login", "throughput": 1}]}`,
+		"candidate": `{"requests": 100, "errorRate": 0.01, "throughput": 10, "latency": {"p95": 300000000}, "endpoints": [{"name": "GET /", "throughput": 10, "latency": {"p95": 300000000}}]}`,
+	} {
+		require.NoError(t, store.Put(ctx, SummaryObjectName(name), strings.NewReader(content), -1, "application/json"))
+	}
+
+	handler := CompareHandler(store, results.Tolerances{Latency: 0.1, Throughput: 0.1, ErrorRate: 0.01})
+
+	t.Run("json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/compare?base=base&candidate=candidate", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result CompareResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, "base", result.Base)
+		assert.Equal(t, "candidate", result.Candidate)
+		assert.True(t, result.Regressed)
+		assert.True(t, result.Total.P95.Regressed)
+		require.Len(t, result.Endpoints, 1)
+		assert.True(t, result.Endpoints[0].P95.Regressed)
+		assert.Equal(t, []string{"POST /login"}, result.MissingEndpoints)
+	})
+
+	t.Run("tolerance from request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/compare?base=base&candidate=candidate&latencyTolerance=0.6", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result CompareResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.False(t, result.Regressed)
+		assert.Equal(t, 0.6, result.Tolerances.Latency)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/compare?base=base&candidate=candidate", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml")
+		handler(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "candidate compared to base")
+		assert.Contains(t, w.Body.String(), "GET /")
+		assert.Contains(t, w.Body.String(), `class="regressed"`)
+		assert.Contains(t, w.Body.String(), "Missing in candidate: POST /login")
+	})
+
+	for name, tc := range map[string]struct {
+		query string
+		code  int
+	}{
+		"missing candidate":  {"base=base", http.StatusBadRequest},
+		"negative tolerance": {"base=base&candidate=candidate&errorRateTolerance=-1", http.StatusBadRequest},
+		"invalid tolerance":  {"base=base&candidate=candidate&throughputTolerance=abc", http.StatusBadRequest},
+		"missing summary":    {"base=base&candidate=missing", http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, "/compare?"+tc.query, nil))
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -2,6 +2,11 @@ package report
 
 // Config is the report package related basic config
 type Config struct {
+	// Storage is the report storage driver, s3 or filesystem
+	Storage string `envconfig:"REPORT_STORAGE" default:"s3"`
+	// StoragePath is the directory the filesystem driver stores reports in
+	StoragePath string `envconfig:"REPORT_STORAGE_PATH" default:"/var/lib/kangal/reports"`
+
 	// S3 compatible configuration access keys and endpoints needed to store load test reports
 	AWSAccessKeyID      string `envconfig:"AWS_ACCESS_KEY_ID" default:""`
 	AWSSecretAccessKey  string `envconfig:"AWS_SECRET_ACCESS_KEY" default:""`
@@ -11,3 +16,8 @@ type Config struct {
 	AWSUseHTTPS         bool   `envconfig:"AWS_USE_HTTPS" default:"false"`
 	AWSPresignedExpires string `envconfig:"AWS_PRESIGNED_EXPIRES" default:""`
 }
+
+// Enabled returns true when the report storage is configured
+func (cfg Config) Enabled() bool {
+	return cfg.Storage == StorageFileSystem || cfg.AWSBucketName != ""
+}
@@ -0,0 +1,259 @@
+package report
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	khttp "github.com/hellofresh/kangal/pkg/core/http"
+)
+
+// archiveExtensions are the file extensions of downloaded report archives
+var archiveExtensions = map[string]string{
+	contentTypeTar:  ".tar",
+	contentTypeGzip: ".gz",
+	contentTypeZstd: ".zst",
+	contentTypeZip:  ".zip",
+}
+
+// ReportFile is a file of the loadtest report
+type ReportFile struct {
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"contentType"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// FilesHandler method returns the list of the report files, archive indexes are cached in the cache shared with ShowHandler
+func FilesHandler(store ReportStore, indexes *ArchiveIndexCache) func(w http.ResponseWriter, r *http.Request) {
+	if store == nil {
+		panic("report store was not initialized")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		loadTestName := chi.URLParam(r, "id")
+		if IsReservedName(loadTestName) {
+			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, ErrReportNotFound.Error()))
+			return
+		}
+
+		files, err := listReportFiles(r.Context(), store, indexes, loadTestName)
+		if errors.Is(err, ErrObjectNotFound) {
+			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, ErrReportNotFound.Error()))
+			return
+		}
+		if nil != err {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		render.JSON(w, r, files)
+	}
+}
+
+// listReportFiles returns the files of the report, reports uploaded as a single static file are listed as the loadtest name
+func listReportFiles(ctx context.Context, store ReportStore, indexes *ArchiveIndexCache, loadTestName string) ([]ReportFile, error) {
+	obj, info, err := store.Get(ctx, loadTestName)
+	if errors.Is(err, ErrObjectNotFound) {
+		return walkReportFiles(&storeFileSystem{ctx: ctx, store: store}, pathSeparator+loadTestName+pathSeparator, "")
+	}
+	if nil != err {
+		return nil, err
+	}
+	defer obj.Close()
+
+	contentType, err := reportContentType(obj, info)
+	if nil != err {
+		return nil, err
+	}
+	if contentType == "" {
+		return []ReportFile{{Path: loadTestName, Size: info.Size, ContentType: info.ContentType, LastModified: info.LastModified}}, nil
+	}
+
+	index, err := indexes.index(loadTestName, contentType, obj, info)
+	if nil != err {
+		return nil, err
+	}
+	if index.single {
+		return []ReportFile{{
+			Path:         loadTestName,
+			Size:         int64(len(index.data)),
+			ContentType:  sniffContentType(index.data[:min(len(index.data), sniffLen)]),
+			LastModified: info.LastModified,
+		}}, nil
+	}
+
+	return walkReportFiles(newArchiveFileSystem(index, obj), pathSeparator, "")
+}
+
+// walkReportFiles lists the files of the directory and its subdirectories using Readdir
+func walkReportFiles(fsys http.FileSystem, dir, prefix string) ([]ReportFile, error) {
+	d, err := fsys.Open(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	if nil != err {
+		return nil, err
+	}
+	entries, err := d.Readdir(-1)
+	d.Close()
+	if nil != err {
+		return nil, err
+	}
+
+	files := make([]ReportFile, 0, len(entries))
+	for _, entry := range entries {
+		name := path.Join(prefix, entry.Name())
+		if entry.IsDir() {
+			children, err := walkReportFiles(fsys, dir+entry.Name()+pathSeparator, name)
+			if nil != err {
+				return nil, err
+			}
+			files = append(files, children...)
+			continue
+		}
+
+		contentType, err := fileContentType(fsys, dir+entry.Name())
+		if nil != err {
+			return nil, err
+		}
+		files = append(files, ReportFile{Path: name, Size: entry.Size(), ContentType: contentType, LastModified: entry.ModTime()})
+	}
+
+	return files, nil
+}
+
+// fileContentType returns the content type of the report file by its extension or by its content
+func fileContentType(fsys http.FileSystem, name string) (string, error) {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType, nil
+	}
+
+	f, err := fsys.Open(name)
+	if nil != err {
+		return "", err
+	}
+	defer f.Close()
+
+	head, err := readHead(f)
+	if nil != err {
+		return "", err
+	}
+	return sniffContentType(head), nil
+}
+
+// ArchiveHandler method downloads the whole report as a single archive. Reports uploaded as archives are downloaded as is,
+// reports uploaded as a static file or as separate objects are downloaded as a tar archive
+func ArchiveHandler(store ReportStore) func(w http.ResponseWriter, r *http.Request) {
+	if store == nil {
+		panic("report store was not initialized")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		loadTestName := chi.URLParam(r, "id")
+		if IsReservedName(loadTestName) {
+			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, ErrReportNotFound.Error()))
+			return
+		}
+
+		obj, info, err := store.Get(ctx, loadTestName)
+		if errors.Is(err, ErrObjectNotFound) {
+			serveObjectsArchive(w, r, store, loadTestName)
+			return
+		}
+		if nil != err {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+		defer obj.Close()
+
+		contentType, err := reportContentType(obj, info)
+		if nil != err {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		// static file report is archived on the fly
+		if contentType == "" {
+			setArchiveHeaders(w, loadTestName, contentTypeTar)
+			if r.Method == http.MethodHead {
+				return
+			}
+			writeReportArchive(w, []ReportFile{{Path: loadTestName, Size: info.Size, LastModified: info.LastModified}}, func(string) (io.ReadCloser, error) {
+				return io.NopCloser(obj), nil
+			})
+			return
+		}
+
+		setArchiveHeaders(w, loadTestName, contentType)
+		if info.ETag != "" {
+			w.Header().Set("ETag", fmt.Sprintf(`"%s"`, strings.Trim(info.ETag, `"`)))
+		}
+		http.ServeContent(w, r, "", info.LastModified, obj)
+	}
+}
+
+// serveObjectsArchive writes the report uploaded as separate objects as a tar archive
+func serveObjectsArchive(w http.ResponseWriter, r *http.Request, store ReportStore, loadTestName string) {
+	ctx := r.Context()
+
+	files, err := walkReportFiles(&storeFileSystem{ctx: ctx, store: store}, pathSeparator+loadTestName+pathSeparator, "")
+	if errors.Is(err, ErrObjectNotFound) {
+		render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, ErrReportNotFound.Error()))
+		return
+	}
+	if nil != err {
+		render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	setArchiveHeaders(w, loadTestName, contentTypeTar)
+	if r.Method == http.MethodHead {
+		return
+	}
+	writeReportArchive(w, files, func(name string) (io.ReadCloser, error) {
+		obj, _, err := store.Get(ctx, path.Join(loadTestName, name))
+		return obj, err
+	})
+}
+
+func setArchiveHeaders(w http.ResponseWriter, loadTestName, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": loadTestName + archiveExtensions[contentType],
+	}))
+}
+
+// writeReportArchive streams the report files as a tar archive, the response status is already sent
+// when a file fails to be read, so the archive is truncated
+func writeReportArchive(w io.Writer, files []ReportFile, open func(name string) (io.ReadCloser, error)) {
+	writer := tar.NewWriter(w)
+	for _, file := range files {
+		f, err := open(file.Path)
+		if nil != err {
+			return
+		}
+
+		err = writer.WriteHeader(&tar.Header{Name: file.Path, Mode: 0644, Size: file.Size, ModTime: file.LastModified, Typeflag: tar.TypeReg})
+		if nil == err {
+			_, err = io.CopyN(writer, f, file.Size)
+		}
+		f.Close()
+		if nil != err {
+			return
+		}
+	}
+	writer.Close()
+}
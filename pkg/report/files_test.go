@@ -0,0 +1,148 @@
+package report
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReportStore(t *testing.T) ReportStore {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "html-report", strings.NewReader("<html>report</html>"), -1, "text/html"))
+	require.NoError(t, store.Put(ctx, "tar-report", bytes.NewReader(newTestTar(t, map[string]string{
+		"./index.html":        "<html>tar</html>",
+		"./content/js/app.js": "console.log()",
+		"./statistics":        `{"Total":{}}`,
+	})), -1, ""))
+	require.NoError(t, store.Put(ctx, "dir-report/index.html", strings.NewReader("<html>dir</html>"), -1, "text/html"))
+	require.NoError(t, store.Put(ctx, "dir-report/content/app.css", strings.NewReader("body{}"), -1, "text/css"))
+
+	return store
+}
+
+func TestFilesHandler(t *testing.T) {
+	store := newTestReportStore(t)
+	indexes := NewArchiveIndexCache()
+	// registered next to the report catch-all like the proxy does
+	handler := chi.NewRouter()
+	handler.Get("/load-test/{id}/report/files", FilesHandler(store, indexes))
+	handler.Get("/load-test/{id}/report/*", ShowHandler(store, indexes))
+
+	for _, tt := range []struct {
+		name           string
+		expectedStatus int
+		expectedFiles  map[string]string
+	}{
+		{"html-report", http.StatusOK, map[string]string{"html-report": "text/html; charset=utf-8"}},
+		{"tar-report", http.StatusOK, map[string]string{
+			"index.html":        "text/html; charset=utf-8",
+			"content/js/app.js": "text/javascript; charset=utf-8",
+			"statistics":        "text/plain; charset=utf-8",
+		}},
+		{"dir-report", http.StatusOK, map[string]string{
+			"index.html":      "text/html; charset=utf-8",
+			"content/app.css": "text/css; charset=utf-8",
+		}},
+		{"missing-report", http.StatusNotFound, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/load-test/"+tt.name+"/report/files", nil))
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedFiles == nil {
+				return
+			}
+
+			var files []ReportFile
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &files))
+
+			actual := map[string]string{}
+			for _, file := range files {
+				actual[file.Path] = file.ContentType
+				assert.NotZero(t, file.Size, file.Path)
+			}
+			assert.Equal(t, tt.expectedFiles, actual)
+		})
+	}
+}
+
+func readTestTar(t *testing.T, archive []byte) map[string]string {
+	files := map[string]string{}
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+}
+
+func TestArchiveHandler(t *testing.T) {
+	store := newTestReportStore(t)
+	archiveHandler := ArchiveHandler(store)
+	handler := chi.NewRouter()
+	handler.Get("/load-test/{id}/report/archive", archiveHandler)
+	handler.Head("/load-test/{id}/report/archive", archiveHandler)
+	handler.Get("/load-test/{id}/report/*", ShowHandler(store, NewArchiveIndexCache()))
+
+	serve := func(method, name string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, "/load-test/"+name+"/report/archive", nil))
+		return rr
+	}
+
+	t.Run("archive is downloaded as is", func(t *testing.T) {
+		rr := serve(http.MethodGet, "tar-report")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-tar", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=tar-report.tar`, rr.Header().Get("Content-Disposition"))
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+		assert.Equal(t, "<html>tar</html>", readTestTar(t, rr.Body.Bytes())["./index.html"])
+	})
+
+	t.Run("separate objects are archived", func(t *testing.T) {
+		rr := serve(http.MethodGet, "dir-report")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, map[string]string{"index.html": "<html>dir</html>", "content/app.css": "body{}"}, readTestTar(t, rr.Body.Bytes()))
+	})
+
+	t.Run("static file is archived", func(t *testing.T) {
+		rr := serve(http.MethodGet, "html-report")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, map[string]string{"html-report": "<html>report</html>"}, readTestTar(t, rr.Body.Bytes()))
+	})
+
+	t.Run("head", func(t *testing.T) {
+		rr := serve(http.MethodHead, "dir-report")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-tar", rr.Header().Get("Content-Type"))
+		assert.Empty(t, rr.Body.String())
+
+		rr = serve(http.MethodHead, "tar-report")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEmpty(t, rr.Header().Get("Content-Length"))
+		assert.Empty(t, rr.Body.String())
+
+		rr = serve(http.MethodHead, "missing-report")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,192 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// tempFilePrefix is the prefix of files being written, they are not listed
+const tempFilePrefix = ".tmp-"
+
+// FileSystemStore stores reports in a local directory, e.g. a PVC mounted to the proxy and the controller
+type FileSystemStore struct {
+	root string
+}
+
+// NewFileSystemStore returns a store keeping objects in files under root
+func NewFileSystemStore(root string) (*FileSystemStore, error) {
+	if root == "" {
+		return nil, errors.New("report storage path is empty")
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("could not create report storage directory: %w", err)
+	}
+
+	return &FileSystemStore{root: root}, nil
+}
+
+// path returns the file path of the key, keys can not point outside of the root
+func (s *FileSystemStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+// Put writes the object to a temporary file and moves it to its path, so readers never see partial objects.
+// Content type is detected from the content when the object is read
+func (s *FileSystemStore) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	target := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(target), tempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), target)
+}
+
+// Get opens the object file
+func (s *FileSystemStore) Get(_ context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ObjectInfo{}, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	info, err := fileObjectInfo(key, f)
+	if err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, err
+	}
+
+	return f, info, nil
+}
+
+// Stat returns the object file info
+func (s *FileSystemStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	f, info, err := s.Get(ctx, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	f.Close()
+
+	return info, nil
+}
+
+// List walks the directory of the prefix and returns the files with keys starting with prefix
+func (s *FileSystemStore) List(_ context.Context, prefix string) ([]ObjectInfo, error) {
+	dir := s.root
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = s.path(prefix[:i])
+	}
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         fi.Size(),
+			LastModified: fi.ModTime(),
+			ETag:         fileETag(fi),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// Delete removes the object file
+func (s *FileSystemStore) Delete(_ context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func fileObjectInfo(key string, f *os.File) (ObjectInfo, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	// directories are prefixes of object keys, not objects
+	if fi.IsDir() {
+		return ObjectInfo{}, ErrObjectNotFound
+	}
+
+	contentType, err := detectContentType(key, f)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:          key,
+		Size:         fi.Size(),
+		ContentType:  contentType,
+		LastModified: fi.ModTime(),
+		ETag:         fileETag(fi),
+	}, nil
+}
+
+func fileETag(fi fs.FileInfo) string {
+	return fmt.Sprintf("%x-%x", fi.ModTime().UnixNano(), fi.Size())
+}
+
+// detectContentType returns the content type by the key extension or by the content,
+// reports are usually uploaded without extension
+func detectContentType(key string, r io.ReadSeeker) (string, error) {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType, nil
+	}
+
+	head, err := readHead(r)
+	if err != nil {
+		return "", err
+	}
+	return sniffContentType(head), nil
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystemStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewFileSystemStore(root)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "loadtest-name", bytes.NewReader(newTestTar(t, map[string]string{"index.html": "<html/>"})), -1, ""))
+	require.NoError(t, store.Put(ctx, "archive/loadtest-name/metadata.json", strings.NewReader(`{}`), 2, "application/json"))
+	require.NoError(t, store.Put(ctx, "archive/loadtest-name/master.log", strings.NewReader("started"), 7, "text/plain"))
+
+	t.Run("get", func(t *testing.T) {
+		obj, info, err := store.Get(ctx, "archive/loadtest-name/metadata.json")
+		require.NoError(t, err)
+		defer obj.Close()
+
+		content, err := io.ReadAll(obj)
+		require.NoError(t, err)
+		assert.Equal(t, `{}`, string(content))
+		assert.Equal(t, "archive/loadtest-name/metadata.json", info.Key)
+		assert.Equal(t, int64(2), info.Size)
+		assert.Equal(t, "application/json", info.ContentType)
+		assert.NotEmpty(t, info.ETag)
+	})
+
+	t.Run("content type is detected", func(t *testing.T) {
+		info, err := store.Stat(ctx, "loadtest-name")
+		require.NoError(t, err)
+		assert.Equal(t, "application/x-tar", info.ContentType)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := store.Get(ctx, "missing")
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+
+		_, err = store.Stat(ctx, "archive/loadtest-name")
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		objects, err := store.List(ctx, "archive/loadtest-name/")
+		require.NoError(t, err)
+
+		var keys []string
+		for _, object := range objects {
+			keys = append(keys, object.Key)
+		}
+		assert.ElementsMatch(t, []string{"archive/loadtest-name/metadata.json", "archive/loadtest-name/master.log"}, keys)
+
+		objects, err = store.List(ctx, "other/")
+		require.NoError(t, err)
+		assert.Empty(t, objects)
+	})
+
+	t.Run("keys stay in root", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "../../escaped", strings.NewReader("content"), -1, ""))
+
+		_, err := os.Stat(filepath.Join(root, "escaped"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, "archive/loadtest-name/master.log"))
+		require.NoError(t, store.Delete(ctx, "archive/loadtest-name/master.log"))
+
+		_, err := store.Stat(ctx, "archive/loadtest-name/master.log")
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+	})
+}
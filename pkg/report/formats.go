@@ -0,0 +1,124 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	contentTypeTar  = "application/x-tar"
+	contentTypeGzip = "application/gzip"
+	contentTypeZstd = "application/zstd"
+	contentTypeZip  = "application/zip"
+
+	// sniffLen is the number of bytes used to detect the report format
+	sniffLen = 512
+	// tarMagicOffset is the offset of the "ustar" magic in a tar header
+	tarMagicOffset = 257
+	// maxDecompressedSize limits the size of decompressed gzip and zstd reports kept in memory,
+	// the archive index cache fits a report of this size, so reports are decompressed once
+	maxDecompressedSize = 256 << 20
+)
+
+// ErrReportTooLarge is returned when a compressed report is too large to be served
+var ErrReportTooLarge = fmt.Errorf("decompressed report is larger than %d bytes", maxDecompressedSize)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagics = [][]byte{[]byte("PK\x03\x04"), []byte("PK\x05\x06")}
+
+	// archiveContentTypes normalizes the content types of supported archive formats
+	archiveContentTypes = map[string]string{
+		contentTypeTar:                 contentTypeTar,
+		contentTypeGzip:                contentTypeGzip,
+		"application/x-gzip":           contentTypeGzip,
+		contentTypeZstd:                contentTypeZstd,
+		contentTypeZip:                 contentTypeZip,
+		"application/x-zip-compressed": contentTypeZip,
+	}
+)
+
+// sniffArchiveContentType returns the content type of the archive format of the content, empty when it is not an archive
+func sniffArchiveContentType(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return contentTypeGzip
+	case bytes.HasPrefix(head, zstdMagic):
+		return contentTypeZstd
+	case bytes.HasPrefix(head, zipMagics[0]) || bytes.HasPrefix(head, zipMagics[1]):
+		return contentTypeZip
+	case len(head) >= tarMagicOffset+5 && string(head[tarMagicOffset:tarMagicOffset+5]) == "ustar":
+		return contentTypeTar
+	}
+	return ""
+}
+
+// sniffContentType returns the content type of the content, archive formats are detected before the formats known to net/http
+func sniffContentType(head []byte) string {
+	if contentType := sniffArchiveContentType(head); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(head)
+}
+
+// archiveContentType returns the normalized archive content type, empty when the content type is not a supported archive
+func archiveContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return archiveContentTypes[mediaType]
+}
+
+// readHead reads the first bytes of the content and seeks back to its start
+func readHead(r io.ReadSeeker) ([]byte, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// decompress returns the content of a gzip or zstd compressed report
+func decompress(contentType string, r io.Reader) ([]byte, error) {
+	var decompressed io.Reader
+	switch contentType {
+	case contentTypeGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("could not read gzip report: %w", err)
+		}
+		defer gz.Close()
+		decompressed = gz
+	case contentTypeZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("could not read zstd report: %w", err)
+		}
+		defer zr.Close()
+		decompressed = zr
+	default:
+		return nil, fmt.Errorf("%s is not a compressed format", contentType)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(decompressed, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress report: %w", err)
+	}
+	if len(content) > maxDecompressedSize {
+		return nil, ErrReportTooLarge
+	}
+	return content, nil
+}
@@ -1,146 +1,147 @@
 package report
 
 import (
-	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/minio/minio-go/v7"
-	"github.com/spf13/afero"
 	"go.uber.org/zap"
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 
 	khttp "github.com/hellofresh/kangal/pkg/core/http"
-	kk8s "github.com/hellofresh/kangal/pkg/kubernetes"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
 )
 
 var httpClient = &http.Client{
 	Timeout: 30 * time.Second,
 }
 
-// ShowHandler method returns response from file bucket in defined object storage
-func ShowHandler() func(w http.ResponseWriter, r *http.Request) {
-
-	if minioClient == nil {
-		panic("client was not initialized, please initialize object storage client")
-	}
-	if bucketName == "" {
-		panic("bucket name was not defined or empty")
+// ShowHandler method returns response from the report store, indexes of report archives are cached in the given cache
+// shared with FilesHandler of the same store
+func ShowHandler(store ReportStore, indexes *ArchiveIndexCache) func(w http.ResponseWriter, r *http.Request) {
+	if store == nil {
+		panic("report store was not initialized")
 	}
 
-	tmpDir := fmt.Sprintf("%s/kangal", strings.TrimRight(os.TempDir(), "/"))
-
-	go func() {
-		ticker := time.NewTicker(5 * time.Hour)
-		for range ticker.C {
-			os.RemoveAll(tmpDir)
-		}
-	}()
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		loadTestName := chi.URLParam(r, "id")
 		file := chi.URLParam(r, "*")
+		if IsReservedName(loadTestName) {
+			http.NotFound(w, r)
+			return
+		}
 
 		r.URL.Path = fmt.Sprintf("/%s", loadTestName)
 		if file != "" {
 			r.URL.Path += fmt.Sprintf("/%s", file)
 		}
 
-		// first, try to handle uncompressed tar archive
-		obj, err := minioClient.GetObject(ctx, bucketName, loadTestName, minio.GetObjectOptions{})
+		// first, try to handle report archive
+		obj, objStat, err := store.Get(ctx, loadTestName)
+		// not found error can be a directory
+		if errors.Is(err, ErrObjectNotFound) {
+			http.FileServer(&storeFileSystem{ctx: ctx, store: store}).ServeHTTP(w, r)
+			return
+		}
+		// unknown error
 		if nil != err {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		defer obj.Close()
 
-		objStat, err := obj.Stat()
-		// not found error can be a directory
-		if objErr, _ := err.(minio.ErrorResponse); http.StatusNotFound == objErr.StatusCode {
-			http.FileServer(fs).ServeHTTP(w, r)
-			return
-		}
-		// unknown error
+		contentType, err := reportContentType(obj, objStat)
 		if nil != err {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// serve uncompressed tar archive content
-		if objStat.ContentType == "application/x-tar" {
-			prefix := fmt.Sprintf("%s/%s", tmpDir, loadTestName)
-			err = untar(prefix, obj, afero.NewOsFs())
+		// serve report archive content, files are read from their offsets in the archive
+		if contentType != "" {
+			index, err := indexes.index(loadTestName, contentType, obj, objStat)
 			if nil != err {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+
+			// compressed single file is served as the report itself
+			if index.single {
+				if file != "" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", sniffContentType(index.data[:min(len(index.data), sniffLen)]))
+				w.Header().Set("ETag", fmt.Sprintf(`"%s"`, strings.Trim(objStat.ETag, `"`)))
+				http.ServeContent(w, r, objStat.Key, objStat.LastModified, bytes.NewReader(index.data))
+				return
+			}
+
+			if etag := index.entryETag(file); etag != "" {
+				w.Header().Set("ETag", etag)
+			}
 			r.URL.Path = fmt.Sprintf("/%s", file)
-			http.FileServer(http.Dir(prefix)).ServeHTTP(w, r)
+			http.FileServer(newArchiveFileSystem(index, obj)).ServeHTTP(w, r)
 			return
 		}
 
 		// serve existing static file
+		if objStat.ETag != "" {
+			w.Header().Set("ETag", fmt.Sprintf(`"%s"`, strings.Trim(objStat.ETag, `"`)))
+		}
 		http.ServeContent(w, r, objStat.Key, objStat.LastModified, obj)
 	}
 }
 
-func untar(prefix string, obj io.Reader, afs afero.Fs) error {
-	_, err := afs.Stat(prefix)
-	if nil == err {
-		// means its already exists locally
-		return nil
+// reportContentType returns the archive format of the report, empty when the report is a static file.
+// Reports uploaded without content type are detected by their content
+func reportContentType(obj io.ReadSeeker, objStat ObjectInfo) (string, error) {
+	if contentType := archiveContentType(objStat.ContentType); contentType != "" {
+		return contentType, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(objStat.ContentType)
+	if mediaType != "" && mediaType != "application/octet-stream" && mediaType != "binary/octet-stream" {
+		return "", nil
 	}
 
-	reader := tar.NewReader(obj)
-	for {
-		header, err := reader.Next()
-		if err == io.EOF {
-			return nil
-		}
-		if nil != err {
-			return err
-		}
-		if header == nil {
-			continue
-		}
-		target := fmt.Sprintf("%s/%s", prefix, strings.Trim(header.Name, "./"))
-		switch header.Typeflag {
-		case tar.TypeDir:
-			if _, err := afs.Stat(target); err != nil {
-				if err := afs.MkdirAll(target, 0755); err != nil {
-					return err
-				}
-			}
-		case tar.TypeReg:
-			f, err := afs.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-			if err != nil {
-				return err
-			}
-			if _, err := io.Copy(f, reader); err != nil {
-				return err
-			}
-			f.Close()
-		}
+	head, err := readHead(obj)
+	if err != nil {
+		return "", err
 	}
+	return sniffArchiveContentType(head), nil
 }
 
-// PersistHandler method streams request to storage presigned URL
-func PersistHandler(kubeClient *kk8s.Client, logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
-	if minioClient == nil {
-		panic("client was not initialized, please initialize object storage client")
+// LoadTestGetter gets loadtests the reports are persisted for
+type LoadTestGetter interface {
+	GetLoadTest(ctx context.Context, loadTest string) (*loadTestV1.LoadTest, error)
+}
+
+// PersistHandler method streams request to the report store, to storage presigned URL when the store supports it
+func PersistHandler(store ReportStore, kubeClient LoadTestGetter, logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	if store == nil {
+		panic("report store was not initialized")
 	}
+	preSigner, preSigned := store.(PreSigner)
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		loadTestName := chi.URLParam(r, "id")
+		if IsReservedName(loadTestName) {
+			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, fmt.Sprintf("report name %q is reserved", loadTestName)))
+			return
+		}
 
-		_, err := kubeClient.GetLoadTest(r.Context(), loadTestName)
+		loadTest, err := kubeClient.GetLoadTest(r.Context(), loadTestName)
 		if k8sAPIErrors.IsNotFound(err) {
 			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, err.Error()))
 			return
@@ -150,19 +151,38 @@ func PersistHandler(kubeClient *kk8s.Client, logger *zap.Logger) func(w http.Res
 			return
 		}
 
-		url, err := newPreSignedPutURL(r.Context(), loadTestName)
+		body, contentType := sniffReportBody(r)
+
+		if !preSigned {
+			err = store.Put(r.Context(), loadTestName, body, r.ContentLength, contentType)
+			if nil != err {
+				logger.Error("Failed to persist report", zap.Error(err), zap.String("loadtest", loadTestName))
+				render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+				return
+			}
+			summarize(r.Context(), store, loadTest, logger)
+
+			render.Status(r, http.StatusOK)
+			render.JSON(w, r, "Report persisted")
+			return
+		}
+
+		url, err := preSigner.PresignedPutURL(r.Context(), loadTestName)
 		if nil != err {
 			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
 			return
 		}
 
-		proxyReq, err := http.NewRequestWithContext(r.Context(), r.Method, url.String(), r.Body)
+		proxyReq, err := http.NewRequestWithContext(r.Context(), r.Method, url.String(), body)
 		if nil != err {
 			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
 			return
 		}
 		proxyReq.ContentLength = r.ContentLength
-		proxyReq.Header = r.Header
+		proxyReq.Header = r.Header.Clone()
+		if contentType != "" {
+			proxyReq.Header.Set("Content-Type", contentType)
+		}
 
 		proxyResp, err := httpClient.Do(proxyReq)
 		if nil != err {
@@ -179,7 +199,37 @@ func PersistHandler(kubeClient *kk8s.Client, logger *zap.Logger) func(w http.Res
 			return
 		}
 
+		summarize(r.Context(), store, loadTest, logger)
+
 		render.Status(r, proxyResp.StatusCode)
 		render.JSON(w, r, "Report persisted")
 	}
 }
+
+// sniffReportBody returns the request body with the report content type, archives are stored with their detected
+// content type as clients often upload them as application/octet-stream
+func sniffReportBody(r *http.Request) (io.Reader, string) {
+	contentType := r.Header.Get("Content-Type")
+	if r.Body == nil || r.Body == http.NoBody {
+		return http.NoBody, contentType
+	}
+
+	body := bufio.NewReaderSize(r.Body, sniffLen)
+	head, _ := body.Peek(sniffLen)
+	if archiveType := sniffArchiveContentType(head); archiveType != "" {
+		contentType = archiveType
+	}
+	return body, contentType
+}
+
+// summarize stores the results summary of the persisted report, report is persisted even if its results can not be read
+func summarize(ctx context.Context, store ReportStore, loadTest *loadTestV1.LoadTest, logger *zap.Logger) {
+	err := storeSummary(ctx, store, loadTest.Spec.Type, loadTest.GetName())
+	if errors.Is(err, results.ErrUnsupportedType) {
+		logger.Debug("Results of load test type are not summarized", zap.String("loadtest", loadTest.GetName()))
+		return
+	}
+	if err != nil {
+		logger.Warn("Failed to summarize report results", zap.Error(err), zap.String("loadtest", loadTest.GetName()))
+	}
+}
@@ -3,21 +3,19 @@ package report
 import (
 	"archive/tar"
 	"bytes"
-	"errors"
-	"fmt"
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/minio/minio-go/v7"
-	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
@@ -27,46 +25,57 @@ import (
 	"github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
 )
 
-// fakeFS mocks http.FileSystem
-type fakeFS struct{}
+func newTestTar(t *testing.T, files map[string]string) []byte {
+	tarball := bytes.NewBuffer(nil)
+	writer := tar.NewWriter(tarball)
 
-func (fakeFS) Open(name string) (http.File, error) {
-	if name != "/loadtest-name" {
-		return nil, errors.New("path is incorrect")
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "./", Mode: 0755, Typeflag: tar.TypeDir}))
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
 	}
-	return os.Open("handler_test.go")
+	require.NoError(t, writer.Close())
+
+	return tarball.Bytes()
 }
 
 func TestShowHandler(t *testing.T) {
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer srv.Close()
-
-	req, err := http.NewRequest("GET", "/load-test/loadtest-name/report/", nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	// init dependencies for report package
-	minioOptions := minio.Options{
-		Secure: false,
-	}
-	minioClient, _ = minio.New(srv.Listener.Addr().String(), &minioOptions)
-	bucketName = "bucket-name"
-	fs = &fakeFS{}
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
 
-	rr := httptest.NewRecorder()
+	require.NoError(t, store.Put(ctx, "html-report", strings.NewReader("<html>report</html>"), -1, "text/html"))
+	require.NoError(t, store.Put(ctx, "tar-report", bytes.NewReader(newTestTar(t, map[string]string{"./index.html": "<html>tar</html>"})), -1, "application/x-tar"))
+	require.NoError(t, store.Put(ctx, "dir-report/index.html", strings.NewReader("<html>dir</html>"), -1, "text/html"))
+	require.NoError(t, store.Put(ctx, ArchiveObjectName("dir-report", "logs/master.log"), strings.NewReader("secret logs"), -1, "text/plain"))
+	require.NoError(t, store.Put(ctx, HistoryObjectName("dir-report"), strings.NewReader(`{"name":"dir-report"}`), -1, "application/json"))
 
 	handler := chi.NewRouter()
-	handler.Get("/load-test/{id}/report/*", ShowHandler())
+	handler.Get("/load-test/{id}/report/*", ShowHandler(store, NewArchiveIndexCache()))
 
-	handler.ServeHTTP(rr, req)
+	for _, tt := range []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"/load-test/html-report/report/", http.StatusOK, "<html>report</html>"},
+		{"/load-test/tar-report/report/", http.StatusOK, "<html>tar</html>"},
+		{"/load-test/dir-report/report/", http.StatusOK, "<html>dir</html>"},
+		{"/load-test/missing-report/report/", http.StatusNotFound, ""},
+		{"/load-test/archive/report/", http.StatusNotFound, ""},
+		{"/load-test/archive/report/dir-report/logs/master.log", http.StatusNotFound, ""},
+		{"/load-test/history/report/", http.StatusNotFound, ""},
+		{"/load-test/history/report/dir-report.json", http.StatusNotFound, ""},
+		{"/load-test/dir-report/report/..%2F..%2Farchive/dir-report/logs/master.log", http.StatusNotFound, ""},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
 
-	// Check the status code is what we expect.
-	if status := rr.Code; status != http.StatusOK {
-		t.Errorf("handler returned wrong status code: got %v want %v",
-			status, http.StatusOK)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
 	}
 }
 
@@ -122,14 +131,8 @@ func TestPersistHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// init dependencies for report package
-	minioOptions := minio.Options{
-		Secure: false,
-		Region: "us-east1",
-	}
-	minioClient, _ = minio.New("localhost:80", &minioOptions)
-	bucketName = "bucket-name"
-	expires = time.Second
+	store, err := NewMinioStore(newTestMinioConfig("localhost:80"))
+	require.NoError(t, err)
 	logger := zap.NewNop()
 
 	for _, scenario := range scenarios {
@@ -153,7 +156,7 @@ func TestPersistHandler(t *testing.T) {
 
 			rr := httptest.NewRecorder()
 			handler := chi.NewRouter()
-			handler.Put("/load-test/{id}/report", PersistHandler(kangalKubeClient, logger))
+			handler.Put("/load-test/{id}/report", PersistHandler(store, kangalKubeClient, logger))
 			handler.ServeHTTP(rr, req)
 
 			assert.Equal(t, rr.Code, scenario.expectedStatusCode)
@@ -161,34 +164,68 @@ func TestPersistHandler(t *testing.T) {
 	}
 }
 
-func TestUntar(t *testing.T) {
-	tarball := bytes.NewBuffer(nil)
+func TestPersistHandlerFileSystemStore(t *testing.T) {
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
 
-	writer := tar.NewWriter(tarball)
-	defer writer.Close()
-
-	for i := 0; i < 10; i++ {
-		str := fmt.Sprintf("my-file-%d", i)
-		file := bytes.NewReader([]byte(str))
-
-		header := &tar.Header{
-			Name:    str,
-			Size:    file.Size(),
-			Mode:    0644,
-			ModTime: time.Now(),
-		}
-
-		err := writer.WriteHeader(header)
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		_, err = io.Copy(writer, file)
-		if err != nil {
-			t.Fatal(err)
-		}
-	}
+	kangalFakeClientSet := fake.NewSimpleClientset(&loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}})
+	kangalKubeClient := kk8s.NewClient(kangalFakeClientSet.KangalV1().LoadTests(), k8sfake.NewSimpleClientset(), zap.NewNop())
+
+	handler := chi.NewRouter()
+	handler.Put("/load-test/{id}/report", PersistHandler(store, kangalKubeClient, zap.NewNop()))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/load-test/loadtest-name/report", strings.NewReader(`{"metrics":{}}`)))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rc, err := NewReader(store).Read(context.Background(), "loadtest-name")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, `{"metrics":{}}`, string(content))
+}
+
+func TestPersistHandlerDetectsArchives(t *testing.T) {
+	store, err := NewMinioStore(newTestMinioConfig("localhost:80"))
+	require.NoError(t, err)
+
+	var contentType string
+	httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) *http.Response {
+		contentType = req.Header.Get("Content-Type")
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody}
+	})}
+
+	kangalFakeClientSet := fake.NewSimpleClientset(&loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}})
+	kangalKubeClient := kk8s.NewClient(kangalFakeClientSet.KangalV1().LoadTests(), k8sfake.NewSimpleClientset(), zap.NewNop())
 
-	err := untar("/my-load-test", tarball, afero.NewMemMapFs())
-	assert.NoError(t, err)
+	handler := chi.NewRouter()
+	handler.Put("/load-test/{id}/report", PersistHandler(store, kangalKubeClient, zap.NewNop()))
+
+	for _, tt := range []struct {
+		name                string
+		body                []byte
+		contentType         string
+		expectedContentType string
+	}{
+		{"tar", newTestTar(t, map[string]string{"index.html": "<html/>"}), "application/octet-stream", "application/x-tar"},
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00}, "", "application/gzip"},
+		{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}, "application/octet-stream", "application/zstd"},
+		{"zip", []byte("PK\x05\x06" + strings.Repeat("\x00", 18)), "application/octet-stream", "application/zip"},
+		{"html", []byte("<html/>"), "text/html", "text/html"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/load-test/loadtest-name/report", bytes.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expectedContentType, contentType)
+		})
+	}
 }
@@ -0,0 +1,391 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+
+	khttp "github.com/hellofresh/kangal/pkg/core/http"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// HistoryPrefix is the bucket prefix under which metadata records of completed loadtests are stored
+const HistoryPrefix = "history"
+
+// historyIndexName is the object of the history prefix indexing the records, loadtest names never start with a dot
+const historyIndexName = ".index.json"
+
+// continueTokenSeparator separates the creation time and the name of the last listed record in the continue token
+const continueTokenSeparator = " "
+
+// ErrInvalidContinue is returned when the continue token was not returned by a previous list
+var ErrInvalidContinue = errors.New("invalid continue token")
+
+// FileDigest identifies loadtest file content without keeping the content, which can hold credentials
+type FileDigest struct {
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// NewFileDigest returns the digest of the content, nil when there is no content
+func NewFileDigest(content []byte) *FileDigest {
+	if len(content) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	return &FileDigest{Size: len(content), SHA256: hex.EncodeToString(sum[:])}
+}
+
+// HistoryRecord is the metadata of a completed loadtest, it is kept after the loadtest is deleted
+type HistoryRecord struct {
+	Name              string                    `json:"name"`
+	Type              loadTestV1.LoadTestType   `json:"type"`
+	Tags              loadTestV1.LoadTestTags   `json:"tags,omitempty"`
+	CreationTimestamp time.Time                 `json:"creationTimestamp"`
+	Spec              loadTestV1.LoadTestSpec   `json:"spec"`
+	Status            loadTestV1.LoadTestStatus `json:"status"`
+	// TestFile and TestData identify the loadtest files, the spec does not keep them
+	TestFile *FileDigest `json:"testFile,omitempty"`
+	TestData *FileDigest `json:"testData,omitempty"`
+	// ReportKey is the report store key of the loadtest report
+	ReportKey string `json:"reportKey"`
+}
+
+// HistoryListOptions limits the listed records, zero limit lists all of them
+type HistoryListOptions struct {
+	Limit    int
+	Continue string
+}
+
+// HistoryPage is a page of history records, continue is empty on the last page
+type HistoryPage struct {
+	Limit    int             `json:"limit"`
+	Continue string          `json:"continue"`
+	Items    []HistoryRecord `json:"items"`
+}
+
+// HistoryFilter selects history records, empty fields match all records
+type HistoryFilter struct {
+	Tags  loadTestV1.LoadTestTags
+	Type  loadTestV1.LoadTestType
+	Since time.Time
+	Until time.Time
+}
+
+// Match returns true when the record matches all the filter fields
+func (f HistoryFilter) Match(record HistoryRecord) bool {
+	if f.Type != "" && record.Type != f.Type {
+		return false
+	}
+	for key, value := range f.Tags {
+		if record.Tags[key] != value {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && record.CreationTimestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.CreationTimestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// historyIndexEntry is the part of a history record the records are filtered and sorted by
+type historyIndexEntry struct {
+	Name              string                  `json:"name"`
+	Type              loadTestV1.LoadTestType `json:"type"`
+	Tags              loadTestV1.LoadTestTags `json:"tags,omitempty"`
+	CreationTimestamp time.Time               `json:"creationTimestamp"`
+}
+
+func newHistoryIndexEntry(record HistoryRecord) historyIndexEntry {
+	return historyIndexEntry{Name: record.Name, Type: record.Type, Tags: record.Tags, CreationTimestamp: record.CreationTimestamp}
+}
+
+func (e historyIndexEntry) record() HistoryRecord {
+	return HistoryRecord{Name: e.Name, Type: e.Type, Tags: e.Tags, CreationTimestamp: e.CreationTimestamp}
+}
+
+// History stores loadtest metadata records in the report store. Records are indexed in a single object,
+// so lists read the full records of the returned page only
+type History struct {
+	store ReportStore
+	// mu serializes index updates of the recorder, records are written by the controller leader only
+	mu sync.Mutex
+}
+
+// NewHistory returns a history catalog using the report store
+func NewHistory(store ReportStore) *History {
+	return &History{store: store}
+}
+
+// Record stores the loadtest metadata record, replacing the previous record of the loadtest, and adds it to the index
+func (h *History) Record(ctx context.Context, record HistoryRecord) error {
+	content, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.Put(ctx, HistoryObjectName(record.Name), bytes.NewReader(content), int64(len(content)), "application/json")
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries, err := h.index(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated := append(make([]historyIndexEntry, 0, len(entries)+1), newHistoryIndexEntry(record))
+	for _, entry := range entries {
+		if entry.Name != record.Name {
+			updated = append(updated, entry)
+		}
+	}
+
+	content, err = json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	return h.store.Put(ctx, path.Join(HistoryPrefix, historyIndexName), bytes.NewReader(content), int64(len(content)), "application/json")
+}
+
+// index returns the indexed records, the index is built from the records stored before it existed
+func (h *History) index(ctx context.Context) ([]historyIndexEntry, error) {
+	key := path.Join(HistoryPrefix, historyIndexName)
+	obj, _, err := h.store.Get(ctx, key)
+	if errors.Is(err, ErrObjectNotFound) {
+		return h.buildIndex(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	var entries []historyIndexEntry
+	if err := json.NewDecoder(obj).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("could not read history index %s: %w", key, err)
+	}
+	return entries, nil
+}
+
+// buildIndex reads every stored record
+func (h *History) buildIndex(ctx context.Context) ([]historyIndexEntry, error) {
+	objects, err := h.store.List(ctx, HistoryPrefix+pathSeparator)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]historyIndexEntry, 0, len(objects))
+	for _, object := range objects {
+		if path.Base(object.Key) == historyIndexName {
+			continue
+		}
+		record, err := h.get(ctx, object.Key)
+		// record can be removed while listing
+		if errors.Is(err, ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, newHistoryIndexEntry(record))
+	}
+	return entries, nil
+}
+
+// List returns the records matching the filter, most recently created loadtests first.
+// The page continue token lists the records following the page
+func (h *History) List(ctx context.Context, filter HistoryFilter, opts HistoryListOptions) (HistoryPage, error) {
+	var after *HistoryRecord
+	if opts.Continue != "" {
+		last, err := parseContinueToken(opts.Continue)
+		if err != nil {
+			return HistoryPage{}, err
+		}
+		after = &last
+	}
+
+	entries, err := h.index(ctx)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	matched := make([]HistoryRecord, 0, len(entries))
+	for _, entry := range entries {
+		record := entry.record()
+		if filter.Match(record) && (after == nil || listedBefore(*after, record)) {
+			matched = append(matched, record)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return listedBefore(matched[i], matched[j])
+	})
+
+	page := HistoryPage{Limit: opts.Limit}
+	if opts.Limit > 0 && len(matched) > opts.Limit {
+		matched = matched[:opts.Limit]
+		page.Continue = continueToken(matched[opts.Limit-1])
+	}
+
+	page.Items = make([]HistoryRecord, 0, len(matched))
+	for _, indexed := range matched {
+		record, err := h.get(ctx, HistoryObjectName(indexed.Name))
+		// record can be removed while listing
+		if errors.Is(err, ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return HistoryPage{}, err
+		}
+		page.Items = append(page.Items, record)
+	}
+	return page, nil
+}
+
+// listedBefore orders records by creation time, newest first, and by name when created at the same time
+func listedBefore(a, b HistoryRecord) bool {
+	if !a.CreationTimestamp.Equal(b.CreationTimestamp) {
+		return a.CreationTimestamp.After(b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// continueToken returns the token listing records following the last listed record
+func continueToken(last HistoryRecord) string {
+	token := strconv.FormatInt(last.CreationTimestamp.UnixNano(), 10) + continueTokenSeparator + last.Name
+	return base64.RawURLEncoding.EncodeToString([]byte(token))
+}
+
+// parseContinueToken returns the last listed record position stored in the token
+func parseContinueToken(token string) (HistoryRecord, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return HistoryRecord{}, ErrInvalidContinue
+	}
+	createdAt, name, found := strings.Cut(string(decoded), continueTokenSeparator)
+	nanos, err := strconv.ParseInt(createdAt, 10, 64)
+	if !found || err != nil {
+		return HistoryRecord{}, ErrInvalidContinue
+	}
+	return HistoryRecord{Name: name, CreationTimestamp: time.Unix(0, nanos)}, nil
+}
+
+func (h *History) get(ctx context.Context, key string) (HistoryRecord, error) {
+	obj, _, err := h.store.Get(ctx, key)
+	if err != nil {
+		return HistoryRecord{}, err
+	}
+	defer obj.Close()
+
+	var record HistoryRecord
+	if err := json.NewDecoder(obj).Decode(&record); err != nil {
+		return HistoryRecord{}, fmt.Errorf("could not read history record %s: %w", key, err)
+	}
+	return record, nil
+}
+
+// HistoryObjectName returns the bucket object name of the loadtest history record
+func HistoryObjectName(loadTestName string) string {
+	return path.Join(HistoryPrefix, loadTestName+".json")
+}
+
+// HistoryHandler method returns a page of history records of loadtests filtered by tags, type and creation time,
+// pages have at most maxLimit records
+func HistoryHandler(history *History, maxLimit int) func(w http.ResponseWriter, r *http.Request) {
+	if history == nil {
+		panic("history was not initialized")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := historyFilterFromRequest(r)
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+		opts, err := historyListOptionsFromRequest(r, maxLimit)
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		page, err := history.List(r.Context(), filter, opts)
+		if errors.Is(err, ErrInvalidContinue) {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		render.JSON(w, r, page)
+	}
+}
+
+// historyListOptionsFromRequest reads the page from limit and continue query parameters, limit defaults to maxLimit
+func historyListOptionsFromRequest(r *http.Request, maxLimit int) (HistoryListOptions, error) {
+	params := r.URL.Query()
+	opts := HistoryListOptions{Limit: maxLimit, Continue: params.Get("continue")}
+
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return HistoryListOptions{}, fmt.Errorf("invalid limit %q", value)
+		}
+		if limit > maxLimit {
+			return HistoryListOptions{}, fmt.Errorf("limit value is too big, max possible value is %d", maxLimit)
+		}
+		opts.Limit = limit
+	}
+
+	return opts, nil
+}
+
+// historyFilterFromRequest reads the filter from tags, type, since and until query parameters,
+// tags use the same format as the loadtest tags, times are RFC3339
+func historyFilterFromRequest(r *http.Request) (HistoryFilter, error) {
+	params := r.URL.Query()
+
+	tags, err := loadTestV1.LoadTestTagsFromString(params.Get("tags"))
+	if err != nil {
+		return HistoryFilter{}, fmt.Errorf("invalid tags: %w", err)
+	}
+
+	filter := HistoryFilter{
+		Tags: tags,
+		Type: loadTestV1.LoadTestType(strings.TrimSpace(params.Get("type"))),
+	}
+
+	for name, into := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		*into, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return HistoryFilter{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+
+	return filter, nil
+}
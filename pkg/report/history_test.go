@@ -0,0 +1,181 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func newTestHistory(t *testing.T) *History {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	history := NewHistory(store)
+	for _, record := range []HistoryRecord{
+		{Name: "k6-old", Type: loadTestV1.LoadTestTypeK6, Tags: loadTestV1.LoadTestTags{"team": "kangal"}, CreationTimestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "k6-new", Type: loadTestV1.LoadTestTypeK6, Tags: loadTestV1.LoadTestTags{"team": "kangal", "env": "staging"}, CreationTimestamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "jmeter", Type: loadTestV1.LoadTestTypeJMeter, Tags: loadTestV1.LoadTestTags{"team": "other"}, CreationTimestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		record.ReportKey = record.Name
+		require.NoError(t, history.Record(ctx, record))
+	}
+	// report objects are not history records
+	require.NoError(t, store.Put(ctx, "k6-old", strings.NewReader("<html/>"), -1, ""))
+
+	return history
+}
+
+func TestHistoryList(t *testing.T) {
+	history := newTestHistory(t)
+
+	for _, tt := range []struct {
+		name     string
+		filter   HistoryFilter
+		expected []string
+	}{
+		{"all records newest first", HistoryFilter{}, []string{"k6-new", "jmeter", "k6-old"}},
+		{"type", HistoryFilter{Type: loadTestV1.LoadTestTypeK6}, []string{"k6-new", "k6-old"}},
+		{"tags", HistoryFilter{Tags: loadTestV1.LoadTestTags{"team": "kangal", "env": "staging"}}, []string{"k6-new"}},
+		{"time range", HistoryFilter{Since: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)}, []string{"jmeter"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := history.List(context.Background(), tt.filter, HistoryListOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, page.Continue)
+
+			var names []string
+			for _, record := range page.Items {
+				names = append(names, record.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestHistoryListPages(t *testing.T) {
+	history := newTestHistory(t)
+
+	var names []string
+	opts := HistoryListOptions{Limit: 2}
+	for {
+		page, err := history.List(context.Background(), HistoryFilter{}, opts)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Items), 2)
+		for _, record := range page.Items {
+			names = append(names, record.Name)
+		}
+		if page.Continue == "" {
+			break
+		}
+		opts.Continue = page.Continue
+	}
+	assert.Equal(t, []string{"k6-new", "jmeter", "k6-old"}, names)
+
+	_, err := history.List(context.Background(), HistoryFilter{}, HistoryListOptions{Continue: "not a token"})
+	assert.ErrorIs(t, err, ErrInvalidContinue)
+}
+
+// countingStore counts the objects read from the store
+type countingStore struct {
+	ReportStore
+	gets []string
+}
+
+func (s *countingStore) Get(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	s.gets = append(s.gets, key)
+	return s.ReportStore.Get(ctx, key)
+}
+
+func TestHistoryListReadsPageRecords(t *testing.T) {
+	ctx := context.Background()
+	store := &countingStore{ReportStore: newTestHistory(t).store}
+	history := NewHistory(store)
+
+	page, err := history.List(ctx, HistoryFilter{}, HistoryListOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "k6-new", page.Items[0].Name)
+	assert.Equal(t, loadTestV1.LoadTestTags{"team": "kangal", "env": "staging"}, page.Items[0].Tags)
+	assert.Equal(t, []string{"history/.index.json", "history/k6-new.json"}, store.gets)
+}
+
+func TestHistoryListWithoutIndex(t *testing.T) {
+	ctx := context.Background()
+	store := newTestHistory(t).store
+	// records stored before the index existed
+	require.NoError(t, store.Delete(ctx, "history/.index.json"))
+
+	history := NewHistory(store)
+	page, err := history.List(ctx, HistoryFilter{Type: loadTestV1.LoadTestTypeK6}, HistoryListOptions{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+
+	// recording builds the index with the previous records
+	require.NoError(t, history.Record(ctx, HistoryRecord{Name: "k6-newest", Type: loadTestV1.LoadTestTypeK6, CreationTimestamp: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}))
+	require.NoError(t, history.Record(ctx, HistoryRecord{Name: "k6-old", Type: loadTestV1.LoadTestTypeK6, CreationTimestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}))
+
+	entries, err := history.index(ctx)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	page, err = history.List(ctx, HistoryFilter{}, HistoryListOptions{})
+	require.NoError(t, err)
+	var names []string
+	for _, record := range page.Items {
+		names = append(names, record.Name)
+	}
+	assert.Equal(t, []string{"k6-newest", "k6-new", "jmeter", "k6-old"}, names)
+}
+
+func TestHistoryHandler(t *testing.T) {
+	handler := HistoryHandler(newTestHistory(t), 50)
+
+	for _, tt := range []struct {
+		query          string
+		expectedStatus int
+		expected       []string
+	}{
+		{"", http.StatusOK, []string{"k6-new", "jmeter", "k6-old"}},
+		{"?type=K6&tags=team:kangal&since=2024-02-01T00:00:00Z", http.StatusOK, []string{"k6-new"}},
+		{"?type=locust", http.StatusOK, []string{}},
+		{"?tags=team", http.StatusBadRequest, nil},
+		{"?until=yesterday", http.StatusBadRequest, nil},
+		{"?limit=1", http.StatusOK, []string{"k6-new"}},
+		{"?limit=51", http.StatusBadRequest, nil},
+		{"?limit=0", http.StatusBadRequest, nil},
+		{"?continue=invalid", http.StatusBadRequest, nil},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest(http.MethodGet, "/history"+tt.query, nil))
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expected == nil {
+				return
+			}
+
+			var page HistoryPage
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+			names := []string{}
+			for _, record := range page.Items {
+				names = append(names, record.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestNewFileDigest(t *testing.T) {
+	assert.Nil(t, NewFileDigest(nil))
+	assert.Equal(t, &FileDigest{Size: 3, SHA256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"}, NewFileDigest([]byte("abc")))
+}
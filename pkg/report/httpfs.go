@@ -0,0 +1,157 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const pathSeparator = "/"
+
+// storeFileSystem exposes report store objects as a http.FileSystem, key prefixes ending with a slash are directories
+type storeFileSystem struct {
+	ctx   context.Context
+	store ReportStore
+}
+
+// Open implements http.FileSystem
+func (s *storeFileSystem) Open(name string) (http.File, error) {
+	if IsReservedName(name) {
+		return nil, os.ErrNotExist
+	}
+
+	if strings.HasSuffix(name, pathSeparator) {
+		return s.openDir(strings.TrimPrefix(name, pathSeparator))
+	}
+
+	name = strings.TrimPrefix(name, pathSeparator)
+	// loadtest report uploaded as separate objects is served from its index page
+	if !strings.Contains(name, pathSeparator) {
+		name = path.Join(name, "index.html")
+	}
+
+	obj, info, err := s.store.Get(s.ctx, name)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil, os.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeFile{ReadSeekCloser: obj, info: objectFileInfo{name: path.Base(info.Key), size: info.Size, modTime: info.LastModified}}, nil
+}
+
+func (s *storeFileSystem) openDir(prefix string) (http.File, error) {
+	objects, err := s.store.List(s.ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	// list only direct children of the prefix
+	children := map[string]objectFileInfo{}
+	for _, object := range objects {
+		name, _, isDir := strings.Cut(strings.TrimPrefix(object.Key, prefix), pathSeparator)
+		child := children[name]
+		child.name, child.isDir = name, isDir
+		if !isDir {
+			child.size = object.Size
+		}
+		if object.LastModified.After(child.modTime) {
+			child.modTime = object.LastModified
+		}
+		children[name] = child
+	}
+
+	entries := make([]fs.FileInfo, 0, len(children))
+	for _, child := range children {
+		entries = append(entries, child)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return &storeFile{
+		info:    objectFileInfo{name: path.Base(prefix), isDir: true, modTime: time.Now().UTC()},
+		entries: entries,
+	}, nil
+}
+
+// storeFile implements http.File for objects and directories of the store
+type storeFile struct {
+	io.ReadSeekCloser
+	info    objectFileInfo
+	entries []fs.FileInfo
+}
+
+func (f *storeFile) Close() error {
+	if f.ReadSeekCloser == nil {
+		return nil
+	}
+	return f.ReadSeekCloser.Close()
+}
+
+func (f *storeFile) Read(p []byte) (int, error) {
+	if f.ReadSeekCloser == nil {
+		return 0, fs.ErrInvalid
+	}
+	return f.ReadSeekCloser.Read(p)
+}
+
+func (f *storeFile) Seek(offset int64, whence int) (int64, error) {
+	if f.ReadSeekCloser == nil {
+		return 0, fs.ErrInvalid
+	}
+	return f.ReadSeekCloser.Seek(offset, whence)
+}
+
+func (f *storeFile) Readdir(count int) ([]fs.FileInfo, error) {
+	if !f.info.isDir {
+		return nil, fs.ErrInvalid
+	}
+	if count <= 0 {
+		entries := f.entries
+		f.entries = nil
+		return entries, nil
+	}
+	if len(f.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	count = min(count, len(f.entries))
+	entries := f.entries[:count]
+	f.entries = f.entries[count:]
+	return entries, nil
+}
+
+func (f *storeFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// objectFileInfo implements fs.FileInfo for objects and directories of the store
+type objectFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (i objectFileInfo) Name() string       { return i.name }
+func (i objectFileInfo) Size() int64        { return i.size }
+func (i objectFileInfo) ModTime() time.Time { return i.modTime }
+func (i objectFileInfo) IsDir() bool        { return i.isDir }
+func (i objectFileInfo) Sys() interface{}   { return nil }
+
+func (i objectFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinioStore stores reports in an S3 compatible bucket
+type MinioStore struct {
+	client  *minio.Client
+	bucket  string
+	expires time.Duration
+}
+
+// NewMinioStore returns new minio backend client to work with S3 compatible storages
+func NewMinioStore(cfg Config) (*MinioStore, error) {
+	// minio doesn't like http schema endpoints - dial tcp: too many colons in address
+	endpoint := strings.Replace(cfg.AWSEndpointURL, "https://", "", -1)
+	endpoint = strings.Replace(endpoint, "http://", "", -1)
+
+	if cfg.AWSBucketName == "" {
+		return nil, errors.New("bucket name is empty")
+	}
+
+	var awsCredProviders = []credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.FileAWSCredentials{},
+		&credentials.IAM{
+			Client: &http.Client{
+				Timeout: time.Second * 5,
+			},
+		},
+		&credentials.EnvMinio{},
+	}
+
+	if cfg.AWSAccessKeyID != "" && cfg.AWSSecretAccessKey != "" {
+		awsCredProviders = []credentials.Provider{
+			&credentials.Static{
+				Value: credentials.Value{
+					AccessKeyID:     cfg.AWSAccessKeyID,
+					SecretAccessKey: cfg.AWSSecretAccessKey,
+				},
+			},
+		}
+	}
+	creds := credentials.NewChainCredentials(awsCredProviders)
+
+	minioOptions := minio.Options{
+		Creds:  creds,
+		Secure: cfg.AWSUseHTTPS,
+		Region: cfg.AWSRegion,
+	}
+
+	// Init object storage (S3 compatible) client
+	client, err := minio.New(endpoint, &minioOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Init PreSigned URL expiration time
+	if cfg.AWSPresignedExpires == "" {
+		cfg.AWSPresignedExpires = "30m" // defaults to 30 minutes
+	}
+	expires, err := time.ParseDuration(cfg.AWSPresignedExpires)
+	if nil != err {
+		return nil, err
+	}
+
+	return &MinioStore{client: client, bucket: cfg.AWSBucketName, expires: expires}, nil
+}
+
+// Put uploads the object to the bucket
+func (s *MinioStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+// Get returns the object from the bucket
+func (s *MinioStore) Get(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	stat, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, ObjectInfo{}, minioError(err)
+	}
+
+	return obj, minioObjectInfo(stat), nil
+}
+
+// Stat returns the object info from the bucket
+func (s *MinioStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	stat, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, minioError(err)
+	}
+	return minioObjectInfo(stat), nil
+}
+
+// List returns the objects of the bucket with keys starting with prefix
+func (s *MinioStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, minioObjectInfo(object))
+	}
+	return objects, nil
+}
+
+// Delete removes the object from the bucket
+func (s *MinioStore) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+// PresignedPutURL returns a signed URL that allows to upload a single file
+func (s *MinioStore) PresignedPutURL(ctx context.Context, key string) (*url.URL, error) {
+	return s.client.PresignedPutObject(ctx, s.bucket, key, s.expires)
+}
+
+func minioObjectInfo(info minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+		ETag:         info.ETag,
+	}
+}
+
+func minioError(err error) error {
+	if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMinioConfig(endpoint string) Config {
+	return Config{
+		Storage:            StorageS3,
+		AWSAccessKeyID:     "access-key-id",
+		AWSSecretAccessKey: "secret-access-key",
+		AWSRegion:          "region",
+		AWSEndpointURL:     endpoint,
+		AWSBucketName:      "some-bucket",
+	}
+}
+
+func TestNewMinioStore(t *testing.T) {
+	type args struct {
+		cfg Config
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Test with empty bucket name",
+			args: args{
+				cfg: Config{
+					AWSAccessKeyID:     "access-key-id",
+					AWSSecretAccessKey: "secret-access-key",
+					AWSRegion:          "region",
+					AWSEndpointURL:     "localhost:80",
+					AWSBucketName:      "",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test with correct input data",
+			args: args{
+				cfg: newTestMinioConfig("localhost:80"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test with endpoint schema - avoid dial tcp: too many colons in address",
+			args: args{
+				cfg: newTestMinioConfig("https://localhost:80"),
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewMinioStore(tt.args.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMinioStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				assert.Nil(t, store)
+			}
+		})
+	}
+}
+
+func TestMinioStorePresignedPutURL(t *testing.T) {
+	store, err := NewMinioStore(newTestMinioConfig("localhost:80"))
+	require.NoError(t, err)
+
+	loadTestName := "fake-loadtest"
+	url, err := store.PresignedPutURL(context.Background(), loadTestName)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, url)
+	assert.Contains(t, url.String(), loadTestName)
+}
+
+func TestNewStore(t *testing.T) {
+	store, err := NewStore(newTestMinioConfig("localhost:80"))
+	require.NoError(t, err)
+	assert.IsType(t, &MinioStore{}, store)
+
+	store, err = NewStore(Config{Storage: StorageFileSystem, StoragePath: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &FileSystemStore{}, store)
+
+	_, err = NewStore(Config{Storage: "gcs"})
+	assert.ErrorIs(t, err, ErrUnknownStorage)
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+// ErrReportNotFound is returned when the loadtest report was not uploaded
+var ErrReportNotFound = errors.New("report not found")
+
+// Reader reads loadtest reports from the report store
+type Reader struct {
+	store ReportStore
+}
+
+// NewReader returns a reader using the report store
+func NewReader(store ReportStore) *Reader {
+	return &Reader{store: store}
+}
+
+// Read returns the report uploaded by the loadtest, caller must close it
+func (r *Reader) Read(ctx context.Context, loadTestName string) (io.ReadCloser, error) {
+	obj, _, err := r.store.Get(ctx, loadTestName)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Summary returns the results of the loadtest report. The summary stored when the report was persisted is read first,
+// reports uploaded without it are read in any of the report formats served by ShowHandler.
+// ErrReportNotFound is returned when the report was not uploaded
+func (r *Reader) Summary(ctx context.Context, loadTestType loadTestV1.LoadTestType, loadTestName string) (results.Summary, error) {
+	summary, err := getSummary(ctx, r.store, loadTestName)
+	if !errors.Is(err, ErrSummaryNotFound) {
+		return summary, err
+	}
+
+	summary, err = readSummary(ctx, r.store, loadTestType, loadTestName)
+	if errors.Is(err, ErrObjectNotFound) {
+		return results.Summary{}, ErrReportNotFound
+	}
+	return summary, err
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+func TestReader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/some-bucket/loadtest-name" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("ETag", `"etag"`)
+		_, _ = w.Write([]byte(`{"metrics":{}}`))
+	}))
+	defer srv.Close()
+
+	store, err := NewMinioStore(newTestMinioConfig(srv.URL))
+	require.NoError(t, err)
+	reader := NewReader(store)
+
+	rc, err := reader.Read(context.Background(), "loadtest-name")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, `{"metrics":{}}`, string(content))
+
+	_, err = reader.Read(context.Background(), "missing-loadtest")
+	assert.ErrorIs(t, err, ErrReportNotFound)
+}
+
+func TestReaderSummary(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+	reader := NewReader(store)
+
+	// zstd report uploaded without stored summary, e.g. directly to the storage
+	var compressed bytes.Buffer
+	zw, err := zstd.NewWriter(&compressed)
+	require.NoError(t, err)
+	_, err = zw.Write([]byte(`{"metrics":{"http_req_duration":{"values":{"p(95)":420}},"http_reqs":{"values":{"count":1200,"rate":120}}}}`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, store.Put(ctx, "zstd-report", &compressed, -1, ""))
+
+	summary, err := reader.Summary(ctx, loadTestV1.LoadTestTypeK6, "zstd-report")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1200), summary.Requests)
+
+	// stored summary is read instead of the report
+	stored, err := json.Marshal(results.Summary{Stats: results.Stats{Requests: 42}})
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, "html-report", strings.NewReader("<html/>"), -1, ""))
+	require.NoError(t, store.Put(ctx, SummaryObjectName("html-report"), bytes.NewReader(stored), -1, ""))
+
+	summary, err = reader.Summary(ctx, loadTestV1.LoadTestTypeK6, "html-report")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), summary.Requests)
+
+	require.NoError(t, store.Delete(ctx, SummaryObjectName("html-report")))
+	_, err = reader.Summary(ctx, loadTestV1.LoadTestTypeK6, "html-report")
+	assert.ErrorIs(t, err, ErrResultsUnreadable)
+
+	_, err = reader.Summary(ctx, loadTestV1.LoadTestTypeK6, "missing-report")
+	assert.ErrorIs(t, err, ErrReportNotFound)
+}
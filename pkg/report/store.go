@@ -0,0 +1,72 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+const (
+	// StorageS3 stores reports in an S3 compatible bucket
+	StorageS3 = "s3"
+	// StorageFileSystem stores reports in a local directory, e.g. a mounted PVC
+	StorageFileSystem = "filesystem"
+)
+
+var (
+	// ErrObjectNotFound is returned when the object does not exist in the store
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrUnknownStorage is returned when the configured storage driver does not exist
+	ErrUnknownStorage = errors.New("unknown report storage")
+)
+
+// ObjectInfo describes an object of the report store
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+	ETag         string
+}
+
+// ReportStore stores loadtest reports and archived loadtest artifacts
+type ReportStore interface {
+	// Put stores the object, size is -1 when it is not known in advance
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns the object content and info, ErrObjectNotFound when it does not exist. Caller must close the content
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error)
+	// Stat returns the object info, ErrObjectNotFound when it does not exist
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// List returns all the objects with keys starting with prefix
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Delete removes the object, it is not an error when it does not exist
+	Delete(ctx context.Context, key string) error
+}
+
+// PreSigner is implemented by stores that can issue upload URLs, so reports are streamed to the store directly
+type PreSigner interface {
+	PresignedPutURL(ctx context.Context, key string) (*url.URL, error)
+}
+
+// NewStore returns the report store configured by cfg.Storage
+func NewStore(cfg Config) (ReportStore, error) {
+	switch cfg.Storage {
+	case StorageS3:
+		store, err := NewMinioStore(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case StorageFileSystem:
+		store, err := NewFileSystemStore(cfg.StoragePath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownStorage, cfg.Storage)
+}
@@ -0,0 +1,214 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	khttp "github.com/hellofresh/kangal/pkg/core/http"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+// SummaryPrefix is the bucket prefix under which normalized results of loadtest reports are stored
+const SummaryPrefix = "summary"
+
+var (
+	// ErrSummaryNotFound is returned when the results of the loadtest report were not summarized
+	ErrSummaryNotFound = errors.New("summary not found, results of the load test report could not be read")
+	// ErrResultsUnreadable is returned when the report was read but its results could not be, reading it again does not help
+	ErrResultsUnreadable = errors.New("could not read results from report")
+)
+
+// SummaryObjectName returns the bucket object name of the loadtest results summary
+func SummaryObjectName(loadTestName string) string {
+	return path.Join(SummaryPrefix, loadTestName+".json")
+}
+
+// storeSummary reads the results of the persisted loadtest report and stores them next to the report.
+// Summary of the previous report is removed when the results can not be read
+func storeSummary(ctx context.Context, store ReportStore, loadTestType loadTestV1.LoadTestType, loadTestName string) error {
+	if !results.Supported(loadTestType) {
+		return fmt.Errorf("%w %s", results.ErrUnsupportedType, loadTestType)
+	}
+
+	summary, err := readSummary(ctx, store, loadTestType, loadTestName)
+	if err != nil {
+		if deleteErr := store.Delete(ctx, SummaryObjectName(loadTestName)); deleteErr != nil {
+			return fmt.Errorf("could not remove previous summary: %w", deleteErr)
+		}
+		return err
+	}
+
+	content, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, SummaryObjectName(loadTestName), bytes.NewReader(content), int64(len(content)), "application/json")
+}
+
+// readSummary parses the results file of the report, reports uploaded as archives or as separate objects
+// are searched for the results file of the loadtest type
+func readSummary(ctx context.Context, store ReportStore, loadTestType loadTestV1.LoadTestType, loadTestName string) (results.Summary, error) {
+	obj, info, err := store.Get(ctx, loadTestName)
+	if errors.Is(err, ErrObjectNotFound) {
+		return readObjectsSummary(ctx, store, loadTestType, loadTestName)
+	}
+	if err != nil {
+		return results.Summary{}, err
+	}
+	defer obj.Close()
+
+	contentType, err := reportContentType(obj, info)
+	if err != nil {
+		return results.Summary{}, unreadable(err)
+	}
+	if contentType == "" {
+		return parseResults(loadTestType, obj)
+	}
+
+	index, err := buildArchiveIndex(contentType, obj, info.Size, info)
+	if err != nil {
+		return results.Summary{}, unreadable(err)
+	}
+	if index.single {
+		return parseResults(loadTestType, bytes.NewReader(index.data))
+	}
+
+	names := make([]string, 0, len(index.files))
+	for name := range index.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fsys := newArchiveFileSystem(index, obj)
+	for _, name := range names {
+		if !results.Matches(loadTestType, name) {
+			continue
+		}
+
+		f, err := fsys.Open(name)
+		if err != nil {
+			return results.Summary{}, unreadable(err)
+		}
+		defer f.Close()
+		return parseResults(loadTestType, f)
+	}
+
+	return results.Summary{}, unreadable(results.ErrResultsNotFound)
+}
+
+func readObjectsSummary(ctx context.Context, store ReportStore, loadTestType loadTestV1.LoadTestType, loadTestName string) (results.Summary, error) {
+	prefix := loadTestName + pathSeparator
+	objects, err := store.List(ctx, prefix)
+	if err != nil {
+		return results.Summary{}, err
+	}
+	if len(objects) == 0 {
+		return results.Summary{}, ErrReportNotFound
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	for _, object := range objects {
+		if !results.Matches(loadTestType, strings.TrimPrefix(object.Key, prefix)) {
+			continue
+		}
+
+		obj, _, err := store.Get(ctx, object.Key)
+		if err != nil {
+			return results.Summary{}, err
+		}
+		defer obj.Close()
+		return parseResults(loadTestType, obj)
+	}
+
+	return results.Summary{}, unreadable(results.ErrResultsNotFound)
+}
+
+// parseResults parses the results file, parse errors are ErrResultsUnreadable
+func parseResults(loadTestType loadTestV1.LoadTestType, r io.Reader) (results.Summary, error) {
+	summary, err := results.Parse(loadTestType, r)
+	if err != nil {
+		return results.Summary{}, unreadable(err)
+	}
+	return summary, nil
+}
+
+func unreadable(err error) error {
+	return fmt.Errorf("%w: %w", ErrResultsUnreadable, err)
+}
+
+// SummaryResult is the loadtest results summary
+type SummaryResult struct {
+	results.Summary
+	// Baseline is the comparison against the baseline loadtest results, set once a loadtest comparing to baseline has finished
+	Baseline *CompareResult `json:"baseline,omitempty"`
+}
+
+// SummaryHandler method returns the normalized results of the loadtest report with the comparison against its baseline.
+// Responses are validated by ETag and Last-Modified of both stored objects, so conditional requests are answered
+// with 304 until the report is persisted again or the loadtest is compared to its baseline
+func SummaryHandler(store ReportStore) func(w http.ResponseWriter, r *http.Request) {
+	if store == nil {
+		panic("report store was not initialized")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		loadTestName := chi.URLParam(r, "id")
+
+		summary, summaryInfo, err := getSummaryObject(r.Context(), store, loadTestName)
+		if errors.Is(err, ErrSummaryNotFound) {
+			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, err.Error()))
+			return
+		}
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		baseline, baselineInfo, err := getBaselineObject(r.Context(), store, loadTestName)
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		content, err := json.Marshal(SummaryResult{Summary: summary, Baseline: baseline})
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		lastModified := summaryInfo.LastModified
+		if baselineInfo.LastModified.After(lastModified) {
+			lastModified = baselineInfo.LastModified
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if etag := summaryETag(summaryInfo, baselineInfo); etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		http.ServeContent(w, r, "", lastModified, bytes.NewReader(content))
+	}
+}
+
+// summaryETag combines ETags of the summary and its baseline comparison, empty when the store does not provide them
+func summaryETag(summaryInfo, baselineInfo ObjectInfo) string {
+	if summaryInfo.ETag == "" {
+		return ""
+	}
+	etag := strings.Trim(summaryInfo.ETag, `"`)
+	if baselineInfo.ETag != "" {
+		etag += "-" + strings.Trim(baselineInfo.ETag, `"`)
+	}
+	return fmt.Sprintf(`"%s"`, etag)
+}
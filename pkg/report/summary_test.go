@@ -0,0 +1,121 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	kk8s "github.com/hellofresh/kangal/pkg/kubernetes"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+	"github.com/hellofresh/kangal/pkg/results"
+)
+
+const jmeterStatistics = `{"Total": {"sampleCount": 100, "errorCount": 5, "pct2ResTime": 250, "throughput": 10},
+"GET /": {"sampleCount": 100, "errorCount": 5, "pct2ResTime": 250, "throughput": 10}}`
+
+func TestReadSummary(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	files := map[string]string{"index.html": "<html/>", "statistics.json": jmeterStatistics}
+	require.NoError(t, store.Put(ctx, "tar-report", bytes.NewReader(newTestTar(t, files)), -1, ""))
+	require.NoError(t, store.Put(ctx, "zip-report", bytes.NewReader(newTestZip(t, files, zip.Deflate)), -1, ""))
+	require.NoError(t, store.Put(ctx, "dir-report/statistics.json", strings.NewReader(jmeterStatistics), -1, ""))
+	require.NoError(t, store.Put(ctx, "html-report", strings.NewReader("<html/>"), -1, ""))
+
+	for _, name := range []string{"tar-report", "zip-report", "dir-report"} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := readSummary(ctx, store, loadTestV1.LoadTestTypeJMeter, name)
+			require.NoError(t, err)
+			assert.Equal(t, int64(100), summary.Requests)
+			assert.Equal(t, int64(5), summary.Errors)
+			require.Len(t, summary.Endpoints, 1)
+			assert.Equal(t, "GET /", summary.Endpoints[0].Name)
+		})
+	}
+
+	_, err = readSummary(ctx, store, loadTestV1.LoadTestTypeJMeter, "html-report")
+	assert.Error(t, err)
+	_, err = readSummary(ctx, store, loadTestV1.LoadTestTypeJMeter, "missing-report")
+	assert.ErrorIs(t, err, ErrReportNotFound)
+}
+
+func TestSummaryHandler(t *testing.T) {
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	kangalFakeClientSet := fake.NewSimpleClientset(&loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeJMeter},
+	})
+	kangalKubeClient := kk8s.NewClient(kangalFakeClientSet.KangalV1().LoadTests(), k8sfake.NewSimpleClientset(), zap.NewNop())
+
+	handler := chi.NewRouter()
+	handler.Put("/load-test/{id}/report", PersistHandler(store, kangalKubeClient, zap.NewNop()))
+	handler.Get("/load-test/{id}/summary", SummaryHandler(store))
+
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, path, bytes.NewReader(body)))
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/load-test/loadtest-name/summary", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// summary is computed when the report is persisted
+	rr = serve(http.MethodPut, "/load-test/loadtest-name/report", newTestTar(t, map[string]string{"./statistics.json": jmeterStatistics}))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(http.MethodGet, "/load-test/loadtest-name/summary", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var summary results.Summary
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summary))
+	assert.Equal(t, int64(100), summary.Requests)
+	assert.InDelta(t, 0.05, summary.ErrorRate, 1e-9)
+
+	// unchanged summary is validated by conditional requests
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+	req := httptest.NewRequest(http.MethodGet, "/load-test/loadtest-name/summary", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	// baseline comparison changes the summary
+	comparison, err := json.Marshal(CompareResult{Base: "baseline-name", Candidate: "loadtest-name"})
+	require.NoError(t, err)
+	require.NoError(t, store.Put(context.Background(), BaselineObjectName("loadtest-name"), bytes.NewReader(comparison), -1, "application/json"))
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+	assert.Contains(t, rr.Body.String(), `"base":"baseline-name"`)
+
+	// report without results replaces the previous summary
+	rr = serve(http.MethodPut, "/load-test/loadtest-name/report", []byte("<html/>"))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(http.MethodGet, "/load-test/loadtest-name/summary", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
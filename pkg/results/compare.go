@@ -0,0 +1,132 @@
+package results
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidTolerance is returned when a comparison tolerance is negative
+var ErrInvalidTolerance = errors.New("tolerance can not be negative")
+
+// Tolerances are the changes between the base and the candidate results which are not regressions
+type Tolerances struct {
+	// Latency is the allowed relative latency percentile increase, e.g. 0.1 is 10%
+	Latency float64 `json:"latency" envconfig:"COMPARE_LATENCY_TOLERANCE" default:"0.1"`
+	// Throughput is the allowed relative throughput decrease
+	Throughput float64 `json:"throughput" envconfig:"COMPARE_THROUGHPUT_TOLERANCE" default:"0.1"`
+	// ErrorRate is the allowed absolute error rate increase, e.g. 0.01 is 1 percentage point
+	ErrorRate float64 `json:"errorRate" envconfig:"COMPARE_ERROR_RATE_TOLERANCE" default:"0.01"`
+}
+
+// Validate returns an error when a tolerance is negative
+func (t Tolerances) Validate() error {
+	for name, tolerance := range map[string]float64{"latency": t.Latency, "throughput": t.Throughput, "errorRate": t.ErrorRate} {
+		if tolerance < 0 {
+			return fmt.Errorf("%s %w", name, ErrInvalidTolerance)
+		}
+	}
+	return nil
+}
+
+// Delta is the change of a measured value between the base and the candidate results, latencies are in nanoseconds
+type Delta struct {
+	Base      float64 `json:"base"`
+	Candidate float64 `json:"candidate"`
+	// Change is the candidate value minus the base value
+	Change float64 `json:"change"`
+	// Relative is the change relative to the base value, zero when the base value is zero
+	Relative float64 `json:"relative"`
+	// Regressed is true when the change is worse than the tolerance
+	Regressed bool `json:"regressed"`
+}
+
+func newDelta(base, candidate float64) Delta {
+	delta := Delta{Base: base, Candidate: candidate, Change: candidate - base}
+	if base != 0 {
+		delta.Relative = delta.Change / base
+	}
+	return delta
+}
+
+// StatsComparison is the comparison of results of the same set of requests
+type StatsComparison struct {
+	Throughput Delta `json:"throughput"`
+	ErrorRate  Delta `json:"errorRate"`
+	P50        Delta `json:"p50"`
+	P95        Delta `json:"p95"`
+	P99        Delta `json:"p99"`
+	// Regressed is true when at least one of the values regressed
+	Regressed bool `json:"regressed"`
+}
+
+// EndpointComparison is the comparison of results of an endpoint present in both results
+type EndpointComparison struct {
+	Name string `json:"name"`
+	StatsComparison
+}
+
+// Comparison is the comparison of candidate results against base results
+type Comparison struct {
+	Total     StatsComparison      `json:"total"`
+	Endpoints []EndpointComparison `json:"endpoints,omitempty"`
+	// MissingEndpoints are the endpoints of the base results the candidate results do not have
+	MissingEndpoints []string `json:"missingEndpoints,omitempty"`
+	// Regressed is true when the total or at least one endpoint results regressed
+	Regressed bool `json:"regressed"`
+}
+
+// Compare computes the changes of candidate results against base results. Latency percentiles and throughput
+// the backend does not report are not compared, a throughput dropping to zero is a regression. Endpoints new in
+// the candidate results are skipped, endpoints missing in the candidate results are listed
+func Compare(base, candidate Summary, tolerances Tolerances) Comparison {
+	comparison := Comparison{Total: compareStats(base.Stats, candidate.Stats, tolerances)}
+	comparison.Regressed = comparison.Total.Regressed
+
+	baseEndpoints := make(map[string]Stats, len(base.Endpoints))
+	for _, endpoint := range base.Endpoints {
+		baseEndpoints[endpoint.Name] = endpoint.Stats
+	}
+	candidateEndpoints := make(map[string]bool, len(candidate.Endpoints))
+	for _, endpoint := range candidate.Endpoints {
+		candidateEndpoints[endpoint.Name] = true
+	}
+	for _, endpoint := range base.Endpoints {
+		if !candidateEndpoints[endpoint.Name] {
+			comparison.MissingEndpoints = append(comparison.MissingEndpoints, endpoint.Name)
+		}
+	}
+
+	for _, endpoint := range candidate.Endpoints {
+		baseStats, ok := baseEndpoints[endpoint.Name]
+		if !ok {
+			continue
+		}
+
+		stats := compareStats(baseStats, endpoint.Stats, tolerances)
+		comparison.Endpoints = append(comparison.Endpoints, EndpointComparison{Name: endpoint.Name, StatsComparison: stats})
+		comparison.Regressed = comparison.Regressed || stats.Regressed
+	}
+
+	return comparison
+}
+
+func compareStats(base, candidate Stats, tolerances Tolerances) StatsComparison {
+	comparison := StatsComparison{
+		Throughput: newDelta(base.Throughput, candidate.Throughput),
+		ErrorRate:  newDelta(base.ErrorRate, candidate.ErrorRate),
+		P50:        newDelta(float64(base.Latency.P50), float64(candidate.Latency.P50)),
+		P95:        newDelta(float64(base.Latency.P95), float64(candidate.Latency.P95)),
+		P99:        newDelta(float64(base.Latency.P99), float64(candidate.Latency.P99)),
+	}
+
+	comparison.Throughput.Regressed = base.Throughput > 0 && comparison.Throughput.Relative < -tolerances.Throughput
+	comparison.ErrorRate.Regressed = comparison.ErrorRate.Change > tolerances.ErrorRate
+	for _, percentile := range []*Delta{&comparison.P50, &comparison.P95, &comparison.P99} {
+		percentile.Regressed = percentile.Base > 0 && percentile.Candidate > 0 &&
+			percentile.Relative > tolerances.Latency
+	}
+
+	comparison.Regressed = comparison.Throughput.Regressed || comparison.ErrorRate.Regressed ||
+		comparison.P50.Regressed || comparison.P95.Regressed || comparison.P99.Regressed
+	return comparison
+}
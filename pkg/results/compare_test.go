@@ -0,0 +1,94 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTolerancesValidate(t *testing.T) {
+	assert.NoError(t, Tolerances{}.Validate())
+	assert.NoError(t, Tolerances{Latency: 0.1, Throughput: 0.1, ErrorRate: 0.01}.Validate())
+	assert.ErrorIs(t, Tolerances{Throughput: -0.1}.Validate(), ErrInvalidTolerance)
+}
+
+func TestCompare(t *testing.T) {
+	tolerances := Tolerances{Latency: 0.1, Throughput: 0.1, ErrorRate: 0.01}
+	stats := func(throughput, errorRate float64, p95 time.Duration) Stats {
+		return Stats{Throughput: throughput, ErrorRate: errorRate, Latency: Latency{P50: p95 / 2, P95: p95, P99: p95 * 2}}
+	}
+
+	base := Summary{
+		Stats: stats(100, 0.01, 200*time.Millisecond),
+		Endpoints: []EndpointStats{
+			{Name: "GET /", Stats: stats(50, 0.01, 100*time.Millisecond)},
+			{Name: "POST /login", Stats: stats(50, 0.01, 300*time.Millisecond)},
+		},
+	}
+
+	t.Run("within tolerances", func(t *testing.T) {
+		candidate := Summary{
+			Stats: stats(95, 0.015, 210*time.Millisecond),
+			Endpoints: []EndpointStats{
+				{Name: "GET /", Stats: stats(50, 0.01, 100*time.Millisecond)},
+				{Name: "GET /new", Stats: stats(10, 0.5, time.Second)},
+			},
+		}
+
+		comparison := Compare(base, candidate, tolerances)
+		assert.False(t, comparison.Regressed)
+		assert.Equal(t, -5.0, comparison.Total.Throughput.Change)
+		assert.InDelta(t, -0.05, comparison.Total.Throughput.Relative, 1e-9)
+		assert.InDelta(t, 0.05, comparison.Total.P95.Relative, 1e-9)
+		// endpoints missing in the base results are not compared
+		assert.Len(t, comparison.Endpoints, 1)
+		assert.Equal(t, "GET /", comparison.Endpoints[0].Name)
+		assert.Equal(t, []string{"POST /login"}, comparison.MissingEndpoints)
+	})
+
+	t.Run("regressions", func(t *testing.T) {
+		candidate := Summary{
+			Stats: stats(80, 0.05, 200*time.Millisecond),
+			Endpoints: []EndpointStats{
+				{Name: "GET /", Stats: stats(50, 0.01, 100*time.Millisecond)},
+				{Name: "POST /login", Stats: stats(50, 0.01, 400*time.Millisecond)},
+			},
+		}
+
+		comparison := Compare(base, candidate, tolerances)
+		assert.True(t, comparison.Regressed)
+		assert.True(t, comparison.Total.Regressed)
+		assert.True(t, comparison.Total.Throughput.Regressed)
+		assert.True(t, comparison.Total.ErrorRate.Regressed)
+		assert.False(t, comparison.Total.P95.Regressed)
+		assert.False(t, comparison.Endpoints[0].Regressed)
+		assert.True(t, comparison.Endpoints[1].Regressed)
+		assert.True(t, comparison.Endpoints[1].P50.Regressed)
+		assert.True(t, comparison.Endpoints[1].P95.Regressed)
+		assert.True(t, comparison.Endpoints[1].P99.Regressed)
+	})
+
+	t.Run("missing values", func(t *testing.T) {
+		// ghz does not report throughput per endpoint and some backends do not report all percentiles
+		base := Summary{Stats: Stats{ErrorRate: 0.01, Latency: Latency{P50: time.Second, P95: time.Second}}}
+		candidate := Summary{Stats: Stats{Throughput: 10, ErrorRate: 0.01, Latency: Latency{P95: 2 * time.Second}}}
+
+		comparison := Compare(base, candidate, tolerances)
+		assert.True(t, comparison.Regressed)
+		assert.False(t, comparison.Total.Throughput.Regressed)
+		assert.False(t, comparison.Total.P50.Regressed)
+		assert.True(t, comparison.Total.P95.Regressed)
+		assert.Empty(t, comparison.MissingEndpoints)
+	})
+
+	t.Run("throughput dropped to zero", func(t *testing.T) {
+		candidate := Summary{Stats: stats(0, 0.01, 200*time.Millisecond)}
+
+		comparison := Compare(base, candidate, tolerances)
+		assert.True(t, comparison.Regressed)
+		assert.True(t, comparison.Total.Throughput.Regressed)
+		assert.Equal(t, -1.0, comparison.Total.Throughput.Relative)
+		assert.Equal(t, []string{"GET /", "POST /login"}, comparison.MissingEndpoints)
+	})
+}
@@ -0,0 +1,302 @@
+package results
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMissingTotal is returned when JMeter statistics do not have the Total transaction
+	ErrMissingTotal = errors.New("statistics do not contain Total transaction")
+	// ErrMissingAggregated is returned when Locust stats do not have the Aggregated row
+	ErrMissingAggregated = errors.New("stats do not contain Aggregated row")
+	// ErrMissingMetric is returned when k6 summary does not have the request duration metric
+	ErrMissingMetric = errors.New("summary does not contain http_req_duration or grpc_req_duration metric")
+	// ErrMissingPercentile is returned when ghz report latency distribution does not have the 95th percentile
+	ErrMissingPercentile = errors.New("latency distribution does not contain 95th percentile")
+)
+
+// milliseconds converts a response time in milliseconds to duration
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// jmeterStatistics is the statistics.json of JMeter HTML dashboard, pct1ResTime, pct2ResTime and pct3ResTime are
+// the 90th, 95th and 99th percentiles unless jmeter.reportgenerator.statistic_window.percentile* were changed
+type jmeterStatistics map[string]struct {
+	SampleCount   float64 `json:"sampleCount"`
+	ErrorCount    float64 `json:"errorCount"`
+	MeanResTime   float64 `json:"meanResTime"`
+	MedianResTime float64 `json:"medianResTime"`
+	MinResTime    float64 `json:"minResTime"`
+	MaxResTime    float64 `json:"maxResTime"`
+	Pct1ResTime   float64 `json:"pct1ResTime"`
+	Pct2ResTime   float64 `json:"pct2ResTime"`
+	Pct3ResTime   float64 `json:"pct3ResTime"`
+	Throughput    float64 `json:"throughput"`
+}
+
+// jmeterTotal is the statistics.json transaction with results of all requests
+const jmeterTotal = "Total"
+
+func parseJMeterStatistics(r io.Reader) (Summary, error) {
+	var statistics jmeterStatistics
+	if err := json.NewDecoder(r).Decode(&statistics); err != nil {
+		return Summary{}, fmt.Errorf("could not decode JMeter statistics: %w", err)
+	}
+
+	if _, ok := statistics[jmeterTotal]; !ok {
+		return Summary{}, ErrMissingTotal
+	}
+
+	var summary Summary
+	for name, transaction := range statistics {
+		stats := newStats(int64(transaction.SampleCount), int64(transaction.ErrorCount), transaction.Throughput, Latency{
+			Mean: milliseconds(transaction.MeanResTime),
+			Min:  milliseconds(transaction.MinResTime),
+			Max:  milliseconds(transaction.MaxResTime),
+			P50:  milliseconds(transaction.MedianResTime),
+			P90:  milliseconds(transaction.Pct1ResTime),
+			P95:  milliseconds(transaction.Pct2ResTime),
+			P99:  milliseconds(transaction.Pct3ResTime),
+		})
+
+		if name == jmeterTotal {
+			summary.Stats = stats
+			continue
+		}
+		summary.Endpoints = append(summary.Endpoints, EndpointStats{Name: name, Stats: stats})
+	}
+	sortEndpoints(summary.Endpoints)
+
+	return summary, nil
+}
+
+// locustAggregated is the name of Locust stats row with results of all requests
+const locustAggregated = "Aggregated"
+
+// parseLocustStats reads Locust --csv stats file, the Aggregated row has total results and other rows
+// have results of the requests with the same method and name
+func parseLocustStats(r io.Reader) (Summary, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return Summary{}, fmt.Errorf("could not read Locust stats: %w", err)
+	}
+	if len(records) < 2 {
+		return Summary{}, ErrMissingAggregated
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+
+	var (
+		summary    Summary
+		aggregated bool
+	)
+	for _, record := range records[1:] {
+		if len(record) != len(records[0]) {
+			continue
+		}
+
+		value := func(column string) float64 {
+			i, ok := columns[column]
+			if !ok {
+				return 0
+			}
+			// percentiles are N/A when there were no requests
+			v, _ := strconv.ParseFloat(record[i], 64)
+			return v
+		}
+
+		stats := newStats(int64(value("Request Count")), int64(value("Failure Count")), value("Requests/s"), Latency{
+			Mean: milliseconds(value("Average Response Time")),
+			Min:  milliseconds(value("Min Response Time")),
+			Max:  milliseconds(value("Max Response Time")),
+			P50:  milliseconds(value("50%")),
+			P90:  milliseconds(value("90%")),
+			P95:  milliseconds(value("95%")),
+			P99:  milliseconds(value("99%")),
+		})
+
+		name := record[columns["Name"]]
+		if name == locustAggregated {
+			summary.Stats, aggregated = stats, true
+			continue
+		}
+		if i, ok := columns["Type"]; ok && record[i] != "" {
+			name = record[i] + " " + name
+		}
+		summary.Endpoints = append(summary.Endpoints, EndpointStats{Name: name, Stats: stats})
+	}
+
+	if !aggregated {
+		return Summary{}, ErrMissingAggregated
+	}
+	sortEndpoints(summary.Endpoints)
+
+	return summary, nil
+}
+
+// k6Metric is a metric of k6 summary, values are nested in handleSummary data and flat in --summary-export
+type k6Metric struct {
+	Values map[string]float64 `json:"values"`
+}
+
+func (m *k6Metric) UnmarshalJSON(b []byte) error {
+	var nested struct {
+		Values map[string]float64 `json:"values"`
+	}
+	if err := json.Unmarshal(b, &nested); err == nil && nested.Values != nil {
+		m.Values = nested.Values
+		return nil
+	}
+
+	// --summary-export has other non numeric fields, e.g. thresholds
+	var flat map[string]json.RawMessage
+	if err := json.Unmarshal(b, &flat); err != nil {
+		return err
+	}
+	m.Values = make(map[string]float64, len(flat))
+	for name, raw := range flat {
+		var v float64
+		if err := json.Unmarshal(raw, &v); err == nil {
+			m.Values[name] = v
+		}
+	}
+	return nil
+}
+
+// k6Metrics are the metrics of k6 summary by name, sub-metrics are named by their metric and tags,
+// e.g. http_req_duration{name:login}
+type k6Metrics map[string]k6Metric
+
+// stats returns results of the requests measured by the duration metric, sub-metric tags select the requests
+func (m k6Metrics) stats(durationMetric, tags string) Stats {
+	duration := m[durationMetric+tags]
+	reqs := m["http_reqs"+tags]
+	failed, hasFailed := m["http_req_failed"+tags]
+
+	requests := int64(reqs.Values["count"])
+	// rate metric passes are the failed requests
+	failures, ok := failed.Values["passes"]
+	if !ok && hasFailed {
+		rate, ok := failed.Values["rate"]
+		if !ok {
+			// --summary-export stores rate metrics value as value
+			rate = failed.Values["value"]
+		}
+		failures = math.Round(rate * float64(requests))
+	}
+
+	return newStats(requests, int64(failures), reqs.Values["rate"], Latency{
+		Mean: milliseconds(duration.Values["avg"]),
+		Min:  milliseconds(duration.Values["min"]),
+		Max:  milliseconds(duration.Values["max"]),
+		P50:  milliseconds(duration.Values["med"]),
+		P90:  milliseconds(duration.Values["p(90)"]),
+		P95:  milliseconds(duration.Values["p(95)"]),
+		P99:  milliseconds(duration.Values["p(99)"]),
+	})
+}
+
+// parseK6Summary reads k6 end of test summary, written by handleSummary or --summary-export.
+// Sub-metrics of the request duration, e.g. defined by thresholds on tagged requests, are the endpoints
+func parseK6Summary(r io.Reader) (Summary, error) {
+	var summary struct {
+		Metrics k6Metrics `json:"metrics"`
+	}
+	if err := json.NewDecoder(r).Decode(&summary); err != nil {
+		return Summary{}, fmt.Errorf("could not decode k6 summary: %w", err)
+	}
+
+	durationMetric := "http_req_duration"
+	if _, ok := summary.Metrics[durationMetric]; !ok {
+		durationMetric = "grpc_req_duration"
+	}
+	if _, ok := summary.Metrics[durationMetric]; !ok {
+		return Summary{}, ErrMissingMetric
+	}
+
+	result := Summary{Stats: summary.Metrics.stats(durationMetric, "")}
+
+	// rate metric value is the error rate even when the number of failed requests is not known
+	failed := summary.Metrics["http_req_failed"].Values
+	if rate, ok := failed["rate"]; ok {
+		result.ErrorRate = rate
+	} else if rate, ok := failed["value"]; ok {
+		result.ErrorRate = rate
+	}
+
+	for name := range summary.Metrics {
+		tags, ok := strings.CutPrefix(name, durationMetric+"{")
+		if !ok || !strings.HasSuffix(tags, "}") {
+			continue
+		}
+		result.Endpoints = append(result.Endpoints, EndpointStats{
+			Name:  strings.TrimSuffix(tags, "}"),
+			Stats: summary.Metrics.stats(durationMetric, "{"+tags),
+		})
+	}
+	sortEndpoints(result.Endpoints)
+
+	return result, nil
+}
+
+// ghzReport is ghz JSON output, latencies are in nanoseconds
+type ghzReport struct {
+	Count               int64         `json:"count"`
+	RPS                 float64       `json:"rps"`
+	Average             time.Duration `json:"average"`
+	Fastest             time.Duration `json:"fastest"`
+	Slowest             time.Duration `json:"slowest"`
+	LatencyDistribution []struct {
+		Percentage int           `json:"percentage"`
+		Latency    time.Duration `json:"latency"`
+	} `json:"latencyDistribution"`
+	ErrorDistribution map[string]int64 `json:"errorDistribution"`
+}
+
+func parseGhzReport(r io.Reader) (Summary, error) {
+	var report ghzReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return Summary{}, fmt.Errorf("could not decode ghz report: %w", err)
+	}
+
+	latency := Latency{Mean: report.Average, Min: report.Fastest, Max: report.Slowest, P95: -1}
+	for _, l := range report.LatencyDistribution {
+		switch l.Percentage {
+		case 50:
+			latency.P50 = l.Latency
+		case 90:
+			latency.P90 = l.Latency
+		case 95:
+			latency.P95 = l.Latency
+		case 99:
+			latency.P99 = l.Latency
+		}
+	}
+	if latency.P95 < 0 {
+		return Summary{}, ErrMissingPercentile
+	}
+
+	var failed int64
+	for _, count := range report.ErrorDistribution {
+		failed += count
+	}
+
+	return Summary{Stats: newStats(report.Count, failed, report.RPS, latency)}, nil
+}
+
+func sortEndpoints(endpoints []EndpointStats) {
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Name < endpoints[j].Name })
+}
@@ -0,0 +1,160 @@
+package results
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var (
+	// ErrUnsupportedType is returned when results of the loadtest type can not be parsed
+	ErrUnsupportedType = errors.New("results are not supported for loadtest type")
+	// ErrResultsNotFound is returned when the report does not contain the results file of the loadtest type
+	ErrResultsNotFound = errors.New("results file not found in report")
+)
+
+// tarMagicOffset is the offset of "ustar" magic in tar header
+const tarMagicOffset = 257
+
+// Summary is the normalized results of a loadtest, regardless of the backend which produced them
+type Summary struct {
+	Stats
+	// Endpoints are the results per endpoint or label, e.g. JMeter transactions, Locust request names or k6 tagged
+	// sub-metrics, sorted by name. Backends testing a single endpoint, e.g. ghz, have only total results
+	Endpoints []EndpointStats `json:"endpoints,omitempty"`
+}
+
+// EndpointStats are the results of the requests of one endpoint or label
+type EndpointStats struct {
+	Name string `json:"name"`
+	Stats
+}
+
+// Stats are the results of a set of requests
+type Stats struct {
+	// Requests is the total number of requests sent
+	Requests int64 `json:"requests"`
+	// Errors is the number of failed requests
+	Errors int64 `json:"errors"`
+	// ErrorRate is the ratio of failed requests between 0 and 1
+	ErrorRate float64 `json:"errorRate"`
+	// Throughput is the number of requests per second
+	Throughput float64 `json:"throughput"`
+	Latency    Latency `json:"latency"`
+}
+
+// Latency is the response time distribution in nanoseconds, values the backend does not report are zero
+type Latency struct {
+	Mean time.Duration `json:"mean"`
+	Min  time.Duration `json:"min"`
+	Max  time.Duration `json:"max"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+}
+
+// newStats returns stats with error rate computed from the number of requests and errors
+func newStats(requests, errors int64, throughput float64, latency Latency) Stats {
+	stats := Stats{Requests: requests, Errors: errors, Throughput: throughput, Latency: latency}
+	if requests > 0 {
+		stats.ErrorRate = float64(errors) / float64(requests)
+	}
+	return stats
+}
+
+// parser reads results from a report file of a loadtest type
+type parser struct {
+	// match returns true when the archived file contains results
+	match func(name string) bool
+	parse func(r io.Reader) (Summary, error)
+}
+
+var parsers = map[loadTestV1.LoadTestType]parser{
+	loadTestV1.LoadTestTypeJMeter: {
+		match: func(name string) bool { return path.Base(name) == "statistics.json" },
+		parse: parseJMeterStatistics,
+	},
+	loadTestV1.LoadTestTypeLocust: {
+		match: func(name string) bool { return strings.HasSuffix(path.Base(name), "_stats.csv") },
+		parse: parseLocustStats,
+	},
+	loadTestV1.LoadTestTypeK6: {
+		match: func(name string) bool { return path.Ext(name) == ".json" },
+		parse: parseK6Summary,
+	},
+	loadTestV1.LoadTestTypeGhz: {
+		match: func(name string) bool { return path.Ext(name) == ".json" },
+		parse: parseGhzReport,
+	},
+}
+
+// Supported returns true when results of the loadtest type can be parsed
+func Supported(loadTestType loadTestV1.LoadTestType) bool {
+	_, ok := parsers[loadTestType]
+	return ok
+}
+
+// Matches returns true when the report file has the results of the loadtest type
+func Matches(loadTestType loadTestV1.LoadTestType, name string) bool {
+	p, ok := parsers[loadTestType]
+	return ok && p.match(name)
+}
+
+// Parse reads the results of a loadtest from its uploaded report. Report can be the results file itself,
+// e.g. k6 summary JSON, or a tar archive, optionally gzip compressed, containing the results file,
+// e.g. JMeter dashboard statistics.json or Locust report_stats.csv
+func Parse(loadTestType loadTestV1.LoadTestType, report io.Reader) (Summary, error) {
+	p, ok := parsers[loadTestType]
+	if !ok {
+		return Summary{}, fmt.Errorf("%w %s", ErrUnsupportedType, loadTestType)
+	}
+
+	br := bufio.NewReader(report)
+	if isGzip(br) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return Summary{}, fmt.Errorf("could not read gzip report: %w", err)
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	if !isTar(br) {
+		return p.parse(br)
+	}
+
+	tr := tar.NewReader(br)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return Summary{}, ErrResultsNotFound
+		}
+		if err != nil {
+			return Summary{}, fmt.Errorf("could not read tar report: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !p.match(header.Name) {
+			continue
+		}
+		return p.parse(tr)
+	}
+}
+
+func isGzip(br *bufio.Reader) bool {
+	magic, _ := br.Peek(2)
+	return bytes.Equal(magic, []byte{0x1f, 0x8b})
+}
+
+func isTar(br *bufio.Reader) bool {
+	header, _ := br.Peek(tarMagicOffset + 5)
+	return len(header) == tarMagicOffset+5 && string(header[tarMagicOffset:]) == "ustar"
+}
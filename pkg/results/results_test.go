@@ -0,0 +1,200 @@
+package results
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	jmeterStatisticsFixture = `{
+  "Total": {"transaction": "Total", "sampleCount": 2000, "errorCount": 20, "errorPct": 1.0, "meanResTime": 120.5,
+    "pct1ResTime": 200.0, "pct2ResTime": 250.5, "pct3ResTime": 400.0, "throughput": 33.3},
+  "GET /": {"transaction": "GET /", "sampleCount": 2000, "errorCount": 20, "pct2ResTime": 250.5, "throughput": 33.3}
+}`
+	locustStatsFixture = `Type,Name,Request Count,Failure Count,Median Response Time,Average Response Time,Min Response Time,Max Response Time,Average Content Size,Requests/s,Failures/s,50%,66%,75%,80%,90%,95%,98%,99%,99.9%,99.99%,100%
+GET,/,1000,10,42,50.2,10,900,1200,16.6,0.16,42,50,60,70,90,120,200,300,800,900,900
+,Aggregated,1000,10,42,50.2,10,900,1200,16.6,0.16,42,50,60,70,90,120,200,300,800,900,900
+`
+	k6SummaryFixture = `{"metrics": {
+  "http_req_duration": {"type": "trend", "contains": "time", "values": {"avg": 80.1, "p(90)": 150.2, "p(95)": 180.5}},
+  "http_req_failed": {"type": "rate", "contains": "default", "values": {"rate": 0.02, "passes": 20, "fails": 980}},
+  "http_reqs": {"type": "counter", "contains": "default", "values": {"count": 1000, "rate": 50.5}}
+}}`
+	k6SummaryExportFixture = `{"metrics": {
+  "http_req_duration": {"avg": 80.1, "p(90)": 150.2, "p(95)": 180.5, "thresholds": {"p(95)<500": false}},
+  "http_req_failed": {"passes": 20, "fails": 980, "value": 0.02},
+  "http_reqs": {"count": 1000, "rate": 50.5}
+}}`
+	ghzReportFixture = `{"count": 500, "rps": 99.5,
+  "latencyDistribution": [{"percentage": 50, "latency": 1000000}, {"percentage": 95, "latency": 25000000}],
+  "errorDistribution": {"rpc error: code = Unavailable": 5},
+  "statusCodeDistribution": {"OK": 495, "Unavailable": 5}}`
+)
+
+func newTar(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func newGzip(t *testing.T, content []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		loadTestType loadTestV1.LoadTestType
+		report       []byte
+		expected     Summary
+		expectedErr  error
+	}{
+		{
+			name:         "JMeter dashboard tar",
+			loadTestType: loadTestV1.LoadTestTypeJMeter,
+			report:       newTar(t, map[string]string{"./index.html": "<html/>", "./statistics.json": jmeterStatisticsFixture}),
+			expected:     Summary{Stats: Stats{Requests: 2000, Latency: Latency{P95: 250500 * time.Microsecond}, ErrorRate: 0.01, Throughput: 33.3}},
+		},
+		{
+			name:         "Locust gzip tar",
+			loadTestType: loadTestV1.LoadTestTypeLocust,
+			report:       newGzip(t, newTar(t, map[string]string{"report_stats_history.csv": "", "report_stats.csv": locustStatsFixture})),
+			expected:     Summary{Stats: Stats{Requests: 1000, Latency: Latency{P95: 120 * time.Millisecond}, ErrorRate: 0.01, Throughput: 16.6}},
+		},
+		{
+			name:         "k6 handleSummary JSON",
+			loadTestType: loadTestV1.LoadTestTypeK6,
+			report:       []byte(k6SummaryFixture),
+			expected:     Summary{Stats: Stats{Requests: 1000, Latency: Latency{P95: 180500 * time.Microsecond}, ErrorRate: 0.02, Throughput: 50.5}},
+		},
+		{
+			name:         "k6 summary export JSON",
+			loadTestType: loadTestV1.LoadTestTypeK6,
+			report:       []byte(k6SummaryExportFixture),
+			expected:     Summary{Stats: Stats{Requests: 1000, Latency: Latency{P95: 180500 * time.Microsecond}, ErrorRate: 0.02, Throughput: 50.5}},
+		},
+		{
+			name:         "ghz JSON",
+			loadTestType: loadTestV1.LoadTestTypeGhz,
+			report:       []byte(ghzReportFixture),
+			expected:     Summary{Stats: Stats{Requests: 500, Latency: Latency{P95: 25 * time.Millisecond}, ErrorRate: 0.01, Throughput: 99.5}},
+		},
+		{
+			name:         "results file missing in tar",
+			loadTestType: loadTestV1.LoadTestTypeJMeter,
+			report:       newTar(t, map[string]string{"index.html": "<html/>"}),
+			expectedErr:  ErrResultsNotFound,
+		},
+		{
+			name:         "Locust without Aggregated row",
+			loadTestType: loadTestV1.LoadTestTypeLocust,
+			report:       []byte(strings.Split(locustStatsFixture, "\n")[0] + "\n"),
+			expectedErr:  ErrMissingAggregated,
+		},
+		{
+			name:         "unsupported type",
+			loadTestType: loadTestV1.LoadTestTypeFake,
+			report:       []byte("{}"),
+			expectedErr:  ErrUnsupportedType,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := Parse(tt.loadTestType, bytes.NewReader(tt.report))
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Requests, summary.Requests)
+			assert.Equal(t, tt.expected.Latency.P95, summary.Latency.P95)
+			assert.InDelta(t, tt.expected.ErrorRate, summary.ErrorRate, 1e-9)
+			assert.InDelta(t, tt.expected.Throughput, summary.Throughput, 1e-9)
+		})
+	}
+}
+
+func TestParseEndpoints(t *testing.T) {
+	k6TaggedSummary := `{"metrics": {
+  "http_req_duration": {"values": {"avg": 80, "min": 5, "med": 70, "max": 900, "p(90)": 150, "p(95)": 180}},
+  "http_req_duration{name:login}": {"values": {"avg": 120, "p(95)": 300}},
+  "http_req_failed": {"values": {"rate": 0.02, "passes": 20, "fails": 980}},
+  "http_reqs": {"values": {"count": 1000, "rate": 50}}
+}}`
+
+	for _, tt := range []struct {
+		name              string
+		loadTestType      loadTestV1.LoadTestType
+		report            string
+		expectedErrors    int64
+		expectedLatency   Latency
+		expectedEndpoints []string
+	}{
+		{
+			name:              "JMeter transactions",
+			loadTestType:      loadTestV1.LoadTestTypeJMeter,
+			report:            jmeterStatisticsFixture,
+			expectedErrors:    20,
+			expectedLatency:   Latency{Mean: 120500 * time.Microsecond, P90: 200 * time.Millisecond, P95: 250500 * time.Microsecond, P99: 400 * time.Millisecond},
+			expectedEndpoints: []string{"GET /"},
+		},
+		{
+			name:              "Locust requests",
+			loadTestType:      loadTestV1.LoadTestTypeLocust,
+			report:            locustStatsFixture,
+			expectedErrors:    10,
+			expectedLatency:   Latency{Mean: 50200 * time.Microsecond, Min: 10 * time.Millisecond, Max: 900 * time.Millisecond, P50: 42 * time.Millisecond, P90: 90 * time.Millisecond, P95: 120 * time.Millisecond, P99: 300 * time.Millisecond},
+			expectedEndpoints: []string{"GET /"},
+		},
+		{
+			name:              "k6 sub-metrics",
+			loadTestType:      loadTestV1.LoadTestTypeK6,
+			report:            k6TaggedSummary,
+			expectedErrors:    20,
+			expectedLatency:   Latency{Mean: 80 * time.Millisecond, Min: 5 * time.Millisecond, Max: 900 * time.Millisecond, P50: 70 * time.Millisecond, P90: 150 * time.Millisecond, P95: 180 * time.Millisecond},
+			expectedEndpoints: []string{"name:login"},
+		},
+		{
+			name:            "ghz single call",
+			loadTestType:    loadTestV1.LoadTestTypeGhz,
+			report:          ghzReportFixture,
+			expectedErrors:  5,
+			expectedLatency: Latency{P50: time.Millisecond, P95: 25 * time.Millisecond},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := Parse(tt.loadTestType, strings.NewReader(tt.report))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedErrors, summary.Errors)
+			assert.Equal(t, tt.expectedLatency, summary.Latency)
+
+			var endpoints []string
+			for _, endpoint := range summary.Endpoints {
+				endpoints = append(endpoints, endpoint.Name)
+				assert.NotZero(t, endpoint.Latency.P95, endpoint.Name)
+			}
+			assert.Equal(t, tt.expectedEndpoints, endpoints)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package results
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var (
+	// ErrInvalidP95Latency is returned when p95Latency threshold is not a positive duration
+	ErrInvalidP95Latency = errors.New("p95Latency threshold should be a positive duration, e.g. 500ms")
+	// ErrInvalidErrorRate is returned when errorRate threshold is not a number between 0 and 1
+	ErrInvalidErrorRate = errors.New("errorRate threshold should be a number between 0 and 1, e.g. 0.01")
+	// ErrInvalidMinThroughput is returned when minThroughput threshold is not a positive number
+	ErrInvalidMinThroughput = errors.New("minThroughput threshold should be a positive number of requests per second, e.g. 100")
+)
+
+// Thresholds is the parsed pass/fail criteria of a loadtest, nil values are not evaluated
+type Thresholds struct {
+	P95Latency    *time.Duration
+	ErrorRate     *float64
+	MinThroughput *float64
+}
+
+// ParseThresholds parses and validates loadtest thresholds, nil thresholds are valid
+func ParseThresholds(thresholds *loadTestV1.LoadTestThresholds) (Thresholds, error) {
+	var parsed Thresholds
+	if thresholds == nil {
+		return parsed, nil
+	}
+
+	if thresholds.P95Latency != "" {
+		d, err := time.ParseDuration(thresholds.P95Latency)
+		if err != nil || d <= 0 {
+			return Thresholds{}, fmt.Errorf("%w, got %q", ErrInvalidP95Latency, thresholds.P95Latency)
+		}
+		parsed.P95Latency = &d
+	}
+
+	if thresholds.ErrorRate != "" {
+		rate, err := strconv.ParseFloat(thresholds.ErrorRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return Thresholds{}, fmt.Errorf("%w, got %q", ErrInvalidErrorRate, thresholds.ErrorRate)
+		}
+		parsed.ErrorRate = &rate
+	}
+
+	if thresholds.MinThroughput != "" {
+		rps, err := strconv.ParseFloat(thresholds.MinThroughput, 64)
+		if err != nil || rps <= 0 {
+			return Thresholds{}, fmt.Errorf("%w, got %q", ErrInvalidMinThroughput, thresholds.MinThroughput)
+		}
+		parsed.MinThroughput = &rps
+	}
+
+	return parsed, nil
+}
+
+// Evaluate checks the summary against thresholds, it returns false when at least one threshold is not met
+// and a message with measured values of every evaluated threshold
+func Evaluate(thresholds Thresholds, summary Summary) (bool, string) {
+	passed := true
+	checks := make([]string, 0, 3)
+
+	check := func(ok bool, format string, args ...interface{}) {
+		passed = passed && ok
+		result := "ok"
+		if !ok {
+			result = "failed"
+		}
+		checks = append(checks, fmt.Sprintf(format, args...)+" "+result)
+	}
+
+	if thresholds.P95Latency != nil {
+		check(summary.Latency.P95 <= *thresholds.P95Latency,
+			"p95Latency %s (max %s)", summary.Latency.P95, *thresholds.P95Latency)
+	}
+	if thresholds.ErrorRate != nil {
+		check(summary.ErrorRate <= *thresholds.ErrorRate,
+			"errorRate %s (max %s)", formatFloat(summary.ErrorRate), formatFloat(*thresholds.ErrorRate))
+	}
+	if thresholds.MinThroughput != nil {
+		check(summary.Throughput >= *thresholds.MinThroughput,
+			"throughput %s/s (min %s/s)", formatFloat(summary.Throughput), formatFloat(*thresholds.MinThroughput))
+	}
+
+	return passed, strings.Join(checks, ", ")
+}
+
+// formatFloat formats measured values with at most 4 decimals
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,58 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestParseThresholds(t *testing.T) {
+	parsed, err := ParseThresholds(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Thresholds{}, parsed)
+
+	parsed, err = ParseThresholds(&loadTestV1.LoadTestThresholds{P95Latency: "500ms", ErrorRate: "0.01", MinThroughput: "100"})
+	require.NoError(t, err)
+	require.NotNil(t, parsed.P95Latency)
+	assert.Equal(t, 500*time.Millisecond, *parsed.P95Latency)
+	require.NotNil(t, parsed.ErrorRate)
+	assert.Equal(t, 0.01, *parsed.ErrorRate)
+	require.NotNil(t, parsed.MinThroughput)
+	assert.Equal(t, 100.0, *parsed.MinThroughput)
+
+	for _, tt := range []struct {
+		thresholds  loadTestV1.LoadTestThresholds
+		expectedErr error
+	}{
+		{thresholds: loadTestV1.LoadTestThresholds{P95Latency: "500"}, expectedErr: ErrInvalidP95Latency},
+		{thresholds: loadTestV1.LoadTestThresholds{P95Latency: "-1s"}, expectedErr: ErrInvalidP95Latency},
+		{thresholds: loadTestV1.LoadTestThresholds{ErrorRate: "1%"}, expectedErr: ErrInvalidErrorRate},
+		{thresholds: loadTestV1.LoadTestThresholds{ErrorRate: "1.5"}, expectedErr: ErrInvalidErrorRate},
+		{thresholds: loadTestV1.LoadTestThresholds{MinThroughput: "0"}, expectedErr: ErrInvalidMinThroughput},
+	} {
+		_, err := ParseThresholds(&tt.thresholds)
+		assert.ErrorIs(t, err, tt.expectedErr)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	thresholds, err := ParseThresholds(&loadTestV1.LoadTestThresholds{P95Latency: "500ms", ErrorRate: "0.01", MinThroughput: "100"})
+	require.NoError(t, err)
+
+	passed, message := Evaluate(thresholds, Summary{Stats: Stats{Latency: Latency{P95: 420 * time.Millisecond}, ErrorRate: 0.002, Throughput: 120.123456}})
+	assert.True(t, passed)
+	assert.Equal(t, "p95Latency 420ms (max 500ms) ok, errorRate 0.002 (max 0.01) ok, throughput 120.1235/s (min 100/s) ok", message)
+
+	passed, message = Evaluate(thresholds, Summary{Stats: Stats{Latency: Latency{P95: 620 * time.Millisecond}, ErrorRate: 0.002, Throughput: 120}})
+	assert.False(t, passed)
+	assert.Equal(t, "p95Latency 620ms (max 500ms) failed, errorRate 0.002 (max 0.01) ok, throughput 120/s (min 100/s) ok", message)
+
+	// thresholds not set are not evaluated
+	passed, message = Evaluate(Thresholds{MinThroughput: thresholds.MinThroughput}, Summary{Stats: Stats{Latency: Latency{P95: time.Hour}, Throughput: 50}})
+	assert.False(t, passed)
+	assert.Equal(t, "throughput 50/s (min 100/s) failed", message)
+}
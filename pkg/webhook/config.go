@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	"github.com/hellofresh/kangal/pkg/core/observability"
+)
+
+// Config is the possible Kangal Webhook configurations
+type Config struct {
+	HTTPPort    int    `envconfig:"WEB_HTTP_PORT" default:"8443"`
+	TLSCertFile string `envconfig:"WEBHOOK_TLS_CERT_FILE" default:"/etc/kangal/webhook/tls.crt"`
+	TLSKeyFile  string `envconfig:"WEBHOOK_TLS_KEY_FILE" default:"/etc/kangal/webhook/tls.key"`
+	TLSCAFile   string `envconfig:"WEBHOOK_TLS_CA_FILE" default:"/etc/kangal/webhook/ca.crt"`
+	Logger      observability.LoggerConfig
+
+	MaxLoadTestsRun     int
+	MasterURL           string
+	AllowedCustomImages bool `envconfig:"ALLOWED_CUSTOM_IMAGES" default:"false"`
+
+	// Scheduling is the allowlist of scheduling constraints a loadtest can set
+	Scheduling backends.SchedulingPolicy
+
+	// MaxTTLAfterFinished is the max value allowed for loadtest ttlSecondsAfterFinished
+	MaxTTLAfterFinished time.Duration `envconfig:"MAX_TTL_AFTER_FINISHED" default:"168h"`
+
+	// ConversionService is the Service of the webhook, when set LoadTest CRD is switched to the webhook conversion
+	// on start and v2 is served
+	ConversionService string `envconfig:"WEBHOOK_CONVERSION_SERVICE"`
+	// ConversionNamespace is the namespace of the webhook Service
+	ConversionNamespace string `envconfig:"WEBHOOK_CONVERSION_NAMESPACE"`
+	// ConversionPort is the port of the webhook Service
+	ConversionPort int32 `envconfig:"WEBHOOK_CONVERSION_PORT" default:"443"`
+
+	// ProxyUsername is the user the proxy creates LoadTests as, e.g. system:serviceaccount:kangal:default.
+	// LoadTests marked as transformed are not transformed again only when created by this user
+	ProxyUsername string `envconfig:"WEBHOOK_PROXY_USERNAME"`
+
+	// KubeClientTimeout specifies timeout for each operation done by kube client
+	KubeClientTimeout time.Duration `envconfig:"KUBE_CLIENT_TIMEOUT" default:"5s"`
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	cHttp "github.com/hellofresh/kangal/pkg/core/http"
+	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
+	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	apisLoadTestV2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
+)
+
+// ConversionReview describes a CRD version conversion request/response,
+// it mirrors apiextensions.k8s.io/v1 ConversionReview wire format
+type ConversionReview struct {
+	metaV1.TypeMeta `json:",inline"`
+	Request         *ConversionRequest  `json:"request,omitempty"`
+	Response        *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest describes the conversion request parameters
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse describes a conversion response
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metaV1.Status          `json:"result"`
+}
+
+// Convert converts LoadTest resources between v1 and v2 API versions
+func Convert(w http.ResponseWriter, r *http.Request) {
+	logger := mPkg.GetLogger(r.Context())
+
+	var review ConversionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		logger.Debug("Could not decode conversion review", zap.Error(err))
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, fmt.Sprintf("could not decode conversion review: %s", err)))
+		return
+	}
+	if review.Request == nil {
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, "conversion review request is empty"))
+		return
+	}
+
+	response := &ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metaV1.Status{Status: metaV1.StatusSuccess},
+	}
+
+	for _, object := range review.Request.Objects {
+		converted, err := convertLoadTest(object.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			logger.Warn("Could not convert LoadTest", zap.String("desiredAPIVersion", review.Request.DesiredAPIVersion), zap.Error(err))
+			response.ConvertedObjects = nil
+			response.Result = metaV1.Status{
+				Status:  metaV1.StatusFailure,
+				Message: err.Error(),
+			}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	render.JSON(w, r, &ConversionReview{
+		TypeMeta: review.TypeMeta,
+		Response: response,
+	})
+}
+
+func convertLoadTest(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	var typeMeta metaV1.TypeMeta
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, fmt.Errorf("could not decode object type: %w", err)
+	}
+
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	v1Version := apisLoadTestV1.SchemeGroupVersion.String()
+	v2Version := apisLoadTestV2.SchemeGroupVersion.String()
+
+	switch {
+	case typeMeta.APIVersion == v1Version && desiredAPIVersion == v2Version:
+		var loadTest apisLoadTestV1.LoadTest
+		if err := json.Unmarshal(raw, &loadTest); err != nil {
+			return nil, fmt.Errorf("could not decode %s LoadTest: %w", v1Version, err)
+		}
+		return json.Marshal(apisLoadTestV2.ConvertFromV1(&loadTest))
+	case typeMeta.APIVersion == v2Version && desiredAPIVersion == v1Version:
+		var loadTest apisLoadTestV2.LoadTest
+		if err := json.Unmarshal(raw, &loadTest); err != nil {
+			return nil, fmt.Errorf("could not decode %s LoadTest: %w", v2Version, err)
+		}
+		return json.Marshal(apisLoadTestV2.ConvertToV1(&loadTest))
+	}
+
+	return nil, fmt.Errorf("unsupported conversion from %q to %q", typeMeta.APIVersion, desiredAPIVersion)
+}
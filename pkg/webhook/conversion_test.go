@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
+	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	apisLoadTestV2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
+)
+
+func doConversionReview(t *testing.T, desiredAPIVersion string, objects ...interface{}) *ConversionResponse {
+	t.Helper()
+
+	request := &ConversionRequest{
+		UID:               "0f5a3b3e-98b4-4c5f-a4f2-3e1c9d0a7b11",
+		DesiredAPIVersion: desiredAPIVersion,
+	}
+	for _, object := range objects {
+		raw, err := json.Marshal(object)
+		require.NoError(t, err)
+		request.Objects = append(request.Objects, runtime.RawExtension{Raw: raw})
+	}
+
+	body, err := json.Marshal(&ConversionReview{
+		TypeMeta: metaV1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  request,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body))
+	req = req.WithContext(mPkg.SetLogger(context.Background(), zaptest.NewLogger(t)))
+
+	w := httptest.NewRecorder()
+	Convert(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var review ConversionReview
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&review))
+	require.NotNil(t, review.Response)
+	assert.Equal(t, request.UID, review.Response.UID)
+	assert.Equal(t, "ConversionReview", review.Kind)
+
+	return review.Response
+}
+
+func TestConvert(t *testing.T) {
+	users := int32(100)
+	v2LoadTest := &apisLoadTestV2.LoadTest{
+		TypeMeta:   metaV1.TypeMeta{APIVersion: apisLoadTestV2.SchemeGroupVersion.String(), Kind: "LoadTest"},
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-v2"},
+		Spec: apisLoadTestV2.LoadTestSpec{
+			Type:   apisLoadTestV1.LoadTestTypeLocust,
+			Locust: &apisLoadTestV2.LocustSpec{Users: &users},
+		},
+	}
+
+	t.Run("v2 to v1", func(t *testing.T) {
+		resp := doConversionReview(t, apisLoadTestV1.SchemeGroupVersion.String(), v2LoadTest)
+
+		assert.Equal(t, metaV1.StatusSuccess, resp.Result.Status)
+		require.Len(t, resp.ConvertedObjects, 1)
+
+		var loadTest apisLoadTestV1.LoadTest
+		require.NoError(t, json.Unmarshal(resp.ConvertedObjects[0].Raw, &loadTest))
+		assert.Equal(t, apisLoadTestV1.SchemeGroupVersion.String(), loadTest.APIVersion)
+		assert.Equal(t, "100", loadTest.Spec.EnvVars[apisLoadTestV2.EnvLocustUsers])
+	})
+
+	t.Run("v1 to v2", func(t *testing.T) {
+		v1LoadTest := apisLoadTestV2.ConvertToV1(v2LoadTest)
+		resp := doConversionReview(t, apisLoadTestV2.SchemeGroupVersion.String(), v1LoadTest, v2LoadTest)
+
+		assert.Equal(t, metaV1.StatusSuccess, resp.Result.Status)
+		require.Len(t, resp.ConvertedObjects, 2)
+
+		for _, object := range resp.ConvertedObjects {
+			var loadTest apisLoadTestV2.LoadTest
+			require.NoError(t, json.Unmarshal(object.Raw, &loadTest))
+			assert.Equal(t, apisLoadTestV2.SchemeGroupVersion.String(), loadTest.APIVersion)
+			require.NotNil(t, loadTest.Spec.Locust)
+			assert.Equal(t, users, *loadTest.Spec.Locust.Users)
+			assert.Empty(t, loadTest.Spec.EnvVars)
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		resp := doConversionReview(t, "kangal.hellofresh.com/v3", v2LoadTest)
+
+		assert.Equal(t, metaV1.StatusFailure, resp.Result.Status)
+		assert.Contains(t, resp.Result.Message, `unsupported conversion from "kangal.hellofresh.com/v2" to "kangal.hellofresh.com/v3"`)
+		assert.Empty(t, resp.ConvertedObjects)
+	})
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+
+	apisLoadTestV2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
+)
+
+// loadTestCRDName is the name of LoadTest CustomResourceDefinition
+const loadTestCRDName = "loadtests.kangal.hellofresh.com"
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// ConversionConfig is the Service the API server calls to convert LoadTest resources between API versions
+type ConversionConfig struct {
+	Service   string
+	Namespace string
+	Port      int32
+	// CABundle is the PEM encoded CA of the webhook certificate
+	CABundle []byte
+}
+
+// ConfigureConversion switches LoadTest CRD to the conversion webhook served by the given Service and serves v2.
+// CRD shipped by the chart serves v1 only, as its conversion config depends on where the webhook is deployed
+func ConfigureConversion(ctx context.Context, client dynamic.Interface, cfg ConversionConfig) error {
+	crds := client.Resource(crdResource)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd, err := crds.Get(ctx, loadTestCRDName, metaV1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("could not get LoadTest CRD: %w", err)
+		}
+
+		if err := setConversion(crd, cfg); err != nil {
+			return err
+		}
+
+		_, err = crds.Update(ctx, crd, metaV1.UpdateOptions{})
+		return err
+	})
+}
+
+func setConversion(crd *unstructured.Unstructured, cfg ConversionConfig) error {
+	conversion := map[string]interface{}{
+		"strategy": "Webhook",
+		"webhook": map[string]interface{}{
+			"conversionReviewVersions": []interface{}{"v1"},
+			"clientConfig": map[string]interface{}{
+				"service": map[string]interface{}{
+					"name":      cfg.Service,
+					"namespace": cfg.Namespace,
+					"path":      "/convert",
+					"port":      int64(cfg.Port),
+				},
+				"caBundle": base64.StdEncoding.EncodeToString(cfg.CABundle),
+			},
+		},
+	}
+	if err := unstructured.SetNestedField(crd.Object, conversion, "spec", "conversion"); err != nil {
+		return fmt.Errorf("could not set LoadTest CRD conversion: %w", err)
+	}
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return fmt.Errorf("could not read LoadTest CRD versions: %w", err)
+	}
+	for _, version := range versions {
+		if version, ok := version.(map[string]interface{}); ok && version["name"] == apisLoadTestV2.SchemeGroupVersion.Version {
+			version["served"] = true
+		}
+	}
+	if err := unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"); err != nil {
+		return fmt.Errorf("could not set LoadTest CRD versions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestConfigureConversion(t *testing.T) {
+	ctx := context.Background()
+
+	content, err := os.ReadFile("../../charts/kangal/crds/loadtest.yaml")
+	require.NoError(t, err)
+	crd := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal(content, &crd.Object))
+
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"}, crd)
+
+	require.NoError(t, ConfigureConversion(ctx, client, ConversionConfig{
+		Service:   "kangal-webhook",
+		Namespace: "kangal",
+		Port:      443,
+		CABundle:  []byte("ca"),
+	}))
+
+	updated, err := client.Resource(crdResource).Get(ctx, loadTestCRDName, metaV1.GetOptions{})
+	require.NoError(t, err)
+
+	strategy, _, _ := unstructured.NestedString(updated.Object, "spec", "conversion", "strategy")
+	assert.Equal(t, "Webhook", strategy)
+	service, _, _ := unstructured.NestedMap(updated.Object, "spec", "conversion", "webhook", "clientConfig", "service")
+	assert.Equal(t, map[string]interface{}{"name": "kangal-webhook", "namespace": "kangal", "path": "/convert", "port": int64(443)}, service)
+	caBundle, _, _ := unstructured.NestedString(updated.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("ca")), caBundle)
+
+	versions, _, _ := unstructured.NestedSlice(updated.Object, "spec", "versions")
+	served := map[string]bool{}
+	for _, version := range versions {
+		version := version.(map[string]interface{})
+		served[version["name"].(string)] = version["served"].(bool)
+	}
+	assert.Equal(t, map[string]bool{"v1": true, "v2": true}, served)
+}
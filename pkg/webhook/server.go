@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	cHttp "github.com/hellofresh/kangal/pkg/core/http"
+	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
+	kube "github.com/hellofresh/kangal/pkg/kubernetes"
+)
+
+// Runner encapsulates all Kangal Webhook server dependencies
+type Runner struct {
+	KubeClient *kube.Client
+	Logger     *zap.Logger
+}
+
+// RunServer runs Kangal admission webhook server
+func RunServer(cfg Config, rr Runner) error {
+	registry := backends.New(
+		backends.WithLogger(rr.Logger),
+		backends.WithSchedulingPolicy(cfg.Scheduling),
+	)
+
+	webhookHandler := NewWebhook(cfg.MaxLoadTestsRun, registry, rr.KubeClient, cfg.AllowedCustomImages, cfg.MaxTTLAfterFinished, cfg.ProxyUsername)
+
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(mPkg.NewLogger(rr.Logger).Handler)
+	r.Use(mPkg.NewRequestLogger().Handler)
+	r.Use(mPkg.Recovery)
+	r.Use(render.SetContentType(render.ContentTypeJSON))
+
+	r.Get("/status", cHttp.LivenessHandler("Kangal Webhook"))
+
+	r.Post("/mutate", webhookHandler.Mutate)
+	r.Post("/validate", webhookHandler.Validate)
+	r.Post("/convert", Convert)
+
+	address := fmt.Sprintf(":%d", cfg.HTTPPort)
+	rr.Logger.Info("Running HTTPS server...", zap.String("address", address))
+
+	// Kubernetes API server requires admission webhooks to be served over TLS
+	err := http.ListenAndServeTLS(address, cfg.TLSCertFile, cfg.TLSKeyFile, r)
+	if err != nil {
+		return fmt.Errorf("failed to run HTTPS server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+	admissionV1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	cHttp "github.com/hellofresh/kangal/pkg/core/http"
+	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
+	kube "github.com/hellofresh/kangal/pkg/kubernetes"
+	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	apisLoadTestV2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
+)
+
+// jsonPatchOperation is a single RFC 6902 JSON patch operation
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Webhook validates and defaults LoadTest resources applied directly to the cluster,
+// with the same rules used by Proxy for load tests created with Kangal API
+type Webhook struct {
+	registry            backends.Registry
+	kubeClient          *kube.Client
+	maxLoadTestsRun     int
+	allowedCustomImages bool
+	maxTTLAfterFinished time.Duration
+	// proxyUsername is the only user whose LoadTests marked as transformed are trusted
+	proxyUsername string
+}
+
+// NewWebhook returns new Webhook instance
+func NewWebhook(maxLoadTestsRun int, registry backends.Registry, kubeClient *kube.Client, allowedCustomImages bool, maxTTLAfterFinished time.Duration, proxyUsername string) *Webhook {
+	return &Webhook{
+		registry:            registry,
+		kubeClient:          kubeClient,
+		maxLoadTestsRun:     maxLoadTestsRun,
+		allowedCustomImages: allowedCustomImages,
+		maxTTLAfterFinished: maxTTLAfterFinished,
+		proxyUsername:       proxyUsername,
+	}
+}
+
+// Mutate defaults LoadTest spec with its backend TransformLoadTestSpec on create
+func (wh *Webhook) Mutate(w http.ResponseWriter, r *http.Request) {
+	wh.serve(w, r, wh.mutate)
+}
+
+// Validate validates LoadTest spec, image policy and active load tests limit
+func (wh *Webhook) Validate(w http.ResponseWriter, r *http.Request) {
+	wh.serve(w, r, wh.validate)
+}
+
+func (wh *Webhook) serve(w http.ResponseWriter, r *http.Request, admit func(*admissionV1.AdmissionRequest, *zap.Logger) *admissionV1.AdmissionResponse) {
+	logger := mPkg.GetLogger(r.Context())
+
+	var review admissionV1.AdmissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		logger.Debug("Could not decode admission review", zap.Error(err))
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, fmt.Sprintf("could not decode admission review: %s", err)))
+		return
+	}
+	if review.Request == nil {
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest, "admission review request is empty"))
+		return
+	}
+
+	logger = logger.With(
+		zap.String("uid", string(review.Request.UID)),
+		zap.String("operation", string(review.Request.Operation)),
+		zap.String("loadtest", review.Request.Name),
+	)
+
+	response := admit(review.Request, logger)
+	response.UID = review.Request.UID
+
+	render.JSON(w, r, &admissionV1.AdmissionReview{
+		TypeMeta: review.TypeMeta,
+		Response: response,
+	})
+}
+
+func (wh *Webhook) mutate(req *admissionV1.AdmissionRequest, logger *zap.Logger) *admissionV1.AdmissionResponse {
+	if req.Operation != admissionV1.Create {
+		return allowed()
+	}
+
+	loadTest, backendErrs, err := decodeRequestLoadTest(req.Kind, req.Object.Raw)
+	if err != nil {
+		return errored(http.StatusBadRequest, err)
+	}
+	if len(backendErrs) > 0 {
+		// let validating webhook deny it with detailed message
+		return allowed()
+	}
+
+	// spec created by Proxy is already transformed and transforming it again is not idempotent,
+	// anyone else can set the annotation, so it is trusted for the proxy user only
+	if apisLoadTestV1.IsTransformed(loadTest) && wh.proxyUsername != "" && req.UserInfo.Username == wh.proxyUsername {
+		return allowed()
+	}
+
+	backend, err := wh.registry.GetBackend(loadTest.Spec.Type)
+	if err != nil {
+		// let validating webhook deny it with detailed message
+		return allowed()
+	}
+
+	spec := loadTest.Spec.DeepCopy()
+	if err := backend.TransformLoadTestSpec(spec); err != nil {
+		// let validating webhook deny it with detailed message
+		return allowed()
+	}
+
+	labels := apisLoadTestV1.BuildLoadTestLabels(*spec)
+	for key, value := range loadTest.GetLabels() {
+		labels[key] = value
+	}
+
+	mutated := loadTest.DeepCopy()
+	apisLoadTestV1.SetTransformed(mutated)
+
+	// the patch applies to the LoadTest in the requested version
+	var patchedSpec interface{} = spec
+	if req.Kind.Version == apisLoadTestV2.SchemeGroupVersion.Version {
+		mutated.Spec = *spec
+		patchedSpec = apisLoadTestV2.ConvertFromV1(mutated).Spec
+	}
+
+	patch, err := json.Marshal([]jsonPatchOperation{
+		{Op: "add", Path: "/spec", Value: patchedSpec},
+		{Op: "add", Path: "/metadata/labels", Value: labels},
+		{Op: "add", Path: "/metadata/annotations", Value: mutated.Annotations},
+	})
+	if err != nil {
+		logger.Error("Could not build patch", zap.Error(err))
+		return errored(http.StatusInternalServerError, err)
+	}
+
+	logger.Debug("Defaulted LoadTest spec")
+
+	patchType := admissionV1.PatchTypeJSONPatch
+	return &admissionV1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}
+}
+
+func (wh *Webhook) validate(req *admissionV1.AdmissionRequest, logger *zap.Logger) *admissionV1.AdmissionResponse {
+	if req.Operation != admissionV1.Create && req.Operation != admissionV1.Update {
+		return allowed()
+	}
+
+	loadTest, backendErrs, err := decodeRequestLoadTest(req.Kind, req.Object.Raw)
+	if err != nil {
+		return errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionV1.Update {
+		oldLoadTest, _, err := decodeRequestLoadTest(req.Kind, req.OldObject.Raw)
+		if err != nil {
+			return errored(http.StatusBadRequest, err)
+		}
+
+		// metadata and status updates done by controller, e.g. finalizers, are not validated again
+		if equality.Semantic.DeepEqual(oldLoadTest.Spec, loadTest.Spec) {
+			return allowed()
+		}
+	}
+
+	if errs := append(backendErrs, wh.validateSpec(loadTest)...); len(errs) > 0 {
+		logger.Debug("Denied LoadTest", zap.Error(errs.ToAggregate()))
+		return denied(k8sAPIErrors.NewInvalid(apisLoadTestV1.SchemeGroupVersion.WithKind("LoadTest").GroupKind(), loadTest.GetName(), errs).Status())
+	}
+
+	if req.Operation == admissionV1.Create {
+		// check the number of active loadtests currently running on the cluster
+		testsByPhase, _, err := wh.kubeClient.CountExistingLoadtests()
+		if err != nil {
+			logger.Error("Could not count active load tests", zap.Error(err))
+			return errored(http.StatusInternalServerError, fmt.Errorf("could not count active load tests: %w", err))
+		}
+		activeLoadTests := testsByPhase["running"] + testsByPhase["creating"]
+
+		if int(activeLoadTests) >= wh.maxLoadTestsRun {
+			logger.Warn("number of active load tests reached limit", zap.Int("current", int(activeLoadTests)), zap.Int("limit", wh.maxLoadTestsRun))
+			return denied(metaV1.Status{
+				Status:  metaV1.StatusFailure,
+				Code:    http.StatusTooManyRequests,
+				Reason:  metaV1.StatusReasonTooManyRequests,
+				Message: fmt.Sprintf("Number of active load tests reached limit, %d of %d load tests are active", activeLoadTests, wh.maxLoadTestsRun),
+			})
+		}
+	}
+
+	return allowed()
+}
+
+// validateSpec applies Proxy request rules to LoadTest spec and returns all violations found
+func (wh *Webhook) validateSpec(loadTest *apisLoadTestV1.LoadTest) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if ttl := loadTest.Spec.TTLSecondsAfterFinished; ttl != nil {
+		maxTTL := int64(wh.maxTTLAfterFinished / time.Second)
+		if *ttl < 0 {
+			errs = append(errs, field.Invalid(specPath.Child("ttlSecondsAfterFinished"), *ttl, "must be greater than or equal to 0"))
+		} else if int64(*ttl) > maxTTL {
+			errs = append(errs, field.Invalid(specPath.Child("ttlSecondsAfterFinished"), *ttl, fmt.Sprintf("must be less than or equal to %d", maxTTL)))
+		}
+	}
+
+	backend, err := wh.registry.GetBackend(loadTest.Spec.Type)
+	if err != nil {
+		return append(errs, field.Invalid(specPath.Child("type"), string(loadTest.Spec.Type), err.Error()))
+	}
+
+	// transform a copy, transforming is not idempotent and spec may be already transformed by Proxy or defaulting
+	spec := loadTest.Spec.DeepCopy()
+	if err := backend.TransformLoadTestSpec(spec); err != nil {
+		errs = append(errs, field.Invalid(specPath, string(loadTest.Spec.Type), fmt.Sprintf("%s backend rejected spec: %s", loadTest.Spec.Type, err)))
+	}
+
+	if !wh.allowedCustomImages {
+		// backend default images are allowed, as they are set by TransformLoadTestSpec
+		defaults := loadTest.Spec.DeepCopy()
+		defaults.MasterConfig = apisLoadTestV1.ImageDetails{}
+		defaults.WorkerConfig = apisLoadTestV1.ImageDetails{}
+		_ = backend.TransformLoadTestSpec(defaults)
+
+		if isCustomImage(loadTest.Spec.MasterConfig, defaults.MasterConfig) {
+			errs = append(errs, field.Forbidden(specPath.Child("masterConfig"), "custom images are not allowed, remove image and tag to use backend default"))
+		}
+		if isCustomImage(loadTest.Spec.WorkerConfig, defaults.WorkerConfig) {
+			errs = append(errs, field.Forbidden(specPath.Child("workerConfig"), "custom images are not allowed, remove image and tag to use backend default"))
+		}
+	}
+
+	return errs
+}
+
+func isCustomImage(image, defaultImage apisLoadTestV1.ImageDetails) bool {
+	if image.Image == "" && image.Tag == "" {
+		return false
+	}
+	return image != defaultImage
+}
+
+// decodeRequestLoadTest decodes the LoadTest of the admission request and converts it to v1, the storage version.
+// Backend specific configuration of v2 LoadTests not matching their type is returned as field errors,
+// as converting it would drop it silently
+func decodeRequestLoadTest(kind metaV1.GroupVersionKind, raw []byte) (*apisLoadTestV1.LoadTest, field.ErrorList, error) {
+	if kind.Version != apisLoadTestV2.SchemeGroupVersion.Version {
+		loadTest, err := decodeLoadTest(raw)
+		return loadTest, nil, err
+	}
+
+	var loadTest apisLoadTestV2.LoadTest
+	if err := json.Unmarshal(raw, &loadTest); err != nil {
+		return nil, nil, fmt.Errorf("could not decode LoadTest: %w", err)
+	}
+	return apisLoadTestV2.ConvertToV1(&loadTest), apisLoadTestV2.ValidateBackendSpec(loadTest.Spec, field.NewPath("spec")), nil
+}
+
+func decodeLoadTest(raw []byte) (*apisLoadTestV1.LoadTest, error) {
+	var loadTest apisLoadTestV1.LoadTest
+	if err := json.Unmarshal(raw, &loadTest); err != nil {
+		return nil, fmt.Errorf("could not decode LoadTest: %w", err)
+	}
+	return &loadTest, nil
+}
+
+func allowed() *admissionV1.AdmissionResponse {
+	return &admissionV1.AdmissionResponse{Allowed: true}
+}
+
+func denied(status metaV1.Status) *admissionV1.AdmissionResponse {
+	return &admissionV1.AdmissionResponse{
+		Allowed: false,
+		Result:  &status,
+	}
+}
+
+func errored(code int32, err error) *admissionV1.AdmissionResponse {
+	return denied(metaV1.Status{
+		Status:  metaV1.StatusFailure,
+		Code:    code,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,399 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	admissionV1 "k8s.io/api/admission/v1"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	_ "github.com/hellofresh/kangal/pkg/backends/jmeter"
+	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
+	kube "github.com/hellofresh/kangal/pkg/kubernetes"
+	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	apisLoadTestV2 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v2"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+)
+
+func buildLoadTest(modify func(lt *apisLoadTestV1.LoadTest)) *apisLoadTestV1.LoadTest {
+	distributedPods := int32(1)
+	lt := &apisLoadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-from-kubectl"},
+		Spec: apisLoadTestV1.LoadTestSpec{
+			Type:            apisLoadTestV1.LoadTestTypeJMeter,
+			DistributedPods: &distributedPods,
+			TestFile:        []byte("test file"),
+		},
+	}
+	if modify != nil {
+		modify(lt)
+	}
+	return lt
+}
+
+func doAdmissionReview(t *testing.T, handler http.HandlerFunc, operation admissionV1.Operation, object, oldObject *apisLoadTestV1.LoadTest) *admissionV1.AdmissionResponse {
+	t.Helper()
+
+	request := &admissionV1.AdmissionRequest{
+		UID:       "7c4fc5a8-1d6c-4a5c-8a0e-1a3a7b6c0f10",
+		Name:      object.Name,
+		Operation: operation,
+	}
+	raw, err := json.Marshal(object)
+	require.NoError(t, err)
+	request.Object = runtime.RawExtension{Raw: raw}
+
+	if oldObject != nil {
+		raw, err := json.Marshal(oldObject)
+		require.NoError(t, err)
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	}
+
+	return doAdmissionRequest(t, handler, request)
+}
+
+func doAdmissionReviewV2(t *testing.T, handler http.HandlerFunc, object *apisLoadTestV2.LoadTest) *admissionV1.AdmissionResponse {
+	t.Helper()
+
+	raw, err := json.Marshal(object)
+	require.NoError(t, err)
+
+	return doAdmissionRequest(t, handler, &admissionV1.AdmissionRequest{
+		UID:       "7c4fc5a8-1d6c-4a5c-8a0e-1a3a7b6c0f10",
+		Kind:      metaV1.GroupVersionKind(apisLoadTestV2.SchemeGroupVersion.WithKind("LoadTest")),
+		Name:      object.Name,
+		Operation: admissionV1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+}
+
+func doAdmissionRequest(t *testing.T, handler http.HandlerFunc, request *admissionV1.AdmissionRequest) *admissionV1.AdmissionResponse {
+	t.Helper()
+
+	body, err := json.Marshal(&admissionV1.AdmissionReview{
+		TypeMeta: metaV1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  request,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+	req = req.WithContext(mPkg.SetLogger(context.Background(), zaptest.NewLogger(t)))
+
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var review admissionV1.AdmissionReview
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&review))
+	require.NotNil(t, review.Response)
+	assert.Equal(t, request.UID, review.Response.UID)
+	assert.Equal(t, "AdmissionReview", review.Kind)
+
+	return review.Response
+}
+
+const testProxyUsername = "system:serviceaccount:kangal:kangal-proxy"
+
+func newTestWebhook(t *testing.T, allowedCustomImages bool, existing ...runtime.Object) *Webhook {
+	logger := zaptest.NewLogger(t)
+	kubeClientSet := fake.NewSimpleClientset()
+	loadtestClientSet := fakeClientset.NewSimpleClientset(existing...)
+
+	registry := backends.New(backends.WithLogger(logger))
+	kubeClient := kube.NewClient(loadtestClientSet.KangalV1().LoadTests(), kubeClientSet, logger)
+
+	return NewWebhook(1, registry, kubeClient, allowedCustomImages, time.Hour, testProxyUsername)
+}
+
+func TestValidate(t *testing.T) {
+	runningLoadTest := buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+		lt.Name = "loadtest-running"
+		lt.Status.Phase = apisLoadTestV1.LoadTestRunning
+	})
+
+	for _, tt := range []struct {
+		name                string
+		operation           admissionV1.Operation
+		loadTest            *apisLoadTestV1.LoadTest
+		oldLoadTest         *apisLoadTestV1.LoadTest
+		existing            []runtime.Object
+		allowedCustomImages bool
+		expectedAllowed     bool
+		expectedCode        int32
+		expectedMessages    []string
+	}{
+		{
+			name:            "valid spec",
+			operation:       admissionV1.Create,
+			loadTest:        buildLoadTest(nil),
+			expectedAllowed: true,
+		},
+		{
+			name:      "default images are allowed",
+			operation: admissionV1.Create,
+			loadTest: buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+				lt.Spec.MasterConfig = apisLoadTestV1.ImageDetails{Image: "hellofresh/kangal-jmeter-master", Tag: "latest"}
+				lt.Spec.WorkerConfig = apisLoadTestV1.ImageDetails{Image: "hellofresh/kangal-jmeter-worker", Tag: "latest"}
+			}),
+			expectedAllowed: true,
+		},
+		{
+			name:      "custom images are allowed when enabled",
+			operation: admissionV1.Create,
+			loadTest: buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+				lt.Spec.MasterConfig = apisLoadTestV1.ImageDetails{Image: "my-registry/jmeter-master", Tag: "5.5"}
+			}),
+			allowedCustomImages: true,
+			expectedAllowed:     true,
+		},
+		{
+			name:      "all violations are reported",
+			operation: admissionV1.Create,
+			loadTest: buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+				ttl := int32(7200)
+				lt.Spec.TTLSecondsAfterFinished = &ttl
+				lt.Spec.MasterConfig = apisLoadTestV1.ImageDetails{Image: "my-registry/jmeter-master", Tag: "5.5"}
+				lt.Spec.TestFile = nil
+			}),
+			expectedAllowed: false,
+			expectedCode:    http.StatusUnprocessableEntity,
+			expectedMessages: []string{
+				`LoadTest.kangal.hellofresh.com "loadtest-from-kubectl" is invalid`,
+				"spec.ttlSecondsAfterFinished: Invalid value: 7200: must be less than or equal to 3600",
+				"spec.masterConfig: Forbidden: custom images are not allowed",
+				"JMeter backend rejected spec",
+			},
+		},
+		{
+			name:      "unknown type",
+			operation: admissionV1.Create,
+			loadTest: buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+				lt.Spec.Type = "Unknown"
+			}),
+			expectedAllowed:  false,
+			expectedCode:     http.StatusUnprocessableEntity,
+			expectedMessages: []string{"spec.type: Invalid value: \"Unknown\""},
+		},
+		{
+			name:             "active load tests limit reached",
+			operation:        admissionV1.Create,
+			loadTest:         buildLoadTest(nil),
+			existing:         []runtime.Object{runningLoadTest},
+			expectedAllowed:  false,
+			expectedCode:     http.StatusTooManyRequests,
+			expectedMessages: []string{"Number of active load tests reached limit, 1 of 1 load tests are active"},
+		},
+		{
+			name:      "update without spec changes",
+			operation: admissionV1.Update,
+			loadTest: buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+				lt.Spec.TestFile = nil
+				lt.Finalizers = []string{"kangal.hellofresh.com/archive"}
+			}),
+			oldLoadTest: buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+				lt.Spec.TestFile = nil
+			}),
+			existing:        []runtime.Object{runningLoadTest},
+			expectedAllowed: true,
+		},
+		{
+			name:      "update with invalid spec",
+			operation: admissionV1.Update,
+			loadTest: buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+				lt.Spec.TestFile = nil
+			}),
+			oldLoadTest:      buildLoadTest(nil),
+			expectedAllowed:  false,
+			expectedCode:     http.StatusUnprocessableEntity,
+			expectedMessages: []string{"JMeter backend rejected spec"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			wh := newTestWebhook(t, tt.allowedCustomImages, tt.existing...)
+
+			resp := doAdmissionReview(t, wh.Validate, tt.operation, tt.loadTest, tt.oldLoadTest)
+
+			assert.Equal(t, tt.expectedAllowed, resp.Allowed)
+			if tt.expectedAllowed {
+				return
+			}
+			require.NotNil(t, resp.Result)
+			assert.Equal(t, tt.expectedCode, resp.Result.Code)
+			for _, message := range tt.expectedMessages {
+				assert.Contains(t, resp.Result.Message, message)
+			}
+		})
+	}
+}
+
+func TestMutate(t *testing.T) {
+	t.Run("defaults spec", func(t *testing.T) {
+		wh := newTestWebhook(t, false)
+
+		loadTest := buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+			lt.Labels = map[string]string{"team": "kangal"}
+			lt.Spec.Tags = apisLoadTestV1.LoadTestTags{"department": "platform"}
+		})
+		resp := doAdmissionReview(t, wh.Mutate, admissionV1.Create, loadTest, nil)
+
+		assert.True(t, resp.Allowed)
+		require.NotNil(t, resp.PatchType)
+		assert.Equal(t, admissionV1.PatchTypeJSONPatch, *resp.PatchType)
+
+		var patch []struct {
+			Op    string          `json:"op"`
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Patch, &patch))
+		require.Len(t, patch, 3)
+
+		var spec apisLoadTestV1.LoadTestSpec
+		assert.Equal(t, "/spec", patch[0].Path)
+		require.NoError(t, json.Unmarshal(patch[0].Value, &spec))
+		assert.Equal(t, "hellofresh/kangal-jmeter-master", spec.MasterConfig.Image)
+		assert.Equal(t, "hellofresh/kangal-jmeter-worker", spec.WorkerConfig.Image)
+
+		var labels map[string]string
+		assert.Equal(t, "/metadata/labels", patch[1].Path)
+		require.NoError(t, json.Unmarshal(patch[1].Value, &labels))
+		assert.Equal(t, "kangal", labels["team"])
+		assert.Equal(t, "platform", labels["test-tag-department"])
+		assert.NotEmpty(t, labels["test-file-hash"])
+
+		var annotations map[string]string
+		assert.Equal(t, "/metadata/annotations", patch[2].Path)
+		require.NoError(t, json.Unmarshal(patch[2].Value, &annotations))
+		assert.Equal(t, "true", annotations[apisLoadTestV1.TransformedAnnotation])
+	})
+
+	transformedAs := func(t *testing.T, wh *Webhook, username string) *admissionV1.AdmissionResponse {
+		loadTest := buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+			lt.Spec.TestData = []byte("already encoded")
+			apisLoadTestV1.SetTransformed(lt)
+		})
+		raw, err := json.Marshal(loadTest)
+		require.NoError(t, err)
+
+		return doAdmissionRequest(t, wh.Mutate, &admissionV1.AdmissionRequest{
+			UID:       "7c4fc5a8-1d6c-4a5c-8a0e-1a3a7b6c0f10",
+			Name:      loadTest.Name,
+			Operation: admissionV1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+			UserInfo:  authenticationV1.UserInfo{Username: username},
+		})
+	}
+
+	t.Run("skips spec transformed by proxy", func(t *testing.T) {
+		resp := transformedAs(t, newTestWebhook(t, false), testProxyUsername)
+
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Patch)
+	})
+
+	t.Run("transforms spec marked as transformed by other users", func(t *testing.T) {
+		resp := transformedAs(t, newTestWebhook(t, false), "kubernetes-admin")
+
+		assert.True(t, resp.Allowed)
+		assert.NotEmpty(t, resp.Patch)
+	})
+
+	t.Run("transforms spec marked as transformed when proxy user is not configured", func(t *testing.T) {
+		wh := newTestWebhook(t, false)
+		wh.proxyUsername = ""
+		resp := transformedAs(t, wh, "")
+
+		assert.True(t, resp.Allowed)
+		assert.NotEmpty(t, resp.Patch)
+	})
+
+	t.Run("skips invalid spec", func(t *testing.T) {
+		wh := newTestWebhook(t, false)
+
+		loadTest := buildLoadTest(func(lt *apisLoadTestV1.LoadTest) {
+			lt.Spec.DistributedPods = nil
+		})
+		resp := doAdmissionReview(t, wh.Mutate, admissionV1.Create, loadTest, nil)
+
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Patch)
+	})
+}
+
+func buildLoadTestV2(modify func(lt *apisLoadTestV2.LoadTest)) *apisLoadTestV2.LoadTest {
+	lt := apisLoadTestV2.ConvertFromV1(buildLoadTest(nil))
+	if modify != nil {
+		modify(lt)
+	}
+	return lt
+}
+
+func TestAdmitV2(t *testing.T) {
+	threads := int32(10)
+
+	t.Run("mutate defaults v2 spec", func(t *testing.T) {
+		wh := newTestWebhook(t, false)
+
+		loadTest := buildLoadTestV2(func(lt *apisLoadTestV2.LoadTest) {
+			lt.Spec.JMeter = &apisLoadTestV2.JMeterSpec{Threads: &threads}
+		})
+		resp := doAdmissionReviewV2(t, wh.Mutate, loadTest)
+		assert.True(t, resp.Allowed)
+
+		var patch []struct {
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Patch, &patch))
+		require.NotEmpty(t, patch)
+
+		var spec apisLoadTestV2.LoadTestSpec
+		assert.Equal(t, "/spec", patch[0].Path)
+		require.NoError(t, json.Unmarshal(patch[0].Value, &spec))
+		assert.Equal(t, "hellofresh/kangal-jmeter-master", spec.MasterConfig.Image)
+		assert.Equal(t, &apisLoadTestV2.JMeterSpec{Threads: &threads}, spec.JMeter)
+		assert.Empty(t, spec.EnvVars)
+	})
+
+	t.Run("validate allows v2 spec", func(t *testing.T) {
+		wh := newTestWebhook(t, false)
+
+		loadTest := buildLoadTestV2(func(lt *apisLoadTestV2.LoadTest) {
+			lt.Spec.JMeter = &apisLoadTestV2.JMeterSpec{Threads: &threads}
+		})
+		resp := doAdmissionReviewV2(t, wh.Validate, loadTest)
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("validate denies backend spec of another type", func(t *testing.T) {
+		wh := newTestWebhook(t, false)
+
+		loadTest := buildLoadTestV2(func(lt *apisLoadTestV2.LoadTest) {
+			lt.Spec.K6 = &apisLoadTestV2.K6Spec{VUs: &threads}
+		})
+
+		resp := doAdmissionReviewV2(t, wh.Mutate, loadTest)
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Patch)
+
+		resp = doAdmissionReviewV2(t, wh.Validate, loadTest)
+		assert.False(t, resp.Allowed)
+		require.NotNil(t, resp.Result)
+		assert.Contains(t, resp.Result.Message, "spec.k6: Forbidden: allowed only for K6 load tests")
+	})
+}